@@ -3,7 +3,9 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 
+	"github.com/atian25/projj-go/internal/output"
 	"github.com/atian25/projj-go/pkg/projj"
 	"github.com/urfave/cli/v3"
 )
@@ -39,10 +41,18 @@ func addAction(ctx context.Context, cmd *cli.Command) error {
 	
 	repoURL := cmd.Args().Get(0)
 	
-	client, err := projj.New()
+	client, err := newClient(cmd)
 	if err != nil {
-		return fmt.Errorf("创建客户端失败: %w", err)
+		return err
 	}
 	
-	return client.Add(repoURL)
+	repo, err := client.Add(repoURL)
+	if err != nil {
+		return err
+	}
+	
+	if f := outputFormat(cmd); f.Structured() {
+		return output.Object(os.Stdout, f, projj.RepoColumns, projj.NewRepoRecord(*repo))
+	}
+	return nil
 }
\ No newline at end of file
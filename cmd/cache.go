@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/config"
+	"github.com/urfave/cli/v3"
+)
+
+// CacheCommand 返回 cache 命令
+func CacheCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "管理仓库缓存文件",
+		Commands: []*cli.Command{
+			{
+				Name:  "migrate",
+				Usage: "将缓存文件升级到当前格式版本",
+				Description: `识别缓存文件的格式版本（包括原版 projj 格式），依次执行迁移并写回。
+
+迁移前的文件会保留在缓存备份中。`,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "只显示需要执行的迁移，不修改文件",
+					},
+				},
+				Action: cacheMigrateAction,
+			},
+		},
+	}
+}
+
+func cacheMigrateAction(ctx context.Context, cmd *cli.Command) error {
+	dryRun := cmd.Bool("dry-run")
+	
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("加载配置失败: %w", err)
+	}
+	if !config.ValidCacheFormat(cfg.CacheFormat) {
+		return fmt.Errorf("未知的缓存格式: %s", cfg.CacheFormat)
+	}
+	
+	result, err := cache.Migrate(cfg.GetCacheFormat(), dryRun)
+	if err != nil {
+		return fmt.Errorf("迁移缓存失败: %w", err)
+	}
+	
+	if len(result.Steps) == 0 {
+		fmt.Printf("缓存文件已是最新版本 v%d: %s\n", result.To, result.Path)
+		return nil
+	}
+	
+	fmt.Printf("缓存文件: %s (v%d -> v%d，%d 个仓库)\n", result.Path, result.From, result.To, result.Repositories)
+	for _, step := range result.Steps {
+		fmt.Printf("  %s\n", step)
+	}
+	
+	if dryRun {
+		fmt.Println("预览模式，未修改缓存文件")
+		return nil
+	}
+	fmt.Println("迁移完成")
+	return nil
+}
\ No newline at end of file
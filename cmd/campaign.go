@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/atian25/projj-go/internal/campaign"
+	"github.com/atian25/projj-go/internal/fsutil"
+	"github.com/atian25/projj-go/internal/output"
+	"github.com/atian25/projj-go/pkg/projj"
+	"github.com/urfave/cli/v3"
+)
+
+// CampaignCommand 返回 campaign 命令
+func CampaignCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "campaign",
+		Usage: "在多个仓库中批量修改、提交和推送",
+		Description: `活动（campaign）在选中的每个仓库中依次执行：
+  1. 从当前分支创建活动分支（有未提交修改或处于分离 HEAD 的仓库会失败）
+  2. 在仓库目录中通过 shell 执行脚本
+  3. 提交脚本产生的所有修改，没有修改的仓库记为无修改
+  4. 使用 --push 时推送活动分支并设置上游
+
+每个仓库的进度保存在配置目录的 campaigns 下，失败或中断后可以用 resume 继续，
+用 rollback 切换回原来的分支并删除活动分支。
+
+示例:
+  projj campaign start bump-lib owner:infra --script 'go get example.com/lib@v1.2.0 && go mod tidy' -m "升级 lib 到 v1.2.0"
+  projj campaign show bump-lib
+  projj campaign resume bump-lib --push
+  projj campaign rollback bump-lib`,
+		Commands: []*cli.Command{
+			{
+				Name:      "start",
+				Usage:     "创建活动并在选中的仓库中执行",
+				ArgsUsage: "<name> [query]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "script",
+						Aliases:  []string{"s"},
+						Usage:    "在每个仓库目录中通过 shell 执行的脚本，当前目录下的脚本文件会转换为绝对路径",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "message",
+						Aliases:  []string{"m"},
+						Usage:    "提交信息",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "branch",
+						Usage: "活动分支名，默认与活动名称相同",
+					},
+					&cli.BoolFlag{
+						Name:  "push",
+						Usage: "提交后推送活动分支",
+					},
+					&cli.IntFlag{
+						Name:  "workers",
+						Usage: "并发数，默认使用配置中的 scan_workers 或 CPU 数",
+					},
+					tagFlag(),
+				},
+				Action: campaignStartAction,
+			},
+			{
+				Name:      "resume",
+				Usage:     "继续执行活动中失败或未完成的仓库",
+				ArgsUsage: "<name>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "push",
+						Usage: "推送已提交的活动分支",
+					},
+					&cli.IntFlag{
+						Name:  "workers",
+						Usage: "并发数，默认使用配置中的 scan_workers 或 CPU 数",
+					},
+				},
+				Action: campaignResumeAction,
+			},
+			{
+				Name:   "list",
+				Usage:  "列出所有活动",
+				Action: campaignListAction,
+			},
+			{
+				Name:      "show",
+				Usage:     "查看活动中每个仓库的进度",
+				ArgsUsage: "<name>",
+				Action:    campaignShowAction,
+			},
+			{
+				Name:      "rollback",
+				Usage:     "切换回原来的分支并删除活动分支",
+				ArgsUsage: "<name>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "丢弃仓库中未提交的修改和未跟踪的文件",
+					},
+					&cli.BoolFlag{
+						Name:  "remote",
+						Usage: "同时删除已推送的远程分支",
+					},
+				},
+				Action: campaignRollbackAction,
+			},
+			{
+				Name:      "delete",
+				Usage:     "删除活动的记录，不修改仓库",
+				ArgsUsage: "<name>",
+				Action:    campaignDeleteAction,
+			},
+		},
+	}
+}
+
+// campaignName 返回命令的第一个参数作为活动名称
+func campaignName(cmd *cli.Command) (string, error) {
+	if cmd.Args().Len() == 0 {
+		return "", fmt.Errorf("请提供活动名称")
+	}
+	return cmd.Args().First(), nil
+}
+
+func campaignStartAction(ctx context.Context, cmd *cli.Command) error {
+	name, err := campaignName(cmd)
+	if err != nil {
+		return err
+	}
+	
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	
+	filter, err := filterQuery(cmd, cmd.Args().Tail())
+	if err != nil {
+		return err
+	}
+	
+	// 脚本在仓库目录中执行，当前目录下的脚本文件需要使用绝对路径
+	script := cmd.String("script")
+	if !filepath.IsAbs(script) && fsutil.Exists(script) {
+		if abs, err := filepath.Abs(script); err == nil {
+			script = abs
+		}
+	}
+	
+	camp, err := client.NewCampaign(name, cmd.String("branch"), script, cmd.String("message"), filter)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("已创建活动 %s: 分支 %s，%d 个仓库\n\n", camp.Name, camp.Branch, len(camp.Repos))
+	
+	return runCampaign(ctx, cmd, client, camp)
+}
+
+func campaignResumeAction(ctx context.Context, cmd *cli.Command) error {
+	name, err := campaignName(cmd)
+	if err != nil {
+		return err
+	}
+	
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	
+	camp, err := campaign.Load(name)
+	if err != nil {
+		return err
+	}
+	
+	return runCampaign(ctx, cmd, client, camp)
+}
+
+// runCampaign 执行活动并输出汇总，有仓库失败时返回错误
+func runCampaign(ctx context.Context, cmd *cli.Command, client *projj.Client, camp *campaign.Campaign) error {
+	err := client.RunCampaign(ctx, camp, projj.CampaignOptions{
+		Workers: cmd.Int("workers"),
+		Push:    cmd.Bool("push"),
+	})
+	if err != nil {
+		return err
+	}
+	
+	fmt.Println()
+	fmt.Println(campaignSummary(camp))
+	if _, failed := camp.Counts(); failed > 0 {
+		return fmt.Errorf("%d 个仓库失败，处理后使用 projj campaign resume %s 继续", failed, camp.Name)
+	}
+	return nil
+}
+
+// campaignSummary 返回活动各状态仓库数量的汇总
+func campaignSummary(camp *campaign.Campaign) string {
+	counts, failed := camp.Counts()
+	parts := []string{fmt.Sprintf("共 %d 个仓库", len(camp.Repos))}
+	for _, state := range []campaign.State{campaign.Pending, campaign.Branched, campaign.Committed, campaign.Unchanged, campaign.Pushed, campaign.RolledBack} {
+		if counts[state] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", state.Label(), counts[state]))
+		}
+	}
+	if failed > 0 {
+		parts = append(parts, fmt.Sprintf("失败 %d", failed))
+	}
+	return strings.Join(parts, "，")
+}
+
+func campaignListAction(ctx context.Context, cmd *cli.Command) error {
+	campaigns, err := campaign.List()
+	if err != nil {
+		return err
+	}
+	
+	if f := outputFormat(cmd); f.Structured() {
+		return output.List(os.Stdout, f, []string{"name", "branch", "script", "message", "created_at"}, campaigns)
+	}
+	
+	if len(campaigns) == 0 {
+		fmt.Println("没有活动")
+		return nil
+	}
+	for _, camp := range campaigns {
+		fmt.Printf("%s  分支 %s  创建于 %s\n  %s\n", camp.Name, camp.Branch, camp.CreatedAt.Format("2006-01-02 15:04"), campaignSummary(camp))
+	}
+	return nil
+}
+
+func campaignShowAction(ctx context.Context, cmd *cli.Command) error {
+	name, err := campaignName(cmd)
+	if err != nil {
+		return err
+	}
+	
+	camp, err := campaign.Load(name)
+	if err != nil {
+		return err
+	}
+	
+	if f := outputFormat(cmd); f.Structured() {
+		return output.Object(os.Stdout, f, []string{"name", "branch", "script", "message", "created_at"}, camp)
+	}
+	
+	fmt.Printf("活动: %s\n分支: %s\n脚本: %s\n提交信息: %s\n创建时间: %s\n\n",
+		camp.Name, camp.Branch, camp.Script, camp.Message, camp.CreatedAt.Format("2006-01-02 15:04:05"))
+	for _, repo := range camp.Repos {
+		line := fmt.Sprintf("  %s (%s): %s", repo.Name, repo.Path, repo.State.Label())
+		if repo.Commit != "" {
+			line += " " + repo.Commit
+		}
+		if repo.Failed() {
+			line += "，失败: " + repo.Error
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+	fmt.Println(campaignSummary(camp))
+	return nil
+}
+
+func campaignRollbackAction(ctx context.Context, cmd *cli.Command) error {
+	name, err := campaignName(cmd)
+	if err != nil {
+		return err
+	}
+	
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	
+	camp, err := campaign.Load(name)
+	if err != nil {
+		return err
+	}
+	
+	err = client.RollbackCampaign(ctx, camp, projj.RollbackOptions{
+		Force:  cmd.Bool("force"),
+		Remote: cmd.Bool("remote"),
+	})
+	if err != nil {
+		return err
+	}
+	
+	fmt.Println()
+	fmt.Println(campaignSummary(camp))
+	remotes := 0
+	for _, repo := range camp.Repos {
+		if repo.RemoteBranch {
+			remotes++
+		}
+	}
+	if remotes > 0 {
+		fmt.Printf("%d 个仓库的远程分支 %s 已保留，可以使用 projj campaign rollback %s --remote 删除\n", remotes, camp.Branch, camp.Name)
+	}
+	if _, failed := camp.Counts(); failed > 0 {
+		return fmt.Errorf("%d 个仓库回滚失败", failed)
+	}
+	return nil
+}
+
+func campaignDeleteAction(ctx context.Context, cmd *cli.Command) error {
+	name, err := campaignName(cmd)
+	if err != nil {
+		return err
+	}
+	
+	if err := campaign.Delete(name); err != nil {
+		return err
+	}
+	fmt.Printf("已删除活动: %s\n", name)
+	return nil
+}
\ No newline at end of file
@@ -3,9 +3,13 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/atian25/projj-go/internal/config"
+	"github.com/atian25/projj-go/internal/output"
 	"github.com/urfave/cli/v3"
 )
 
@@ -74,7 +78,23 @@ func configGetAction(ctx context.Context, cmd *cli.Command) error {
 		fmt.Printf("%s\n", cfg.Base)
 	case "change_directory":
 		fmt.Printf("%t\n", cfg.ChangeDirectory)
+	case "scan_workers":
+		fmt.Printf("%d\n", cfg.ScanWorkers)
+	case "scan_ignore":
+		fmt.Printf("%s\n", strings.Join(cfg.ScanIgnore, ","))
+	case "cache_format":
+		fmt.Printf("%s\n", cfg.GetCacheFormat())
+	case "cache_backend":
+		fmt.Printf("%s\n", cfg.GetCacheBackend())
 	default:
+		if name, ok := strings.CutPrefix(key, config.TemplatePrefix); ok {
+			text, found := cfg.Templates[name]
+			if !found {
+				return fmt.Errorf("未定义模板: %s", name)
+			}
+			fmt.Printf("%s\n", text)
+			return nil
+		}
 		return fmt.Errorf("未知的配置键: %s", key)
 	}
 	
@@ -85,21 +105,40 @@ func configSetAction(ctx context.Context, cmd *cli.Command) error {
 	key := cmd.String("key")
 	value := cmd.String("value")
 	
-	cfg, err := config.Load()
+	// 在文件锁内修改配置，避免并发修改相互覆盖
+	_, err := config.Update(func(cfg *config.Config) error {
+		switch key {
+		case "base":
+			cfg.Base = value
+		case "change_directory":
+			cfg.ChangeDirectory = strings.ToLower(value) == "true"
+		case "scan_workers":
+			workers, err := strconv.Atoi(value)
+			if err != nil || workers < 0 {
+				return fmt.Errorf("scan_workers 必须是非负整数: %s", value)
+			}
+			cfg.ScanWorkers = workers
+		case "scan_ignore":
+			cfg.ScanIgnore = splitList(value)
+		case "cache_format":
+			if !config.ValidCacheFormat(value) {
+				return fmt.Errorf("cache_format 必须是 %s、%s 或 %s: %s", config.CacheFormatGo, config.CacheFormatNode, config.CacheFormatBoth, value)
+			}
+			cfg.CacheFormat = value
+		case "cache_backend":
+			if !config.ValidCacheBackend(value) {
+				return fmt.Errorf("cache_backend 必须是 %s 或 %s: %s", config.CacheBackendJSON, config.CacheBackendBolt, value)
+			}
+			cfg.CacheBackend = value
+		default:
+			if name, ok := strings.CutPrefix(key, config.TemplatePrefix); ok {
+				return cfg.SetTemplate(name, value)
+			}
+			return fmt.Errorf("未知的配置键: %s", key)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("加载配置失败: %w", err)
-	}
-	
-	switch key {
-	case "base":
-		cfg.Base = value
-	case "change_directory":
-		cfg.ChangeDirectory = strings.ToLower(value) == "true"
-	default:
-		return fmt.Errorf("未知的配置键: %s", key)
-	}
-	
-	if err := cfg.Save(); err != nil {
 		return fmt.Errorf("保存配置失败: %w", err)
 	}
 	
@@ -113,9 +152,25 @@ func configListAction(ctx context.Context, cmd *cli.Command) error {
 		return fmt.Errorf("加载配置失败: %w", err)
 	}
 	
+	if f := outputFormat(cmd); f.Structured() {
+		return output.List(os.Stdout, f, []string{"key", "value"}, configEntries(cfg))
+	}
+	
 	fmt.Println("当前配置:")
 	fmt.Printf("  base = %s\n", cfg.Base)
 	fmt.Printf("  change_directory = %t\n", cfg.ChangeDirectory)
+	if cfg.ScanWorkers > 0 {
+		fmt.Printf("  scan_workers = %d\n", cfg.ScanWorkers)
+	}
+	if len(cfg.ScanIgnore) > 0 {
+		fmt.Printf("  scan_ignore = %s\n", strings.Join(cfg.ScanIgnore, ","))
+	}
+	if cfg.CacheFormat != "" {
+		fmt.Printf("  cache_format = %s\n", cfg.CacheFormat)
+	}
+	if cfg.CacheBackend != "" {
+		fmt.Printf("  cache_backend = %s\n", cfg.CacheBackend)
+	}
 	
 	if len(cfg.Hooks) > 0 {
 		fmt.Println("  hooks:")
@@ -124,6 +179,13 @@ func configListAction(ctx context.Context, cmd *cli.Command) error {
 		}
 	}
 	
+	if len(cfg.Templates) > 0 {
+		fmt.Println("  templates:")
+		for name, text := range cfg.Templates {
+			fmt.Printf("    %s = %s\n", name, text)
+		}
+	}
+	
 	if len(cfg.PostAdd) > 0 {
 		fmt.Println("  postadd:")
 		for platform, userInfo := range cfg.PostAdd {
@@ -141,4 +203,53 @@ func configPathAction(ctx context.Context, cmd *cli.Command) error {
 	configPath := config.GetConfigPath()
 	fmt.Printf("配置文件路径: %s\n", configPath)
 	return nil
+}
+
+// configEntry 表示机器可读格式下的一项配置
+type configEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// configEntries 将配置展开为键值对，嵌套的配置使用点号连接键名，如 hooks.postadd、postadd.github.com.user.email
+func configEntries(cfg *config.Config) []configEntry {
+	entries := []configEntry{
+		{"base", cfg.Base},
+		{"change_directory", strconv.FormatBool(cfg.ChangeDirectory)},
+		{"scan_workers", strconv.Itoa(cfg.ScanWorkers)},
+		{"scan_ignore", strings.Join(cfg.ScanIgnore, ",")},
+		{"cache_format", cfg.GetCacheFormat()},
+		{"cache_backend", cfg.GetCacheBackend()},
+	}
+	
+	var nested []configEntry
+	for name, value := range cfg.Alias {
+		nested = append(nested, configEntry{"alias." + name, value})
+	}
+	for name, command := range cfg.Hooks {
+		nested = append(nested, configEntry{"hooks." + name, command})
+	}
+	for name, text := range cfg.Templates {
+		nested = append(nested, configEntry{config.TemplatePrefix + name, text})
+	}
+	for platform, values := range cfg.PostAdd {
+		for key, value := range values {
+			nested = append(nested, configEntry{"postadd." + platform + "." + key, value})
+		}
+	}
+	sort.Slice(nested, func(i, j int) bool {
+		return nested[i].Key < nested[j].Key
+	})
+	return append(entries, nested...)
+}
+
+// splitList 将逗号分隔的字符串拆分为列表，忽略空项
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
\ No newline at end of file
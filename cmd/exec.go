@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/atian25/projj-go/pkg/projj"
+	"github.com/urfave/cli/v3"
+)
+
+// ExecCommand 返回 exec 命令
+func ExecCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "exec",
+		Usage:     "在多个仓库中执行任意命令",
+		Action:    execAction,
+		ArgsUsage: "[query] -- <command...>",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:    "workers",
+				Aliases: []string{"j"},
+				Usage:   "并发数，默认使用配置中的 scan_workers 或 CPU 数",
+			},
+			&cli.BoolFlag{
+				Name:  "group",
+				Usage: "每个仓库执行完后整体输出，而不是逐行加上仓库名前缀",
+			},
+			&cli.BoolFlag{
+				Name:  "fail-fast",
+				Usage: "有仓库失败后不再启动新的仓库，并终止正在执行的命令",
+			},
+			tagFlag(),
+		},
+		Description: `在满足查询的仓库目录中并发执行命令，-- 之前为查询条件，之后为要执行的命令。
+没有 -- 时所有参数都是命令，在所有仓库中执行；查询中取反请用 NOT 或 !。
+
+只有一个参数时通过系统 shell 执行，可以使用管道和 &&；多个参数时直接执行。
+默认逐行输出并加上 [owner/name] 前缀，--group 时每个仓库执行完后整体输出。
+命令的环境变量中包含 PROJJ_REPO_NAME、PROJJ_REPO_OWNER、PROJJ_REPO_HOST、
+PROJJ_REPO_PATH 和 PROJJ_REPO_URL。
+
+默认单个仓库失败不会中断执行；有仓库失败时以其中最大的退出码退出。
+
+示例:
+  projj exec -- git status -s
+  projj exec owner:infra -- 'test ! -f go.mod || go mod tidy'
+  projj exec --tag work -j 4 --group -- git log --oneline -3
+  projj exec --fail-fast -- make test`,
+	}
+}
+
+func execAction(ctx context.Context, cmd *cli.Command) error {
+	query, command := splitCommand(cmd.Args().Slice(), os.Args)
+	if len(command) == 0 {
+		return fmt.Errorf("请在 -- 之后提供要执行的命令")
+	}
+	
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	
+	filter, err := filterQuery(cmd, query)
+	if err != nil {
+		return err
+	}
+	
+	results, err := client.Exec(ctx, command, projj.ExecOptions{
+		Filter:   filter,
+		Workers:  cmd.Int("workers"),
+		Group:    cmd.Bool("group"),
+		FailFast: cmd.Bool("fail-fast"),
+	})
+	if err != nil {
+		return err
+	}
+	
+	var succeeded, skipped, code int
+	var failed []projj.ExecResult
+	for _, result := range results {
+		switch {
+		case result.Skipped:
+			skipped++
+		case result.Err != nil:
+			failed = append(failed, result)
+			code = max(code, result.ExitCode)
+		default:
+			succeeded++
+		}
+	}
+	
+	fmt.Fprintf(os.Stderr, "\n执行完成: 成功 %d 个，失败 %d 个", succeeded, len(failed))
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "，未执行 %d 个", skipped)
+	}
+	fmt.Fprintln(os.Stderr, "仓库")
+	for _, result := range failed {
+		fmt.Fprintf(os.Stderr, "  失败: %s (%s): 退出码 %d\n", result.Repo.Name, result.Repo.Path, result.ExitCode)
+	}
+	
+	if code > 0 {
+		// 失败的原因已在上面输出，只设置退出码
+		return cli.Exit("", code)
+	}
+	return nil
+}
+
+// splitCommand 按 -- 分开查询条件和要执行的命令；解析参数时 -- 会被去掉，
+// 因此根据原始参数中第一个 -- 之后的参数个数切分，没有 -- 时所有参数都是命令
+func splitCommand(args, raw []string) (query, command []string) {
+	for i, arg := range raw {
+		if arg != "--" {
+			continue
+		}
+		if n := len(raw) - i - 1; n <= len(args) {
+			return args[:len(args)-n], args[len(args)-n:]
+		}
+		break
+	}
+	return nil, args
+}
\ No newline at end of file
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/atian25/projj-go/internal/output"
+	"github.com/atian25/projj-go/internal/termutil"
+	"github.com/atian25/projj-go/pkg/projj"
+	"github.com/urfave/cli/v3"
+)
+
+// FetchCommand 返回 fetch 命令
+func FetchCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "fetch",
+		Usage:     "并发获取多个仓库的远程更新",
+		Action:    fetchAction,
+		ArgsUsage: "[query]",
+		Flags:     updateFlags(),
+		Description: `并发地在仓库中执行 git fetch --prune，提供查询或 --tag 时只处理满足条件的仓库。
+
+每个仓库的 git 输出单独捕获，不会交错显示；结束后输出已更新、已是最新和失败的仓库汇总。
+
+示例:
+  projj fetch
+  projj fetch --tag work --timeout 30s
+  projj fetch host:gitlab.com --verbose`,
+	}
+}
+
+// updateFlags 返回 fetch 和 pull 共用的标志
+func updateFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:  "workers",
+			Usage: "并发数，默认使用配置中的 scan_workers 或 CPU 数",
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "单个仓库的超时时间，为 0 时不限制",
+			Value: 2 * time.Minute,
+		},
+		&cli.BoolFlag{
+			Name:  "verbose",
+			Usage: "同时列出已是最新的仓库，并显示每个仓库的 git 输出",
+		},
+		tagFlag(),
+	}
+}
+
+func fetchAction(ctx context.Context, cmd *cli.Command) error {
+	return runUpdate(ctx, cmd, "获取", (*projj.Client).Fetch, projj.UpdateOptions{})
+}
+
+// runUpdate 执行批量 fetch 或 pull 并输出结果，有仓库失败时返回错误
+func runUpdate(ctx context.Context, cmd *cli.Command, verb string,
+	run func(*projj.Client, context.Context, projj.UpdateOptions) ([]projj.UpdateResult, error), opts projj.UpdateOptions) error {
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	
+	opts.Filter, err = filterQuery(cmd, cmd.Args().Slice())
+	if err != nil {
+		return err
+	}
+	opts.Workers = cmd.Int("workers")
+	opts.Timeout = cmd.Duration("timeout")
+	
+	results, err := run(client, ctx, opts)
+	if err != nil {
+		return err
+	}
+	
+	failed := 0
+	for _, result := range results {
+		if result.State == projj.UpdateFailed {
+			failed++
+		}
+	}
+	
+	if f := outputFormat(cmd); f.Structured() {
+		records := make([]projj.UpdateRecord, len(results))
+		for i, result := range results {
+			records[i] = projj.NewUpdateRecord(result)
+		}
+		if err := output.List(os.Stdout, f, projj.UpdateColumns, records); err != nil {
+			return err
+		}
+		if failed > 0 {
+			// 失败已记录在输出中，只设置退出码
+			return cli.Exit("", 1)
+		}
+		return nil
+	}
+	
+	printUpdates(results, client.GetConfig().GetBasePath(), cmd.Bool("verbose"))
+	if failed > 0 {
+		return fmt.Errorf("%d 个仓库%s更新失败", failed, verb)
+	}
+	return nil
+}
+
+// updateLabels 结果状态在文字输出中的名称
+var updateLabels = map[projj.UpdateState]string{
+	projj.UpdateUpdated:  "已更新",
+	projj.UpdateUpToDate: "已是最新",
+	projj.UpdateSkipped:  "已跳过",
+	projj.UpdateFailed:   "失败",
+}
+
+// printUpdates 按列输出 fetch 或 pull 的结果和汇总，verbose 为 false 时省略已是最新的仓库
+func printUpdates(results []projj.UpdateResult, base string, verbose bool) {
+	counts := map[projj.UpdateState]int{}
+	var shown []projj.UpdateResult
+	for _, result := range results {
+		counts[result.State]++
+		if verbose || result.State != projj.UpdateUpToDate {
+			shown = append(shown, result)
+		}
+	}
+	
+	names := make([]string, len(shown))
+	nameWidth, labelWidth := 0, 0
+	for i, result := range shown {
+		names[i] = output.RelPath(base, result.Repo.Path)
+		nameWidth = max(nameWidth, termutil.Width(names[i]))
+		labelWidth = max(labelWidth, termutil.Width(updateLabels[result.State]))
+	}
+	
+	for i, result := range shown {
+		name := names[i] + strings.Repeat(" ", nameWidth-termutil.Width(names[i]))
+		label := updateLabels[result.State]
+		line := name + "  " + label
+		if result.Reason != "" {
+			line += strings.Repeat(" ", labelWidth-termutil.Width(label)) + "  " + result.Reason
+		}
+		fmt.Println(line)
+		if verbose && result.Output != "" {
+			for _, l := range strings.Split(result.Output, "\n") {
+				fmt.Println("    " + l)
+			}
+		}
+	}
+	
+	if len(shown) > 0 {
+		fmt.Println()
+	}
+	fmt.Printf("共 %d 个仓库: %d 个已更新，%d 个已是最新，%d 个已跳过，%d 个失败\n", len(results),
+		counts[projj.UpdateUpdated], counts[projj.UpdateUpToDate], counts[projj.UpdateSkipped], counts[projj.UpdateFailed])
+}
\ No newline at end of file
@@ -3,7 +3,11 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/output"
+	"github.com/atian25/projj-go/internal/picker"
 	"github.com/atian25/projj-go/pkg/projj"
 	"github.com/urfave/cli/v3"
 )
@@ -27,37 +31,94 @@ func FindCommand() *cli.Command {
 				Usage: "只显示路径",
 				Aliases: []string{"p"},
 			},
+			tagFlag(),
+			fzfFlag(),
+			templateFlag(),
 		},
 		Description: `查找管理的仓库。
 
 如果不提供查询条件，将列出所有仓库。
-查询支持仓库名、路径和 URL 的模糊匹配。
+查询支持仓库名、路径和 URL 的模糊匹配，结果按匹配程度排序：
+owner/name 完全匹配 > 名称完全匹配 > 名称前缀 > 单词开头 > 包含 > 字符依次出现。
+
+匹配多个仓库且在终端中运行时，会打开选择器（方向键选择，输入继续过滤，Enter 确认，Esc 取消），
+选中后只输出路径；加 --fzf 使用外部的 fzf，输出被重定向时仍列出所有匹配的仓库。
+
+`+queryHelp+`
+
+`+templateHelp+`
 
 示例:
   projj find                # 列出所有仓库
   projj find golang         # 查找包含 "golang" 的仓库
   projj find golang/go      # 精确查找
   projj find --details go   # 显示详细信息
-  projj find --path-only go # 只显示路径`,
+  projj find --path-only go # 只显示路径
+  projj find host:gitlab.com owner:infra name:~^api-
+  projj find --tag infra api  # 在带有 infra 标签的仓库中查找
+  projj find api --template '{{.Path}}'`,
 	}
 }
 
+// fzfFlag 返回使用 fzf 选择仓库的 --fzf 标志
+func fzfFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:  "fzf",
+		Usage: "匹配多个仓库时使用 fzf 选择",
+	}
+}
+
+// repoChooser 返回匹配多个仓库时的选择方式：指定 --fzf 时使用 fzf，在终端中使用内置选择器，否则返回 nil 保持可供脚本解析的输出
+func repoChooser(cmd *cli.Command) projj.Chooser {
+	useFZF := cmd.Bool("fzf")
+	if !useFZF && !picker.Interactive() {
+		return nil
+	}
+	return func(repos []cache.Repository) (*cache.Repository, error) {
+		return projj.PickRepo(repos, useFZF)
+	}
+}
+
+// queryHelp 字段查询的语法说明，find 和 list 共用
+const queryHelp = `字段查询:
+  host:gitlab.com     主机名，owner、name 同理，默认完全匹配
+  path:infra          路径包含，url 同理
+  name:~^api-         ~ 开头为正则表达式
+  name:api-*          含 * 或 ? 时为通配符
+  tag:payments        带有标签，desc:鉴权 为描述包含
+  dirty:true          工作区有未提交的修改（读取实时 git 状态）
+  NOT owner:infra     取反，也可写作 !owner:infra 或在 -- 之后写 -owner:infra
+  a b / a OR b        空格表示与，OR 或 | 表示或，可用括号分组`
+
 func findAction(ctx context.Context, cmd *cli.Command) error {
-	client, err := projj.New()
+	client, err := newClient(cmd)
 	if err != nil {
-		return fmt.Errorf("创建客户端失败: %w", err)
+		return err
 	}
 	
-	var query string
-	if cmd.Args().Len() > 0 {
-		query = cmd.Args().Get(0)
+	query, err := filterQuery(cmd, cmd.Args().Slice())
+	if err != nil {
+		return err
 	}
 	
-	repos, err := client.Find(query)
+	matches, err := client.Search(query)
 	if err != nil {
 		return fmt.Errorf("查找仓库失败: %w", err)
 	}
 	
+	repos := make([]cache.Repository, 0, len(matches))
+	for _, m := range matches {
+		repos = append(repos, m.Repo)
+	}
+	
+	// 按模板或机器可读格式输出时按匹配程度输出所有结果，不交互选择也不切换目录
+	if cmd.IsSet("template") {
+		return writeTemplate(client, cmd, templateRepos(repos))
+	}
+	if f := outputFormat(cmd); f.Structured() {
+		return output.List(os.Stdout, f, projj.RepoColumns, projj.NewRepoRecords(repos))
+	}
+	
 	if len(repos) == 0 {
 		if query == "" {
 			fmt.Println("未找到任何仓库，请先使用 'projj add' 添加仓库")
@@ -67,6 +128,20 @@ func findAction(ctx context.Context, cmd *cli.Command) error {
 		return nil
 	}
 	
+	// 没有明显最匹配的仓库时交互选择，选中后只输出路径
+	winner, ok := cache.ClearWinner(matches)
+	if !ok && query != "" && !cmd.Bool("path-only") && !cmd.Bool("details") {
+		if chooser := repoChooser(cmd); chooser != nil {
+			repo, err := chooser(repos)
+			if err != nil {
+				return err
+			}
+			fmt.Println(repo.Path)
+			enterRepo(client, repo.Path)
+			return nil
+		}
+	}
+	
 	// 根据标志决定输出格式
 	if cmd.Bool("path-only") {
 		for _, repo := range repos {
@@ -78,11 +153,18 @@ func findAction(ctx context.Context, cmd *cli.Command) error {
 		fmt.Println(output)
 	}
 	
-	// 如果启用了 change_directory 且只找到一个仓库，输出切换目录信息
-	config := client.GetConfig()
-	if config.ChangeDirectory && len(repos) == 1 && query != "" {
-		fmt.Printf("PROJJ_CHANGE_DIRECTORY=%s\n", repos[0].Path)
+	// 有明显最匹配的仓库时进入该仓库
+	if ok && query != "" {
+		enterRepo(client, winner.Repo.Path)
 	}
 	
 	return nil
+}
+
+// enterRepo 记录对仓库的访问，启用了 change_directory 时输出切换目录信息
+func enterRepo(client *projj.Client, path string) {
+	client.Visit(path)
+	if client.GetConfig().ChangeDirectory {
+		fmt.Printf("PROJJ_CHANGE_DIRECTORY=%s\n", path)
+	}
 }
\ No newline at end of file
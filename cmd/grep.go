@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/atian25/projj-go/internal/git"
+	"github.com/atian25/projj-go/internal/output"
+	"github.com/atian25/projj-go/internal/termutil"
+	"github.com/atian25/projj-go/pkg/projj"
+	"github.com/urfave/cli/v3"
+)
+
+// grepLineWidth 文字输出中匹配行的最大显示宽度，避免压缩后的文件刷屏
+const grepLineWidth = 200
+
+// GrepCommand 返回 grep 命令
+func GrepCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "grep",
+		Usage:     "在多个仓库中搜索代码",
+		Action:    grepAction,
+		ArgsUsage: "<pattern> [query]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "ignore-case",
+				Aliases: []string{"i"},
+				Usage:   "忽略大小写",
+			},
+			&cli.BoolFlag{
+				Name:    "fixed-strings",
+				Aliases: []string{"F"},
+				Usage:   "按固定字符串而不是正则表达式匹配",
+			},
+			&cli.BoolFlag{
+				Name:    "word",
+				Aliases: []string{"w"},
+				Usage:   "只匹配完整的单词",
+			},
+			&cli.BoolFlag{
+				Name:    "files-with-matches",
+				Aliases: []string{"l"},
+				Usage:   "只列出包含匹配的文件",
+			},
+			&cli.BoolFlag{
+				Name:  "untracked",
+				Usage: "同时搜索未跟踪但没有被 .gitignore 忽略的文件",
+			},
+			&cli.IntFlag{
+				Name:    "workers",
+				Aliases: []string{"j"},
+				Usage:   "并发数，默认使用配置中的 scan_workers 或 CPU 数",
+			},
+			tagFlag(),
+		},
+		Description: `在满足查询的仓库中并发搜索文本，第一个参数为搜索模式，其余参数为查询条件。
+
+git 仓库使用 git grep 搜索已跟踪的文件，模式为扩展正则表达式（ERE）；
+其他目录遍历文件，遵循各级 .gitignore，模式为 Go 正则表达式。二进制文件总会跳过。
+
+结果按仓库分组，显示相对于仓库根目录的路径和行号。
+与 grep 一致，没有匹配时以退出码 1 退出，有仓库搜索失败时以退出码 2 退出。
+
+示例:
+  projj grep 'TODO|FIXME'
+  projj grep -i -w config owner:infra
+  projj grep -F 'fmt.Println(' --tag work -l
+  projj grep --format json 'example\.com/lib' | jq -r '.[].path'`,
+	}
+}
+
+func grepAction(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() == 0 {
+		return fmt.Errorf("请提供搜索模式")
+	}
+	pattern := cmd.Args().First()
+	
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	
+	filter, err := filterQuery(cmd, cmd.Args().Tail())
+	if err != nil {
+		return err
+	}
+	
+	results, err := client.Grep(ctx, pattern, projj.GrepOptions{
+		GrepOptions: git.GrepOptions{
+			IgnoreCase: cmd.Bool("ignore-case"),
+			Fixed:      cmd.Bool("fixed-strings"),
+			Word:       cmd.Bool("word"),
+			Untracked:  cmd.Bool("untracked"),
+			FilesOnly:  cmd.Bool("files-with-matches"),
+		},
+		Filter:  filter,
+		Workers: cmd.Int("workers"),
+	})
+	if err != nil {
+		return err
+	}
+	
+	var matches, files, repos, failed int
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: 搜索失败: %v\n", result.Repo.Path, result.Err)
+			continue
+		}
+		repos++
+		matches += len(result.Matches)
+		seen := map[string]bool{}
+		for _, match := range result.Matches {
+			if !seen[match.Path] {
+				seen[match.Path] = true
+				files++
+			}
+		}
+	}
+	
+	if f := outputFormat(cmd); f.Structured() {
+		records := []projj.GrepRecord{}
+		for _, result := range results {
+			records = append(records, projj.NewGrepRecords(result)...)
+		}
+		if err := output.List(os.Stdout, f, projj.GrepColumns, records); err != nil {
+			return err
+		}
+		if failed > 0 {
+			// 失败已写到 stderr，只设置退出码
+			return cli.Exit("", 2)
+		}
+	} else if repos > 0 {
+		printGrep(results, client.GetConfig().GetBasePath(), cmd.Bool("files-with-matches"))
+		if cmd.Bool("files-with-matches") {
+			fmt.Printf("\n共 %d 个文件，分布在 %d 个仓库中\n", files, repos)
+		} else {
+			fmt.Printf("\n共 %d 处匹配，分布在 %d 个仓库的 %d 个文件中\n", matches, repos, files)
+		}
+	}
+	
+	if failed > 0 {
+		return cli.Exit(fmt.Sprintf("%d 个仓库搜索失败", failed), 2)
+	}
+	if repos == 0 {
+		// 与 grep 一致，没有匹配时以退出码 1 退出
+		return cli.Exit("", 1)
+	}
+	return nil
+}
+
+// printGrep 按仓库分组输出匹配，仓库显示相对于 base 的路径
+func printGrep(results []projj.GrepResult, base string, filesOnly bool) {
+	first := true
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		if !first {
+			fmt.Println()
+		}
+		first = false
+		
+		fmt.Println(output.RelPath(base, result.Repo.Path))
+		for _, match := range result.Matches {
+			if filesOnly {
+				fmt.Printf("  %s\n", match.Path)
+				continue
+			}
+			text := strings.TrimSpace(strings.ReplaceAll(match.Text, "\t", "  "))
+			fmt.Printf("  %s:%d: %s\n", match.Path, match.Line, termutil.Truncate(text, grepLineWidth))
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atian25/projj-go/pkg/projj"
+	"github.com/urfave/cli/v3"
+)
+
+// IdentityCommand 返回 identity 命令
+func IdentityCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "identity",
+		Usage: "检查和修正仓库的 git 身份配置",
+		Description: `根据配置中的 postadd 检查仓库本地 git 配置。
+
+postadd 按平台配置需要写入仓库的 git 配置，例如:
+  {
+    "postadd": {
+      "gitlab.company.com": {
+        "user.name": "Zhang San",
+        "user.email": "zhangsan@company.com"
+      }
+    }
+  }
+
+projj add 克隆后会自动写入，audit 和 fix 用于检查和修正已有仓库。`,
+		Commands: []*cli.Command{
+			{
+				Name:   "audit",
+				Usage:  "列出 git 配置不一致的仓库",
+				Action: identityAuditAction,
+				Flags:  []cli.Flag{tagFlag()},
+			},
+			{
+				Name:   "fix",
+				Usage:  "修正 git 配置不一致的仓库",
+				Action: identityFixAction,
+				Flags:  []cli.Flag{tagFlag()},
+			},
+		},
+	}
+}
+
+func identityAuditAction(ctx context.Context, cmd *cli.Command) error {
+	client, err := projj.New()
+	if err != nil {
+		return fmt.Errorf("创建客户端失败: %w", err)
+	}
+	
+	filter, err := filterQuery(cmd, nil)
+	if err != nil {
+		return err
+	}
+	
+	issues, err := client.AuditIdentity(filter)
+	if err != nil {
+		return fmt.Errorf("检查 git 身份失败: %w", err)
+	}
+	
+	if len(issues) == 0 {
+		fmt.Println("所有仓库的 git 配置均与 postadd 一致")
+		return nil
+	}
+	
+	printIdentityIssues(issues)
+	return fmt.Errorf("发现 %d 处不一致，可使用 'projj identity fix' 修正", len(issues))
+}
+
+func identityFixAction(ctx context.Context, cmd *cli.Command) error {
+	client, err := projj.New()
+	if err != nil {
+		return fmt.Errorf("创建客户端失败: %w", err)
+	}
+	
+	filter, err := filterQuery(cmd, nil)
+	if err != nil {
+		return err
+	}
+	
+	issues, remaining, err := client.FixIdentity(filter)
+	if err != nil {
+		if len(issues) > 0 {
+			printIdentityIssues(issues)
+			fmt.Printf("\n已修正 %d 处不一致\n\n", len(issues))
+		}
+		fmt.Printf("未修正 %d 处不一致:\n", len(remaining))
+		printIdentityIssues(remaining)
+		return fmt.Errorf("修正 git 身份失败: %w", err)
+	}
+	
+	if len(issues) == 0 {
+		fmt.Println("所有仓库的 git 配置均与 postadd 一致")
+		return nil
+	}
+	
+	printIdentityIssues(issues)
+	fmt.Printf("\n已修正 %d 处不一致\n", len(issues))
+	return nil
+}
+
+func printIdentityIssues(issues []projj.IdentityIssue) {
+	for _, issue := range issues {
+		actual := issue.Actual
+		if actual == "" {
+			actual = "(未设置)"
+		}
+		fmt.Printf("%s (%s)\n  %s: %s -> %s\n", issue.Repo.Name, issue.Repo.Path, issue.Key, actual, issue.Expected)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/output"
+	"github.com/atian25/projj-go/pkg/projj"
+	"github.com/urfave/cli/v3"
+)
+
+// ImportCommand 返回 import 命令
+func ImportCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "导入现有仓库",
+		Action:    importAction,
+		ArgsUsage: "<dir> | --cache [file]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "cache",
+				Usage: "从缓存文件导入，默认读取 ~/.projj/cache.json",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "只显示计划的操作，不做任何修改",
+			},
+			&cli.BoolFlag{
+				Name:  "copy",
+				Usage: "复制仓库到标准目录，保留原目录",
+			},
+			&cli.StringFlag{
+				Name:  "conflict",
+				Usage: "目标路径已存在时的处理方式: skip, rename, fail",
+				Value: projj.ConflictSkip,
+			},
+			&cli.IntFlag{
+				Name:  "workers",
+				Usage: "扫描并发数，默认使用配置中的 scan_workers 或 CPU 数",
+			},
+		},
+		Description: `扫描目录下的 Git 仓库，按 remote origin 移动到标准目录结构并加入管理。
+
+使用 --cache 时从原版 projj 的 cache.json（或指定文件）导入：
+缓存中的目录存在时直接登记，不存在时克隆到标准目录结构，不能与 --copy 或 --conflict 一起使用。
+
+目标路径已存在时:
+  skip    跳过该仓库（默认）
+  rename  在目标路径后追加序号，如 repo-1
+  fail    中止导入
+
+示例:
+  projj import ~/code                  # 移动并导入
+  projj import --dry-run ~/code        # 预览将要执行的操作
+  projj import --copy /mnt/backup      # 复制导入，保留原目录
+  projj import --conflict rename ~/code
+  projj import --cache                 # 从 ~/.projj/cache.json 导入
+  projj import --cache ./cache.json    # 从同事的缓存文件导入`,
+	}
+}
+
+func importAction(ctx context.Context, cmd *cli.Command) error {
+	opts := projj.ImportOptions{
+		DryRun:   cmd.Bool("dry-run"),
+		Copy:     cmd.Bool("copy"),
+		Conflict: cmd.String("conflict"),
+		Workers:  cmd.Int("workers"),
+	}
+	
+	if !cmd.Bool("cache") && cmd.Args().Len() == 0 {
+		return fmt.Errorf("请提供要导入的目录")
+	}
+	if cmd.Bool("cache") && (cmd.IsSet("copy") || cmd.IsSet("conflict")) {
+		return fmt.Errorf("--cache 不能与 --copy 或 --conflict 一起使用")
+	}
+	
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	
+	var result *projj.ImportResult
+	if cmd.Bool("cache") {
+		cachePath := cache.GetCachePath()
+		if cmd.Args().Len() > 0 {
+			cachePath = cmd.Args().Get(0)
+		}
+		result, err = client.ImportCache(cachePath, opts)
+	} else {
+		result, err = client.Import(cmd.Args().Get(0), opts)
+	}
+	if err != nil {
+		return err
+	}
+	
+	if f := outputFormat(cmd); f.Structured() {
+		return output.List(os.Stdout, f, []string{"source", "target", "action", "reason"}, result.Entries)
+	}
+	return nil
+}
\ No newline at end of file
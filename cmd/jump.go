@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/atian25/projj-go/internal/config"
+	"github.com/atian25/projj-go/pkg/projj"
+	"github.com/urfave/cli/v3"
+)
+
+// JumpCommand 返回 jump 命令
+func JumpCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "jump",
+		Usage:     "跳转到最常用的匹配仓库",
+		Action:    jumpAction,
+		Aliases:   []string{"j"},
+		ArgsUsage: "[query]",
+		Flags: []cli.Flag{
+			tagFlag(),
+		},
+		Description: `在匹配查询的仓库中，按访问次数和最近访问时间选出最常用的一个。
+
+通过 find、add 和 jump 定位到的仓库都会记录访问。
+启用 change_directory 并安装 shell 包装函数后会自动切换到该目录。
+
+示例:
+  projj jump          # 跳转到最常用的仓库
+  projj jump api      # 跳转到匹配 "api" 的最常用仓库
+  projj jump -t infra # 跳转到带有 infra 标签的最常用仓库`,
+	}
+}
+
+// RecentCommand 返回 recent 命令
+func RecentCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "recent",
+		Usage:  "列出最常用的仓库",
+		Action: recentAction,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:    "limit",
+				Aliases: []string{"n"},
+				Usage:   "显示数量，0 表示全部",
+				Value:   10,
+			},
+			&cli.BoolFlag{
+				Name:    "path-only",
+				Aliases: []string{"p"},
+				Usage:   "只显示路径",
+			},
+			tagFlag(),
+		},
+	}
+}
+
+func jumpAction(ctx context.Context, cmd *cli.Command) error {
+	client, err := projj.New()
+	if err != nil {
+		return fmt.Errorf("创建客户端失败: %w", err)
+	}
+	
+	query, err := filterQuery(cmd, cmd.Args().Slice())
+	if err != nil {
+		return err
+	}
+	
+	warnVisitsNotSaved(client)
+	repo, err := client.Jump(query)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Println(repo.Path)
+	if client.GetConfig().ChangeDirectory {
+		fmt.Printf("PROJJ_CHANGE_DIRECTORY=%s\n", repo.Path)
+	}
+	return nil
+}
+
+func recentAction(ctx context.Context, cmd *cli.Command) error {
+	client, err := projj.New()
+	if err != nil {
+		return fmt.Errorf("创建客户端失败: %w", err)
+	}
+	
+	filter, err := filterQuery(cmd, nil)
+	if err != nil {
+		return err
+	}
+	
+	warnVisitsNotSaved(client)
+	repos, err := client.Recent(cmd.Int("limit"), filter)
+	if err != nil {
+		return fmt.Errorf("获取访问记录失败: %w", err)
+	}
+	
+	if len(repos) == 0 {
+		fmt.Println("还没有访问记录")
+		return nil
+	}
+	
+	for _, repo := range repos {
+		if cmd.Bool("path-only") {
+			fmt.Println(repo.Path)
+			continue
+		}
+		fmt.Printf("%4d  %s  %s (%s)\n", repo.Visits, repo.LastVisitedAt.Format("2006-01-02 15:04"), repo.Name, repo.Path)
+	}
+	return nil
+}
+
+// warnVisitsNotSaved 缓存不保存访问记录时提示，否则 jump 和 recent 看起来从不记录访问
+func warnVisitsNotSaved(client *projj.Client) {
+	if !client.GetConfig().CacheKeepsMetadata() {
+		fmt.Fprintf(os.Stderr, "警告: cache_format 为 %s 时不保存访问记录，jump 无法按常用程度排序，recent 始终为空；可改用 %s\n",
+			config.CacheFormatNode, config.CacheFormatBoth)
+	}
+}
\ No newline at end of file
@@ -3,7 +3,9 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 
+	"github.com/atian25/projj-go/internal/output"
 	"github.com/atian25/projj-go/pkg/projj"
 	"github.com/urfave/cli/v3"
 )
@@ -13,8 +15,9 @@ func ListCommand() *cli.Command {
 	return &cli.Command{
 		Name:    "list",
 		Usage:   "列出所有仓库",
-		Action:  listAction,
-		Aliases: []string{"ls"},
+		Action:    listAction,
+		Aliases:   []string{"ls"},
+		ArgsUsage: "[query]",
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:  "details",
@@ -26,27 +29,49 @@ func ListCommand() *cli.Command {
 				Usage: "只显示路径",
 				Aliases: []string{"p"},
 			},
+			tagFlag(),
+			templateFlag(),
 		},
-		Description: `列出所有管理的仓库。
+		Description: `列出所有管理的仓库，提供查询时只列出满足条件的仓库。
+
+`+queryHelp+`
+
+`+templateHelp+`
 
 示例:
   projj list              # 列出所有仓库
   projj list --details    # 显示详细信息
-  projj list --path-only  # 只显示路径`,
+  projj list --path-only  # 只显示路径
+  projj list host:gitlab.com dirty:true
+  projj list --tag payments # 只列出带有 payments 标签的仓库
+  projj list --template '{{.Platform}}/{{.Owner}}/{{.Name}}\t{{rel .Path}}'
+  projj list --template '{{pad 24 .Name}} {{.Branch}}{{if .Dirty}} *{{end}}'`,
 	}
 }
 
 func listAction(ctx context.Context, cmd *cli.Command) error {
-	client, err := projj.New()
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	
+	query, err := filterQuery(cmd, cmd.Args().Slice())
 	if err != nil {
-		return fmt.Errorf("创建客户端失败: %w", err)
+		return err
 	}
 	
-	repos, err := client.List()
+	repos, err := client.Query(query)
 	if err != nil {
 		return fmt.Errorf("获取仓库列表失败: %w", err)
 	}
 	
+	if cmd.IsSet("template") {
+		return writeTemplate(client, cmd, templateRepos(repos))
+	}
+	if f := outputFormat(cmd); f.Structured() {
+		return output.List(os.Stdout, f, projj.RepoColumns, projj.NewRepoRecords(repos))
+	}
+	
 	if len(repos) == 0 {
 		fmt.Println("未找到任何仓库，请先使用 'projj add' 添加仓库")
 		return nil
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/output"
+	"github.com/atian25/projj-go/pkg/projj"
+	"github.com/urfave/cli/v3"
+)
+
+// formatFlag 返回全局的 --format 标志，所有子命令都可以使用
+func formatFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "format",
+		Usage:   "输出格式: text, json, ndjson, tsv（list、find、status、fetch、pull、grep、campaign list/show、config list、sync、import、add、remove 支持机器可读格式）",
+		Value:   string(output.Text),
+		Sources: cli.EnvVars("PROJJ_FORMAT"),
+	}
+}
+
+// checkFormat 在执行命令前校验输出格式
+func checkFormat(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+	_, err := output.Parse(cmd.String("format"))
+	return ctx, err
+}
+
+// outputFormat 返回命令的输出格式
+func outputFormat(cmd *cli.Command) output.Format {
+	// 已在 checkFormat 中校验过
+	f, _ := output.Parse(cmd.String("format"))
+	return f
+}
+
+// newClient 创建客户端，机器可读格式下进度信息输出到标准错误，标准输出只有结果
+func newClient(cmd *cli.Command) (*projj.Client, error) {
+	client, err := projj.New()
+	if err != nil {
+		return nil, fmt.Errorf("创建客户端失败: %w", err)
+	}
+	if outputFormat(cmd).Structured() {
+		client.SetOutput(os.Stderr)
+	}
+	return client, nil
+}
+
+// templateFlag 返回按模板输出的 --template 标志
+func templateFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "template",
+		Usage: "按 Go 模板输出每个仓库，也可以是配置中 templates 的预设名称",
+	}
+}
+
+// templateHelp 模板的用法说明，list、find、status 共用
+const templateHelp = `输出模板:
+  --template 使用 Go text/template 语法，每个仓库输出一行，\t 和 \n 表示制表符和换行
+  字段: .Name .Owner .Platform .Path .URL .Tags .Description .AddedAt .Visits .LastVisitedAt
+  git 状态（用到时才读取）: .Branch .Dirty .Changed .Untracked .Upstream .Ahead .Behind .Stashes .Detached .StatusError
+  函数: rel（相对基础目录的路径）、home（主目录显示为 ~）、date "2006-01-02"、ago、
+        pad 20、padl 6（按显示宽度补齐）、trunc 30、join ","、upper、lower
+  预设: projj config set -k templates.short -v '{{.Owner}}/{{.Name}}'，然后 --template short`
+
+// templateRepos 将仓库列表转换为模板中的仓库
+func templateRepos(repos []cache.Repository) []*output.TemplateRepo {
+	result := make([]*output.TemplateRepo, len(repos))
+	for i, repo := range repos {
+		result[i] = output.NewTemplateRepo(repo)
+	}
+	return result
+}
+
+// writeTemplate 按模板输出仓库列表
+func writeTemplate(client *projj.Client, cmd *cli.Command, repos []*output.TemplateRepo) error {
+	if outputFormat(cmd).Structured() {
+		return fmt.Errorf("--template 不能与 --format %s 同时使用", outputFormat(cmd))
+	}
+	tmpl, err := client.Template(cmd.String("template"))
+	if err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		if err := tmpl.Execute(os.Stdout, repo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorRecord 表示机器可读格式下的错误
+type errorRecord struct {
+	Error string `json:"error"`
+}
+
+// ReportError 使用机器可读格式时将错误输出到标准输出，返回是否已输出；text 格式时由调用方处理
+func ReportError(app *cli.Command, err error) bool {
+	f, parseErr := output.Parse(app.String("format"))
+	if parseErr != nil || !f.Structured() {
+		return false
+	}
+	return output.Object(os.Stdout, f, []string{"error"}, errorRecord{Error: err.Error()}) == nil
+}
\ No newline at end of file
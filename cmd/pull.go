@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/atian25/projj-go/pkg/projj"
+	"github.com/urfave/cli/v3"
+)
+
+// PullCommand 返回 pull 命令
+func PullCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "pull",
+		Usage:     "并发拉取多个仓库的更新",
+		Action:    pullAction,
+		ArgsUsage: "[query]",
+		Flags: append(updateFlags(),
+			&cli.BoolFlag{
+				Name:  "ff-only",
+				Usage: "只允许快进合并，--ff-only=false 时按 git 的配置合并",
+				Value: true,
+			},
+		),
+		Description: `并发地在仓库中执行 git pull，提供查询或 --tag 时只处理满足条件的仓库。
+
+默认只允许快进合并，无法快进的仓库记为失败。已跟踪的文件有未提交修改、
+处于分离 HEAD 或当前分支没有上游的仓库会被跳过，只有未跟踪文件的仓库照常拉取。
+每个仓库的 git 输出单独捕获，不会交错显示；结束后输出已更新、已是最新、已跳过和失败的仓库汇总。
+
+示例:
+  projj pull
+  projj pull --tag work --workers 16
+  projj pull api --timeout 30s --verbose`,
+	}
+}
+
+func pullAction(ctx context.Context, cmd *cli.Command) error {
+	return runUpdate(ctx, cmd, "拉取", (*projj.Client).Pull, projj.UpdateOptions{FFOnly: cmd.Bool("ff-only")})
+}
\ No newline at end of file
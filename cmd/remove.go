@@ -3,7 +3,9 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 
+	"github.com/atian25/projj-go/internal/output"
 	"github.com/atian25/projj-go/pkg/projj"
 	"github.com/urfave/cli/v3"
 )
@@ -22,30 +24,53 @@ func RemoveCommand() *cli.Command {
 				Usage: "同时删除本地文件",
 				Aliases: []string{"d"},
 			},
+			tagFlag(),
+			fzfFlag(),
 		},
 		Description: `从 projj 管理中移除仓库。
 
 默认情况下只从缓存中移除，不删除本地文件。
 使用 --delete-files 标志可以同时删除本地文件。
+匹配多个仓库时，在终端中会打开选择器，否则列出候选仓库并退出。
 
 示例:
   projj remove golang/go           # 只从管理中移除
-  projj remove --delete-files go   # 移除并删除文件`,
+  projj remove --delete-files go   # 移除并删除文件
+  projj remove --tag legacy api    # 在带有 legacy 标签的仓库中查找`,
 	}
 }
 
 func removeAction(ctx context.Context, cmd *cli.Command) error {
-	if cmd.Args().Len() == 0 {
+	query, err := filterQuery(cmd, cmd.Args().Slice())
+	if err != nil {
+		return err
+	}
+	if query == "" {
 		return fmt.Errorf("请提供要移除的仓库查询条件")
 	}
 	
-	query := cmd.Args().Get(0)
 	deleteFiles := cmd.Bool("delete-files")
 	
-	client, err := projj.New()
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	
+	client.SetChooser(repoChooser(cmd))
+	repo, err := client.Remove(query, deleteFiles)
 	if err != nil {
-		return fmt.Errorf("创建客户端失败: %w", err)
+		return err
 	}
 	
-	return client.Remove(query, deleteFiles)
+	if f := outputFormat(cmd); f.Structured() {
+		record := removeRecord{RepoRecord: projj.NewRepoRecord(*repo), FilesDeleted: deleteFiles}
+		return output.Object(os.Stdout, f, append(projj.RepoColumns, "files_deleted"), record)
+	}
+	return nil
+}
+
+// removeRecord 表示机器可读格式下被移除的仓库
+type removeRecord struct {
+	projj.RepoRecord
+	FilesDeleted bool `json:"files_deleted"`
 }
\ No newline at end of file
@@ -11,9 +11,25 @@ func GetAllCommands() []*cli.Command {
 		InitCommand(),
 		AddCommand(),
 		FindCommand(),
+		JumpCommand(),
+		RecentCommand(),
 		ListCommand(),
+		StatusCommand(),
+		FetchCommand(),
+		PullCommand(),
 		RemoveCommand(),
 		SyncCommand(),
+		ImportCommand(),
+		RunCommand(),
+		RunAllCommand(),
+		ExecCommand(),
+		GrepCommand(),
+		CampaignCommand(),
+		IdentityCommand(),
+		TagCommand(),
+		DescribeCommand(),
+		UICommand(),
+		CacheCommand(),
 		
 		// 原有命令（保留用于演示）
 		HelloCommand(),
@@ -31,5 +47,7 @@ func NewApp() *cli.Command {
 			"支持统一的目录结构、智能的仓库查找、灵活的别名配置等功能。",
 		Version:     "1.0.0",
 		Commands:    GetAllCommands(),
+		Flags:       []cli.Flag{formatFlag()},
+		Before:      checkFormat,
 	}
 }
\ No newline at end of file
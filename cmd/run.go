@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/atian25/projj-go/pkg/projj"
+	"github.com/urfave/cli/v3"
+)
+
+// RunCommand 返回 run 命令
+func RunCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "run",
+		Usage:     "在当前仓库执行 hook",
+		Action:    runAction,
+		ArgsUsage: "<hook>",
+		Description: `在当前目录所属的仓库中执行配置的 hook。
+
+hook 在 ~/.projj/config.json 的 hooks 中配置，执行时以仓库根目录为工作目录。
+
+示例:
+  projj run clean
+  projj run install`,
+	}
+}
+
+// RunAllCommand 返回 runall 命令
+func RunAllCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "runall",
+		Usage:     "在所有仓库执行 hook",
+		Action:    runAllAction,
+		ArgsUsage: "<hook> [query]",
+		Flags: []cli.Flag{
+			tagFlag(),
+		},
+		Description: `在所有管理的仓库中依次执行配置的 hook，提供查询或 --tag 时只在满足条件的仓库中执行。
+
+单个仓库失败不会中断执行，结束后输出成功与失败的汇总。
+
+示例:
+  projj runall clean
+  projj runall install
+  projj runall --tag payments install
+  projj runall clean host:gitlab.com`,
+	}
+}
+
+func runAction(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() == 0 {
+		return fmt.Errorf("请提供 hook 名称")
+	}
+	
+	name := cmd.Args().Get(0)
+	
+	client, err := projj.New()
+	if err != nil {
+		return fmt.Errorf("创建客户端失败: %w", err)
+	}
+	
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	
+	return client.Run(name, cwd)
+}
+
+func runAllAction(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() == 0 {
+		return fmt.Errorf("请提供 hook 名称")
+	}
+	
+	name := cmd.Args().Get(0)
+	
+	client, err := projj.New()
+	if err != nil {
+		return fmt.Errorf("创建客户端失败: %w", err)
+	}
+	
+	filter, err := filterQuery(cmd, cmd.Args().Tail())
+	if err != nil {
+		return err
+	}
+	
+	_, err = client.RunAll(name, filter)
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/atian25/projj-go/internal/git"
+	"github.com/atian25/projj-go/internal/output"
+	"github.com/atian25/projj-go/internal/termutil"
+	"github.com/atian25/projj-go/pkg/projj"
+	"github.com/urfave/cli/v3"
+)
+
+// StatusCommand 返回 status 命令
+func StatusCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "status",
+		Usage:     "查看多个仓库的 git 状态",
+		Action:    statusAction,
+		Aliases:   []string{"st"},
+		ArgsUsage: "[query]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "only-dirty",
+				Usage: "只显示有未提交修改（包括未跟踪文件）的仓库",
+			},
+			&cli.BoolFlag{
+				Name:  "only-unpushed",
+				Usage: "只显示有未推送提交（领先上游或没有上游）的仓库",
+			},
+			&cli.IntFlag{
+				Name:  "workers",
+				Usage: "并发数，默认使用配置中的 scan_workers 或 CPU 数",
+			},
+			tagFlag(),
+			templateFlag(),
+		},
+		Description: `并发读取仓库的 git 状态，提供查询时只检查满足条件的仓库。
+
+每个仓库显示当前分支、修改和未跟踪的文件数、相对上游领先和落后的提交数、
+stash 数量，以及分离 HEAD、没有上游等情况。读取失败的仓库总会显示。
+
+示例:
+  projj status                      # 所有仓库
+  projj status --only-dirty         # 只显示有未提交修改的仓库
+  projj status --only-unpushed      # 只显示有未推送提交的仓库
+  projj status --tag work --format json
+  projj status --template '{{.Name}}\t{{.Ahead}}\t{{.Behind}}'`,
+	}
+}
+
+func statusAction(ctx context.Context, cmd *cli.Command) error {
+	client, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	
+	filter, err := filterQuery(cmd, cmd.Args().Slice())
+	if err != nil {
+		return err
+	}
+	
+	results, err := client.Status(projj.StatusOptions{
+		Filter:       filter,
+		Workers:      cmd.Int("workers"),
+		OnlyDirty:    cmd.Bool("only-dirty"),
+		OnlyUnpushed: cmd.Bool("only-unpushed"),
+	})
+	if err != nil {
+		return err
+	}
+	
+	if cmd.IsSet("template") {
+		repos := make([]*output.TemplateRepo, len(results))
+		for i, result := range results {
+			repos[i] = output.NewTemplateRepo(result.Repo).WithStatus(result.Status, result.Err)
+		}
+		return writeTemplate(client, cmd, repos)
+	}
+	
+	if f := outputFormat(cmd); f.Structured() {
+		records := make([]projj.StatusRecord, len(results))
+		for i, result := range results {
+			records[i] = projj.NewStatusRecord(result)
+		}
+		return output.List(os.Stdout, f, projj.StatusColumns, records)
+	}
+	
+	if len(results) == 0 {
+		fmt.Println("没有需要关注的仓库")
+		return nil
+	}
+	printStatus(results, client.GetConfig().GetBasePath())
+	return nil
+}
+
+// printStatus 按列输出仓库状态和汇总
+func printStatus(results []projj.RepoStatus, base string) {
+	names := make([]string, len(results))
+	nameWidth, branchWidth := 0, 0
+	for i, result := range results {
+		names[i] = output.RelPath(base, result.Repo.Path)
+		nameWidth = max(nameWidth, termutil.Width(names[i]))
+		if result.Status != nil {
+			branchWidth = max(branchWidth, termutil.Width(result.Status.Branch))
+		}
+	}
+	
+	var dirty, unpushed, failed int
+	for i, result := range results {
+		name := names[i] + strings.Repeat(" ", nameWidth-termutil.Width(names[i]))
+		if result.Err != nil {
+			failed++
+			fmt.Printf("%s  错误: %v\n", name, result.Err)
+			continue
+		}
+		
+		s := result.Status
+		if s.Dirty() {
+			dirty++
+		}
+		if s.Unpushed() {
+			unpushed++
+		}
+		branch := s.Branch + strings.Repeat(" ", branchWidth-termutil.Width(s.Branch))
+		fmt.Printf("%s  %s  %s\n", name, branch, describeStatus(s))
+	}
+	
+	fmt.Printf("\n共 %d 个仓库: %d 个有未提交的修改，%d 个有未推送的提交", len(results), dirty, unpushed)
+	if failed > 0 {
+		fmt.Printf("，%d 个读取失败", failed)
+	}
+	fmt.Println()
+}
+
+// describeStatus 返回仓库状态的简短说明
+func describeStatus(s *git.Status) string {
+	var parts []string
+	if s.Changed > 0 {
+		parts = append(parts, fmt.Sprintf("修改 %d", s.Changed))
+	}
+	if s.Untracked > 0 {
+		parts = append(parts, fmt.Sprintf("未跟踪 %d", s.Untracked))
+	}
+	if s.Conflicts > 0 {
+		parts = append(parts, fmt.Sprintf("冲突 %d", s.Conflicts))
+	}
+	if s.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("领先 %d", s.Ahead))
+	}
+	if s.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("落后 %d", s.Behind))
+	}
+	if s.Detached {
+		parts = append(parts, "分离 HEAD")
+	} else if s.NoUpstream() {
+		parts = append(parts, "无上游")
+	}
+	if s.Stashes > 0 {
+		parts = append(parts, fmt.Sprintf("stash %d", s.Stashes))
+	}
+	if len(parts) == 0 {
+		return "干净"
+	}
+	return strings.Join(parts, "  ")
+}
\ No newline at end of file
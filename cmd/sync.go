@@ -2,8 +2,9 @@ package cmd
 
 import (
 	"context"
-	"fmt"
+	"os"
 
+	"github.com/atian25/projj-go/internal/output"
 	"github.com/atian25/projj-go/pkg/projj"
 	"github.com/urfave/cli/v3"
 )
@@ -15,21 +16,47 @@ func SyncCommand() *cli.Command {
 		Usage:   "同步缓存与文件系统",
 		Action:  syncAction,
 		Aliases: []string{"s"},
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "nested",
+				Usage: "同时扫描仓库内嵌套的仓库和子模块",
+			},
+			&cli.IntFlag{
+				Name:  "workers",
+				Usage: "扫描并发数，默认使用配置中的 scan_workers 或 CPU 数",
+			},
+		},
 		Description: `同步缓存与文件系统状态。
 
 检查缓存中的仓库是否还存在，移除不存在的记录。
-扫描并添加新发现的仓库到缓存中。
+扫描基础目录，按 origin URL 添加新发现的仓库到缓存中。
+磁盘路径与 origin URL 对应的标准路径不一致的仓库会被标记出来。
 
 示例:
-  projj sync`,
+  projj sync
+  projj sync --nested   # 包括嵌套仓库和子模块`,
 	}
 }
 
 func syncAction(ctx context.Context, cmd *cli.Command) error {
-	client, err := projj.New()
+	client, err := newClient(cmd)
 	if err != nil {
-		return fmt.Errorf("创建客户端失败: %w", err)
+		return err
 	}
 	
-	return client.Sync()
+	result, err := client.Sync(projj.SyncOptions{
+		Nested:  cmd.Bool("nested"),
+		Workers: cmd.Int("workers"),
+	})
+	if err != nil {
+		return err
+	}
+	
+	if f := outputFormat(cmd); f.Structured() {
+		if result.Mismatched == nil {
+			result.Mismatched = []projj.PathMismatch{}
+		}
+		return output.Object(os.Stdout, f, []string{"added", "removed", "mismatched"}, result)
+	}
+	return nil
 }
\ No newline at end of file
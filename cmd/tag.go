@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/pkg/projj"
+	"github.com/urfave/cli/v3"
+)
+
+// TagCommand 返回 tag 命令
+func TagCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "tag",
+		Usage: "管理仓库标签",
+		Description: `为仓库添加自定义标签，按服务或团队分组。
+
+仓库可以用查询指定，"." 表示当前目录所属的仓库。
+list、find、remove、jump、recent、runall 和 identity 都支持用 --tag 只处理带有标签的仓库，
+字段查询中也可以使用 tag:payments。
+
+示例:
+  projj tag add api-gateway payments infra
+  projj tag rm . infra
+  projj tag ls
+  projj list --tag payments`,
+		Commands: []*cli.Command{
+			{
+				Name:      "add",
+				Usage:     "为仓库添加标签",
+				ArgsUsage: "<repo> <tag>...",
+				Action:    tagAddAction,
+				Flags:     []cli.Flag{fzfFlag()},
+			},
+			{
+				Name:      "rm",
+				Usage:     "移除仓库的标签",
+				Aliases:   []string{"remove"},
+				ArgsUsage: "<repo> <tag>...",
+				Action:    tagRemoveAction,
+				Flags:     []cli.Flag{fzfFlag()},
+			},
+			{
+				Name:      "ls",
+				Usage:     "列出所有标签，或指定仓库的标签",
+				Aliases:   []string{"list"},
+				ArgsUsage: "[repo]",
+				Action:    tagListAction,
+			},
+		},
+	}
+}
+
+// DescribeCommand 返回 describe 命令
+func DescribeCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "describe",
+		Usage:     "查看或设置仓库描述",
+		Action:    describeAction,
+		ArgsUsage: "<repo> [description]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "clear",
+				Usage: "清除描述",
+			},
+			fzfFlag(),
+		},
+		Description: `为仓库写一段说明，显示在 list --details 中，可以用 desc: 字段查询。
+
+只提供仓库时显示当前描述。
+
+示例:
+  projj describe api-gateway "对外 API 入口，负责鉴权和限流"
+  projj describe . --clear`,
+	}
+}
+
+// tagFlag 返回按标签过滤仓库的 --tag 标志
+func tagFlag() cli.Flag {
+	return &cli.StringSliceFlag{
+		Name:    "tag",
+		Aliases: []string{"t"},
+		Usage:   "只处理带有该标签的仓库，多次指定时需带有全部标签",
+	}
+}
+
+// filterQuery 将命令参数组成的查询与 --tag 指定的标签合并
+func filterQuery(cmd *cli.Command, args []string) (string, error) {
+	query := strings.TrimSpace(strings.Join(args, " "))
+	tags := cmd.StringSlice("tag")
+	if len(tags) == 0 {
+		return query, nil
+	}
+	
+	var parts []string
+	if query != "" {
+		parts = append(parts, "("+query+")")
+	}
+	for _, tag := range tags {
+		t, err := cache.NormalizeTag(tag)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, "tag:"+t)
+	}
+	return strings.Join(parts, " "), nil
+}
+
+func tagAddAction(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() < 2 {
+		return fmt.Errorf("请提供仓库和标签")
+	}
+	
+	client, err := projj.New()
+	if err != nil {
+		return fmt.Errorf("创建客户端失败: %w", err)
+	}
+	
+	client.SetChooser(repoChooser(cmd))
+	repo, added, err := client.AddTags(cmd.Args().First(), cmd.Args().Tail()...)
+	if err != nil {
+		return err
+	}
+	
+	if len(added) == 0 {
+		fmt.Printf("%s 已有这些标签\n", repo.Name)
+	} else {
+		fmt.Printf("已为 %s 添加标签: %s\n", repo.Name, strings.Join(added, ", "))
+	}
+	fmt.Printf("当前标签: %s\n", strings.Join(repo.Tags, ", "))
+	return nil
+}
+
+func tagRemoveAction(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() < 2 {
+		return fmt.Errorf("请提供仓库和标签")
+	}
+	
+	client, err := projj.New()
+	if err != nil {
+		return fmt.Errorf("创建客户端失败: %w", err)
+	}
+	
+	client.SetChooser(repoChooser(cmd))
+	repo, removed, err := client.RemoveTags(cmd.Args().First(), cmd.Args().Tail()...)
+	if err != nil {
+		return err
+	}
+	
+	if len(removed) == 0 {
+		fmt.Printf("%s 没有这些标签\n", repo.Name)
+	} else {
+		fmt.Printf("已移除 %s 的标签: %s\n", repo.Name, strings.Join(removed, ", "))
+	}
+	if len(repo.Tags) > 0 {
+		fmt.Printf("当前标签: %s\n", strings.Join(repo.Tags, ", "))
+	}
+	return nil
+}
+
+func tagListAction(ctx context.Context, cmd *cli.Command) error {
+	client, err := projj.New()
+	if err != nil {
+		return fmt.Errorf("创建客户端失败: %w", err)
+	}
+	
+	// 指定仓库时只列出该仓库的标签
+	if cmd.Args().Len() > 0 {
+		query := strings.Join(cmd.Args().Slice(), " ")
+		var repos []cache.Repository
+		if query == "." {
+			repo, err := client.Resolve(query)
+			if err != nil {
+				return err
+			}
+			repos = append(repos, *repo)
+		} else if repos, err = client.Find(query); err != nil {
+			return fmt.Errorf("查找仓库失败: %w", err)
+		}
+		for _, repo := range repos {
+			fmt.Printf("%s (%s): %s\n", repo.Name, repo.Path, strings.Join(repo.Tags, ", "))
+		}
+		return nil
+	}
+	
+	counts, err := client.Tags()
+	if err != nil {
+		return fmt.Errorf("获取标签失败: %w", err)
+	}
+	if len(counts) == 0 {
+		fmt.Println("还没有任何标签，可使用 'projj tag add <repo> <tag>' 添加")
+		return nil
+	}
+	
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		fmt.Printf("%-20s %d 个仓库\n", tag, counts[tag])
+	}
+	return nil
+}
+
+func describeAction(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() == 0 {
+		return fmt.Errorf("请提供仓库")
+	}
+	
+	client, err := projj.New()
+	if err != nil {
+		return fmt.Errorf("创建客户端失败: %w", err)
+	}
+	
+	client.SetChooser(repoChooser(cmd))
+	query := cmd.Args().First()
+	description := strings.Join(cmd.Args().Tail(), " ")
+	if cmd.Bool("clear") {
+		description = ""
+	} else if description == "" {
+		// 只提供仓库时显示当前描述
+		repo, err := client.Resolve(query)
+		if err != nil {
+			return err
+		}
+		if repo.Description == "" {
+			fmt.Printf("%s 还没有描述\n", repo.Name)
+		} else {
+			fmt.Println(repo.Description)
+		}
+		return nil
+	}
+	
+	repo, err := client.Describe(query, description)
+	if err != nil {
+		return err
+	}
+	if repo.Description == "" {
+		fmt.Printf("已清除 %s 的描述\n", repo.Name)
+	} else {
+		fmt.Printf("已更新 %s 的描述\n", repo.Name)
+	}
+	return nil
+}
\ No newline at end of file
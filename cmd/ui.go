@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atian25/projj-go/pkg/projj"
+	"github.com/urfave/cli/v3"
+)
+
+// UICommand 返回 ui 命令
+func UICommand() *cli.Command {
+	return &cli.Command{
+		Name:  "ui",
+		Usage: "在全屏界面中浏览和管理仓库",
+		Description: `按 host/owner 或标签分组显示所有仓库及其 git 状态，
+输入时即时过滤，并可对光标所在的仓库或多选的仓库执行操作:
+
+  ↑↓ / j k   移动光标          空格   选择或取消选择
+  a          全选或取消全选    /      输入过滤条件（支持字段查询）
+  g          切换分组方式      r      刷新仓库列表和 git 状态
+  o / Enter  在仓库中打开 shell
+  p          拉取更新          d      从 projj 中移除（不删除文件）
+  y          复制仓库路径      h      执行 hook
+  q / Esc    退出
+
+状态标记: ✓ 干净  ● 有未提交的修改  … 读取中  ? 读取失败`,
+		Action: uiAction,
+	}
+}
+
+func uiAction(ctx context.Context, cmd *cli.Command) error {
+	client, err := projj.New()
+	if err != nil {
+		return fmt.Errorf("创建客户端失败: %w", err)
+	}
+	
+	return client.UI()
+}
\ No newline at end of file
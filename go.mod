@@ -2,11 +2,10 @@ module github.com/atian25/projj-go
 
 go 1.22.2
 
-require github.com/urfave/cli/v2 v2.27.7
-
 require (
-	github.com/cpuguy83/go-md2man/v2 v2.0.7 // indirect
-	github.com/russross/blackfriday/v2 v2.1.0 // indirect
-	github.com/urfave/cli/v3 v3.3.8 // indirect
-	github.com/xrash/smetrics v0.0.0-20240521201337-686a1a2994c1 // indirect
+	github.com/urfave/cli/v3 v3.3.8
+	go.etcd.io/bbolt v1.3.11
+	golang.org/x/term v0.4.0
 )
+
+require golang.org/x/sys v0.4.0 // indirect
@@ -0,0 +1,401 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/atian25/projj-go/internal/config"
+	"github.com/atian25/projj-go/internal/fsutil"
+	"github.com/atian25/projj-go/internal/lockfile"
+	bolt "go.etcd.io/bbolt"
+)
+
+// bucket 名称：repos 以路径为键保存仓库，url 和 name 为索引，键为索引值加分隔符加路径
+var (
+	bucketRepos = []byte("repos")
+	bucketURL   = []byte("url")
+	bucketName  = []byte("name")
+)
+
+// indexSep 索引键中索引值与路径之间的分隔符
+const indexSep = "\x00"
+
+// GetDBPath 获取数据库文件路径
+func GetDBPath() string {
+	return filepath.Join(config.GetConfigDir(), "cache.db")
+}
+
+// BoltStore 基于 bbolt 的存储，按路径、规范化 URL 和名称建立索引
+type BoltStore struct {
+	path   string
+	notice string
+}
+
+// OpenBolt 打开数据库，不存在时创建，并导入已有的 cache.json
+func OpenBolt(path string) (*BoltStore, error) {
+	s := &BoltStore{path: path}
+	if fsutil.Exists(path) {
+		return s, nil
+	}
+	
+	count, err := s.importJSON()
+	if err != nil {
+		return nil, fmt.Errorf("从 cache.json 迁移失败: %w", err)
+	}
+	if count > 0 {
+		s.notice = fmt.Sprintf("已将 %s 中的 %d 个仓库迁移到 %s", GetCachePath(), count, path)
+	}
+	return s, nil
+}
+
+// importJSON 创建数据库并导入 cache.json，先写入临时文件，完成后再重命名，避免留下不完整的数据库
+func (s *BoltStore) importJSON() (int, error) {
+	lock, err := lockfile.Acquire(s.path, lockfile.DefaultTimeout)
+	if err != nil {
+		return 0, err
+	}
+	defer lock.Release()
+	
+	// 其他进程可能已完成迁移
+	if fsutil.Exists(s.path) {
+		return 0, nil
+	}
+	
+	cache, err := Load()
+	if err != nil {
+		return 0, err
+	}
+	
+	tmpPath := s.path + ".tmp"
+	os.Remove(tmpPath)
+	db, err := bolt.Open(tmpPath, 0644, &bolt.Options{Timeout: lockfile.DefaultTimeout})
+	if err != nil {
+		return 0, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if err := createBuckets(tx); err != nil {
+			return err
+		}
+		for _, repo := range cache.Repositories {
+			if err := putRepo(tx, repo); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	return len(cache.Repositories), nil
+}
+
+// view 以只读方式打开数据库执行查询，每次操作单独打开，避免长时间持有文件锁
+func (s *BoltStore) view(fn func(tx *bolt.Tx) error) error {
+	db, err := bolt.Open(s.path, 0644, &bolt.Options{Timeout: lockfile.DefaultTimeout, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("打开缓存数据库失败: %w", err)
+	}
+	defer db.Close()
+	return db.View(fn)
+}
+
+// update 以读写方式打开数据库并在事务中执行修改
+func (s *BoltStore) update(fn func(tx *bolt.Tx) error) error {
+	db, err := bolt.Open(s.path, 0644, &bolt.Options{Timeout: lockfile.DefaultTimeout})
+	if err != nil {
+		return fmt.Errorf("打开缓存数据库失败: %w", err)
+	}
+	defer db.Close()
+	return db.Update(func(tx *bolt.Tx) error {
+		if err := createBuckets(tx); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+func (s *BoltStore) List() ([]Repository, error) {
+	var repos []Repository
+	err := s.view(func(tx *bolt.Tx) error {
+		return forEachRepo(tx, func(repo Repository) error {
+			repos = append(repos, repo)
+			return nil
+		})
+	})
+	return repos, err
+}
+
+// Find 解码所有仓库后评分，与 JSON 存储的匹配类型和排序一致
+func (s *BoltStore) Find(query string) ([]Match, error) {
+	repos, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	return NewIndex(repos).Search(query), nil
+}
+
+func (s *BoltStore) GetByPath(path string) (*Repository, error) {
+	var repo *Repository
+	err := s.view(func(tx *bolt.Tx) error {
+		var err error
+		repo, err = getRepo(tx, path)
+		return err
+	})
+	return repo, err
+}
+
+// GetByDir 从指定目录逐级向上按路径查找
+func (s *BoltStore) GetByDir(dir string) (*Repository, error) {
+	var repo *Repository
+	err := s.view(func(tx *bolt.Tx) error {
+		for current := filepath.Clean(dir); ; current = filepath.Dir(current) {
+			found, err := getRepo(tx, current)
+			if err != nil || found != nil {
+				repo = found
+				return err
+			}
+			if filepath.Dir(current) == current {
+				return nil
+			}
+		}
+	})
+	return repo, err
+}
+
+func (s *BoltStore) GetByURL(url string) ([]Repository, error) {
+	var repos []Repository
+	prefix := []byte(CanonicalURL(url) + indexSep)
+	err := s.view(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketURL)
+		if b == nil {
+			return nil
+		}
+		c := b.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			_, path := splitIndexKey(k)
+			repo, err := getRepo(tx, path)
+			if err != nil {
+				return err
+			}
+			if repo != nil {
+				repos = append(repos, *repo)
+			}
+		}
+		return nil
+	})
+	return repos, err
+}
+
+func (s *BoltStore) Put(repos ...Repository) error {
+	return s.update(func(tx *bolt.Tx) error {
+		for _, repo := range repos {
+			if err := putRepo(tx, repo); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *BoltStore) Remove(path string) (bool, error) {
+	var removed bool
+	err := s.update(func(tx *bolt.Tx) error {
+		var err error
+		removed, err = deleteRepo(tx, path)
+		return err
+	})
+	return removed, err
+}
+
+func (s *BoltStore) Visit(path string, at time.Time) (bool, error) {
+	var visited bool
+	err := s.update(func(tx *bolt.Tx) error {
+		repo, err := getRepo(tx, path)
+		if err != nil || repo == nil {
+			return err
+		}
+		repo.Visits++
+		repo.LastVisitedAt = at
+		visited = true
+		return putRepo(tx, *repo)
+	})
+	return visited, err
+}
+
+func (s *BoltStore) Modify(path string, fn func(repo *Repository) error) (bool, error) {
+	var found bool
+	err := s.update(func(tx *bolt.Tx) error {
+		repo, err := getRepo(tx, path)
+		if err != nil || repo == nil {
+			return err
+		}
+		found = true
+		if err := fn(repo); err != nil {
+			return err
+		}
+		// 修改不能改变路径，否则会留下旧记录
+		repo.Path = path
+		return putRepo(tx, *repo)
+	})
+	return found, err
+}
+
+func (s *BoltStore) Sync(discovered []Repository) (int, int, error) {
+	var added, removed int
+	err := s.update(func(tx *bolt.Tx) error {
+		// 先收集再删除，避免遍历时修改 bucket
+		var missing []string
+		err := forEachRepo(tx, func(repo Repository) error {
+			if _, err := os.Stat(filepath.Join(repo.Path, ".git")); err != nil {
+				missing = append(missing, repo.Path)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, path := range missing {
+			if _, err := deleteRepo(tx, path); err != nil {
+				return err
+			}
+			removed++
+		}
+		
+		for _, repo := range discovered {
+			existing, err := getRepo(tx, repo.Path)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				continue
+			}
+			if err := putRepo(tx, repo); err != nil {
+				return err
+			}
+			added++
+		}
+		return nil
+	})
+	return added, removed, err
+}
+
+func (s *BoltStore) Notice() string {
+	return s.notice
+}
+
+// createBuckets 创建所需的 bucket
+func createBuckets(tx *bolt.Tx) error {
+	for _, name := range [][]byte{bucketRepos, bucketURL, bucketName} {
+		if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexKeys 返回仓库在 url 和 name 索引中的键
+func indexKeys(repo Repository) ([]byte, []byte) {
+	urlKey := []byte(CanonicalURL(repo.URL) + indexSep + repo.Path)
+	nameKey := []byte(strings.ToLower(repo.Name) + indexSep + repo.Path)
+	return urlKey, nameKey
+}
+
+// splitIndexKey 将索引键拆分为索引值和路径
+func splitIndexKey(key []byte) (string, string) {
+	value, path, _ := strings.Cut(string(key), indexSep)
+	return value, path
+}
+
+// getRepo 根据路径读取仓库，不存在时返回 nil
+func getRepo(tx *bolt.Tx, path string) (*Repository, error) {
+	b := tx.Bucket(bucketRepos)
+	if b == nil {
+		return nil, nil
+	}
+	data := b.Get([]byte(path))
+	if data == nil {
+		return nil, nil
+	}
+	var repo Repository
+	if err := json.Unmarshal(data, &repo); err != nil {
+		return nil, fmt.Errorf("解析仓库 %s 失败: %w", path, err)
+	}
+	return &repo, nil
+}
+
+// putRepo 写入仓库并更新索引，已存在时合并到原记录
+func putRepo(tx *bolt.Tx, repo Repository) error {
+	existing, err := getRepo(tx, repo.Path)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		repo = merge(*existing, repo)
+	}
+	if _, err := deleteRepo(tx, repo.Path); err != nil {
+		return err
+	}
+	if repo.AddedAt.IsZero() {
+		repo.AddedAt = time.Now()
+	}
+	
+	data, err := json.Marshal(repo)
+	if err != nil {
+		return err
+	}
+	if err := tx.Bucket(bucketRepos).Put([]byte(repo.Path), data); err != nil {
+		return err
+	}
+	
+	urlKey, nameKey := indexKeys(repo)
+	if err := tx.Bucket(bucketURL).Put(urlKey, nil); err != nil {
+		return err
+	}
+	return tx.Bucket(bucketName).Put(nameKey, nil)
+}
+
+// deleteRepo 删除仓库及其索引
+func deleteRepo(tx *bolt.Tx, path string) (bool, error) {
+	repo, err := getRepo(tx, path)
+	if err != nil || repo == nil {
+		return false, err
+	}
+	
+	if err := tx.Bucket(bucketRepos).Delete([]byte(path)); err != nil {
+		return false, err
+	}
+	urlKey, nameKey := indexKeys(*repo)
+	if err := tx.Bucket(bucketURL).Delete(urlKey); err != nil {
+		return false, err
+	}
+	return true, tx.Bucket(bucketName).Delete(nameKey)
+}
+
+// forEachRepo 按路径顺序遍历所有仓库
+func forEachRepo(tx *bolt.Tx, fn func(Repository) error) error {
+	b := tx.Bucket(bucketRepos)
+	if b == nil {
+		return nil
+	}
+	return b.ForEach(func(k, v []byte) error {
+		var repo Repository
+		if err := json.Unmarshal(v, &repo); err != nil {
+			return fmt.Errorf("解析仓库 %s 失败: %w", k, err)
+		}
+		return fn(repo)
+	})
+}
\ No newline at end of file
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,30 +10,65 @@ import (
 	"time"
 
 	"github.com/atian25/projj-go/internal/config"
+	"github.com/atian25/projj-go/internal/fsutil"
+	"github.com/atian25/projj-go/internal/lockfile"
 )
 
 // Repository 表示一个仓库的信息
 type Repository struct {
-	Name     string    `json:"name"`
-	URL      string    `json:"url"`
-	Path     string    `json:"path"`
-	Platform string    `json:"platform"`
-	AddedAt  time.Time `json:"added_at"`
+	Name          string    `json:"name"`
+	URL           string    `json:"url"`
+	Path          string    `json:"path"`
+	Platform      string    `json:"platform"`
+	AddedAt       time.Time `json:"added_at"`
+	Visits        int       `json:"visits,omitempty"`
+	LastVisitedAt time.Time `json:"last_visited_at,omitempty"`
+	Tags          []string  `json:"tags,omitempty"`
+	Description   string    `json:"description,omitempty"`
 }
 
 // Cache 表示缓存结构
 type Cache struct {
+	Version      int          `json:"version"`
 	Repositories []Repository `json:"repositories"`
 	UpdatedAt    time.Time    `json:"updated_at"`
+	Recovered    string       `json:"-"` // 缓存文件损坏并从备份恢复时的说明
+	Format       string       `json:"-"` // 保存时的写入格式，由调用方按配置的 cache_format 设置，为空时为 projj-go 格式
 }
 
+// Backups 保存缓存时保留的历史备份数量
+var Backups = 3
+
+// ErrCorrupt 表示缓存文件无法解析且没有可用的备份
+var ErrCorrupt = errors.New("缓存文件已损坏")
+
 // GetCachePath 获取缓存文件路径
 func GetCachePath() string {
 	return filepath.Join(config.GetConfigDir(), "cache.json")
 }
 
-// Load 加载缓存文件
+// errNeedRecover 表示缓存文件损坏，需要持有锁后再恢复
+var errNeedRecover = errors.New("缓存文件需要恢复")
+
+// Load 加载缓存文件，文件损坏时在锁内从备份恢复
 func Load() (*Cache, error) {
+	cache, err := load(false)
+	if !errors.Is(err, errNeedRecover) {
+		return cache, err
+	}
+	
+	// 恢复会重命名缓存文件，需要持有与 Update 相同的锁，避免多个进程同时恢复
+	lock, err := lockfile.Acquire(GetCachePath(), lockfile.DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("锁定缓存文件失败: %w", err)
+	}
+	defer lock.Release()
+	
+	return load(true)
+}
+
+// load 读取缓存文件，文件损坏时 locked 为 true 才从备份恢复（调用方需持有锁），否则返回 errNeedRecover
+func load(locked bool) (*Cache, error) {
 	cachePath := GetCachePath()
 	
 	// 如果缓存文件不存在，返回空缓存
@@ -48,51 +84,105 @@ func Load() (*Cache, error) {
 		return nil, fmt.Errorf("读取缓存文件失败: %w", err)
 	}
 	
-	// 尝试解析为新格式
-	var cache Cache
-	if err := json.Unmarshal(data, &cache); err == nil && len(cache.Repositories) > 0 {
-		return &cache, nil
+	cache, err := migrate(data)
+	if err == nil {
+		return cache, nil
 	}
-	
-	// 尝试解析为旧格式（projj 原始格式）
-	var oldFormat map[string]interface{}
-	if err := json.Unmarshal(data, &oldFormat); err != nil {
-		return nil, fmt.Errorf("解析缓存文件失败: %w", err)
+	// 更新版本写入的缓存不是损坏，恢复或重建都会丢失其中的数据
+	if errors.Is(err, ErrUnsupportedVersion) {
+		return nil, err
 	}
-	
-	// 转换旧格式到新格式
-	cache = Cache{
-		Repositories: make([]Repository, 0),
-		UpdatedAt:    time.Now(),
+	if !locked {
+		return nil, errNeedRecover
+	}
+	// 等待锁期间其他进程可能已经恢复，此时读到的是恢复后的文件
+	return recoverCache(cachePath, data, err)
+}
+
+// LoadFile 加载指定的缓存文件，旧版本格式（包括原版 projj 格式）会迁移到当前版本
+func LoadFile(cachePath string) (*Cache, error) {
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取缓存文件失败: %w", err)
+	}
+	return migrate(data)
+}
+
+// recoverCache 将损坏的缓存文件另存，并从最新的可用备份恢复；
+// 恢复时直接覆盖缓存文件，避免未持有锁的读取看到文件不存在
+func recoverCache(cachePath string, data []byte, parseErr error) (*Cache, error) {
+	corruptPath := cachePath + ".corrupt"
+	if err := fsutil.WriteFileAtomic(corruptPath, data, 0644); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, parseErr)
 	}
 	
-	for path, repoData := range oldFormat {
-		// 跳过 version 字段
-		if path == "version" {
+	for i := 1; i <= Backups; i++ {
+		backupPath := backupPath(cachePath, i)
+		data, err := os.ReadFile(backupPath)
+		if err != nil {
+			continue
+		}
+		cache, err := migrate(data)
+		if err != nil {
 			continue
 		}
-		
-		if repoMap, ok := repoData.(map[string]interface{}); ok {
-			if repoURL, exists := repoMap["repo"]; exists {
-				if urlStr, ok := repoURL.(string); ok {
-					repo := Repository{
-						Path:     path,
-						URL:      urlStr,
-						Name:     extractRepoName(path),
-						Platform: extractPlatform(urlStr),
-						AddedAt:  time.Now(),
-					}
-					cache.Repositories = append(cache.Repositories, repo)
-				}
-			}
+		if err := fsutil.WriteFileAtomic(cachePath, data, 0644); err != nil {
+			return nil, fmt.Errorf("恢复缓存文件失败: %w", err)
 		}
+		cache.Recovered = fmt.Sprintf("缓存文件已损坏（%v），已从备份 %s 恢复，损坏的文件保存在 %s", parseErr, backupPath, corruptPath)
+		return cache, nil
 	}
 	
-	return &cache, nil
+	// 没有可用的备份时移走损坏的文件，由调用方重建
+	if err := os.Remove(cachePath); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, parseErr)
+	}
+	return nil, fmt.Errorf("%w: %v，损坏的文件保存在 %s", ErrCorrupt, parseErr, corruptPath)
+}
+
+// backupPath 返回第 n 个备份的路径，数字越小越新
+func backupPath(cachePath string, n int) string {
+	return fmt.Sprintf("%s.bak.%d", cachePath, n)
 }
 
 // Save 保存缓存文件
 func (c *Cache) Save() error {
+	lock, err := lockfile.Acquire(GetCachePath(), lockfile.DefaultTimeout)
+	if err != nil {
+		return fmt.Errorf("锁定缓存文件失败: %w", err)
+	}
+	defer lock.Release()
+	
+	return c.save()
+}
+
+// Update 在文件锁内重新加载缓存、修改并按 format 保存，避免并发修改相互覆盖
+func Update(format string, fn func(*Cache) error) (*Cache, error) {
+	lock, err := lockfile.Acquire(GetCachePath(), lockfile.DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("锁定缓存文件失败: %w", err)
+	}
+	defer lock.Release()
+	
+	cache, err := load(true)
+	if err != nil {
+		return nil, err
+	}
+	cache.Format = format
+	
+	if err := fn(cache); err != nil {
+		return nil, err
+	}
+	
+	if err := cache.save(); err != nil {
+		return nil, err
+	}
+	
+	return cache, nil
+}
+
+// save 写入缓存文件，调用方需持有锁
+func (c *Cache) save() error {
 	configDir := config.GetConfigDir()
 	
 	// 确保配置目录存在
@@ -100,36 +190,104 @@ func (c *Cache) Save() error {
 		return fmt.Errorf("创建配置目录失败: %w", err)
 	}
 	
+	c.Version = CurrentVersion
 	c.UpdatedAt = time.Now()
 	cachePath := GetCachePath()
-	data, err := json.MarshalIndent(c, "", "  ")
+	data, err := c.marshal(c.Format)
 	if err != nil {
 		return fmt.Errorf("序列化缓存失败: %w", err)
 	}
 	
-	if err := os.WriteFile(cachePath, data, 0644); err != nil {
+	if err := rotateBackups(cachePath); err != nil {
+		return fmt.Errorf("备份缓存文件失败: %w", err)
+	}
+	
+	if err := fsutil.WriteFileAtomic(cachePath, data, 0644); err != nil {
 		return fmt.Errorf("写入缓存文件失败: %w", err)
 	}
 	
 	return nil
 }
 
-// Add 添加仓库到缓存
+// rotateBackups 将当前缓存文件保存为最新备份，超出 Backups 的旧备份被丢弃
+func rotateBackups(cachePath string) error {
+	if Backups <= 0 {
+		return nil
+	}
+	
+	// 只备份完整的缓存文件，避免用损坏的内容覆盖可用的备份
+	data, err := os.ReadFile(cachePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !json.Valid(data) {
+		return nil
+	}
+	
+	for i := Backups - 1; i >= 1; i-- {
+		err := os.Rename(backupPath(cachePath, i), backupPath(cachePath, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	
+	return fsutil.WriteFileAtomic(backupPath(cachePath, 1), data, 0644)
+}
+
+// Add 添加仓库到缓存，已存在时合并到原记录
 func (c *Cache) Add(repo Repository) {
 	// 检查是否已存在
 	for i, existing := range c.Repositories {
 		if existing.Path == repo.Path {
 			// 更新现有记录
-			c.Repositories[i] = repo
+			c.Repositories[i] = merge(existing, repo)
 			return
 		}
 	}
 	
-	// 添加新记录
-	repo.AddedAt = time.Now()
+	// 添加新记录，保留调用方提供的添加时间
+	if repo.AddedAt.IsZero() {
+		repo.AddedAt = time.Now()
+	}
 	c.Repositories = append(c.Repositories, repo)
 }
 
+// merge 用新的仓库信息更新已有记录，保留原记录的添加时间，以及新记录中没有的标签、描述和访问记录，
+// 避免重新导入或登记已管理的仓库时丢失用户维护的数据
+func merge(existing, repo Repository) Repository {
+	if !existing.AddedAt.IsZero() {
+		repo.AddedAt = existing.AddedAt
+	}
+	if repo.Visits == 0 {
+		repo.Visits = existing.Visits
+	}
+	if repo.LastVisitedAt.IsZero() {
+		repo.LastVisitedAt = existing.LastVisitedAt
+	}
+	if len(repo.Tags) == 0 {
+		repo.Tags = existing.Tags
+	}
+	if repo.Description == "" {
+		repo.Description = existing.Description
+	}
+	return repo
+}
+
+// Visit 记录一次对仓库的访问，仓库不存在时返回 false
+func (c *Cache) Visit(path string, at time.Time) bool {
+	for i := range c.Repositories {
+		if c.Repositories[i].Path == path {
+			c.Repositories[i].Visits++
+			c.Repositories[i].LastVisitedAt = at
+			return true
+		}
+	}
+	return false
+}
+
 // Remove 从缓存中移除仓库
 func (c *Cache) Remove(path string) bool {
 	for i, repo := range c.Repositories {
@@ -142,24 +300,13 @@ func (c *Cache) Remove(path string) bool {
 	return false
 }
 
-// Find 查找仓库
+// Find 查找仓库，结果按匹配程度排序
 func (c *Cache) Find(query string) []Repository {
-	if query == "" {
-		return c.Repositories
+	matches := NewIndex(c.Repositories).Search(query)
+	results := make([]Repository, 0, len(matches))
+	for _, m := range matches {
+		results = append(results, m.Repo)
 	}
-	
-	var results []Repository
-	query = strings.ToLower(query)
-	
-	for _, repo := range c.Repositories {
-		// 支持多种匹配方式
-		if strings.Contains(strings.ToLower(repo.Name), query) ||
-			strings.Contains(strings.ToLower(repo.Path), query) ||
-			strings.Contains(strings.ToLower(repo.URL), query) {
-			results = append(results, repo)
-		}
-	}
-	
 	return results
 }
 
@@ -173,6 +320,24 @@ func (c *Cache) GetByPath(path string) *Repository {
 	return nil
 }
 
+// GetByDir 获取包含指定目录的仓库，存在嵌套时返回最深的一个
+func (c *Cache) GetByDir(dir string) *Repository {
+	dir = filepath.Clean(dir)
+	
+	var found *Repository
+	for i, repo := range c.Repositories {
+		repoPath := filepath.Clean(repo.Path)
+		if dir != repoPath && !strings.HasPrefix(dir, repoPath+string(filepath.Separator)) {
+			continue
+		}
+		if found == nil || len(repoPath) > len(found.Path) {
+			r := c.Repositories[i]
+			found = &r
+		}
+	}
+	return found
+}
+
 // extractRepoName 从路径中提取仓库名称
 func extractRepoName(path string) string {
 	parts := strings.Split(path, "/")
@@ -182,20 +347,16 @@ func extractRepoName(path string) string {
 	return ""
 }
 
-// extractPlatform 从 URL 中提取平台信息
+// extractPlatform 从 URL 中提取平台信息，与其他仓库的 Platform 一致为主机名，如 github.com
 func extractPlatform(url string) string {
-	if strings.Contains(url, "github.com") {
-		return "github"
-	} else if strings.Contains(url, "code.byted.org") {
-		return "byted"
-	} else if strings.Contains(url, "git.byted.org") {
-		return "byted"
-	}
-	return "unknown"
+	host, _, _ := strings.Cut(CanonicalURL(url), "/")
+	// ssh://git@host:port/owner/repo 中的端口不属于平台
+	host, _, _ = strings.Cut(host, ":")
+	return host
 }
 
-// Sync 同步缓存与文件系统
-func (c *Cache) Sync() (int, int, error) {
+// Sync 同步缓存与文件系统：移除目录已不存在的仓库，添加扫描发现的新仓库
+func (c *Cache) Sync(discovered []Repository) (int, int, error) {
 	var removed, added int
 	
 	// 检查缓存中的仓库是否还存在
@@ -209,8 +370,14 @@ func (c *Cache) Sync() (int, int, error) {
 	}
 	c.Repositories = validRepos
 	
-	// TODO: 扫描文件系统中的新仓库
-	// 这里可以实现扫描基础目录下的所有 .git 目录
+	// 添加新发现的仓库
+	for _, repo := range discovered {
+		if c.GetByPath(repo.Path) != nil {
+			continue
+		}
+		c.Add(repo)
+		added++
+	}
 	
 	return added, removed, nil
 }
\ No newline at end of file
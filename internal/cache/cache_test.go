@@ -2,8 +2,11 @@ package cache
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 )
@@ -315,4 +318,247 @@ func TestGetCachePath(t *testing.T) {
 	if cachePath != expected {
 		t.Errorf("Expected cache path %s, got %s", expected, cachePath)
 	}
+}
+
+func TestCacheGetByDir(t *testing.T) {
+	cache := &Cache{
+		Repositories: []Repository{
+			{
+				Name: "repo1",
+				Path: "/path/to/repo1",
+			},
+			{
+				Name: "nested",
+				Path: "/path/to/repo1/vendor/nested",
+			},
+			{
+				Name: "repo10",
+				Path: "/path/to/repo10",
+			},
+		},
+		UpdatedAt: time.Now(),
+	}
+	
+	tests := []struct {
+		dir      string
+		expected string
+	}{
+		{"/path/to/repo1", "repo1"},
+		{"/path/to/repo1/src/pkg", "repo1"},
+		{"/path/to/repo1/vendor/nested/lib", "nested"},
+		{"/path/to/repo10/", "repo10"},
+		{"/path/to", ""},
+		{"/path/to/repo", ""},
+	}
+	
+	for _, tt := range tests {
+		repo := cache.GetByDir(tt.dir)
+		if tt.expected == "" {
+			if repo != nil {
+				t.Errorf("Expected no repo for %s, got %s", tt.dir, repo.Name)
+			}
+			continue
+		}
+		if repo == nil || repo.Name != tt.expected {
+			t.Errorf("Expected repo %s for %s, got %v", tt.expected, tt.dir, repo)
+		}
+	}
+}
+
+func TestCacheSync(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "projj-cache-sync-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	existing := filepath.Join(tempDir, "existing")
+	if err := os.MkdirAll(filepath.Join(existing, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create repo: %v", err)
+	}
+	
+	cache := &Cache{
+		Repositories: []Repository{
+			{Name: "existing", Path: existing},
+			{Name: "gone", Path: filepath.Join(tempDir, "gone")},
+		},
+		UpdatedAt: time.Now(),
+	}
+	
+	discovered := []Repository{
+		{Name: "existing", Path: existing},
+		{Name: "new", Path: filepath.Join(tempDir, "new")},
+	}
+	
+	added, removed, err := cache.Sync(discovered)
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	
+	if added != 1 || removed != 1 {
+		t.Errorf("Expected 1 added and 1 removed, got %d added and %d removed", added, removed)
+	}
+	
+	if cache.GetByPath(filepath.Join(tempDir, "new")) == nil {
+		t.Error("Discovered repository should be added")
+	}
+	
+	if cache.GetByPath(filepath.Join(tempDir, "gone")) != nil {
+		t.Error("Missing repository should be removed")
+	}
+}
+
+func TestCacheUpdateConcurrent(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "projj-cache-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	originalConfigDir := os.Getenv("PROJJ_CONFIG_DIR")
+	os.Setenv("PROJJ_CONFIG_DIR", tempDir)
+	defer os.Setenv("PROJJ_CONFIG_DIR", originalConfigDir)
+	
+	// 并发修改时每个仓库都应被保留
+	const count = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, count)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := Update("", func(c *Cache) error {
+				c.Add(Repository{
+					Name: fmt.Sprintf("repo-%d", i),
+					Path: fmt.Sprintf("/path/to/repo-%d", i),
+				})
+				return nil
+			})
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Update() failed: %v", err)
+		}
+	}
+	
+	cache, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(cache.Repositories) != count {
+		t.Errorf("Expected %d repositories, got %d", count, len(cache.Repositories))
+	}
+	if _, err := os.Stat(GetCachePath() + ".lock"); !os.IsNotExist(err) {
+		t.Error("Lock file should be removed after update")
+	}
+}
+
+func TestCacheBackupAndRecover(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "projj-cache-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	originalConfigDir := os.Getenv("PROJJ_CONFIG_DIR")
+	os.Setenv("PROJJ_CONFIG_DIR", tempDir)
+	defer os.Setenv("PROJJ_CONFIG_DIR", originalConfigDir)
+	
+	// 多次保存，只保留最近的 Backups 个备份
+	cache := &Cache{}
+	for i := 0; i < Backups+2; i++ {
+		cache.Add(Repository{Name: fmt.Sprintf("repo-%d", i), Path: fmt.Sprintf("/path/to/repo-%d", i)})
+		if err := cache.Save(); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+	
+	cachePath := GetCachePath()
+	for i := 1; i <= Backups; i++ {
+		if _, err := os.Stat(backupPath(cachePath, i)); err != nil {
+			t.Errorf("Backup %d should exist: %v", i, err)
+		}
+	}
+	if _, err := os.Stat(backupPath(cachePath, Backups+1)); !os.IsNotExist(err) {
+		t.Error("Backups beyond the limit should be discarded")
+	}
+	
+	// 缓存文件损坏时从最新的备份恢复
+	if err := os.WriteFile(cachePath, []byte(`{"repositories": [`), 0644); err != nil {
+		t.Fatalf("Failed to corrupt cache: %v", err)
+	}
+	
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() should recover from backup: %v", err)
+	}
+	if loaded.Recovered == "" {
+		t.Error("Recovered should describe the restore")
+	}
+	if len(loaded.Repositories) != Backups+1 {
+		t.Errorf("Expected %d repositories from newest backup, got %d", Backups+1, len(loaded.Repositories))
+	}
+	
+	// 恢复后的缓存文件可以正常加载
+	loaded, err = Load()
+	if err != nil || loaded.Recovered != "" {
+		t.Errorf("Restored cache should load cleanly: %v", err)
+	}
+	
+	// 多个进程同时加载损坏的缓存时只恢复一次，都能读到恢复后的内容
+	if err := os.WriteFile(cachePath, []byte(`{"repositories": [`), 0644); err != nil {
+		t.Fatalf("Failed to corrupt cache: %v", err)
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loaded, err := Load()
+			if err == nil && len(loaded.Repositories) != Backups+1 {
+				err = fmt.Errorf("expected %d repositories, got %d", Backups+1, len(loaded.Repositories))
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Concurrent Load() failed: %v", err)
+		}
+	}
+	
+	// 没有可用备份时返回 ErrCorrupt
+	for i := 1; i <= Backups; i++ {
+		os.Remove(backupPath(cachePath, i))
+	}
+	if err := os.WriteFile(cachePath, nil, 0644); err != nil {
+		t.Fatalf("Failed to corrupt cache: %v", err)
+	}
+	if _, err := Load(); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt, got %v", err)
+	}
+	if _, err := os.Stat(cachePath + ".corrupt"); err != nil {
+		t.Errorf("Corrupt cache should be kept: %v", err)
+	}
+}
+
+func TestExtractPlatform(t *testing.T) {
+	tests := map[string]string{
+		"git@github.com:user/repo.git":            "github.com",
+		"https://GitLab.com/team/repo":            "gitlab.com",
+		"ssh://git@git.example.com:2222/team/api": "git.example.com",
+	}
+	for url, expected := range tests {
+		if got := extractPlatform(url); got != expected {
+			t.Errorf("extractPlatform(%q) = %q, expected %q", url, got, expected)
+		}
+	}
 }
\ No newline at end of file
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/atian25/projj-go/internal/config"
+)
+
+// nodeVersion 原版 projj 缓存文件中的版本标记
+const nodeVersion = "v1"
+
+// nodeEntry 表示原版 projj 缓存中的一项，both 格式下额外保留 projj-go 的字段
+type nodeEntry struct {
+	Repo          string     `json:"repo"`
+	Name          string     `json:"name,omitempty"`
+	Platform      string     `json:"platform,omitempty"`
+	AddedAt       *time.Time `json:"added_at,omitempty"`
+	Visits        int        `json:"visits,omitempty"`
+	LastVisitedAt *time.Time `json:"last_visited_at,omitempty"`
+	Tags          []string   `json:"tags,omitempty"`
+	Description   string     `json:"description,omitempty"`
+}
+
+// marshal 按指定格式序列化缓存，空格式为 projj-go 格式
+func (c *Cache) marshal(format string) ([]byte, error) {
+	if format == "" || format == config.CacheFormatGo {
+		return json.MarshalIndent(c, "", "  ")
+	}
+	
+	doc := make(map[string]interface{}, len(c.Repositories)+1)
+	for _, repo := range c.Repositories {
+		entry := nodeEntry{Repo: repo.URL}
+		if format == config.CacheFormatBoth {
+			entry.Name = repo.Name
+			entry.Platform = repo.Platform
+			if !repo.AddedAt.IsZero() {
+				addedAt := repo.AddedAt
+				entry.AddedAt = &addedAt
+			}
+			entry.Visits = repo.Visits
+			if !repo.LastVisitedAt.IsZero() {
+				lastVisitedAt := repo.LastVisitedAt
+				entry.LastVisitedAt = &lastVisitedAt
+			}
+			entry.Tags = repo.Tags
+			entry.Description = repo.Description
+		}
+		doc[repo.Path] = entry
+	}
+	doc["version"] = nodeVersion
+	
+	return json.MarshalIndent(doc, "", "  ")
+}
\ No newline at end of file
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/atian25/projj-go/internal/config"
+)
+
+func TestCacheFormatRoundTrip(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "projj-cache-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	originalConfigDir := os.Getenv("PROJJ_CONFIG_DIR")
+	os.Setenv("PROJJ_CONFIG_DIR", tempDir)
+	defer os.Setenv("PROJJ_CONFIG_DIR", originalConfigDir)
+	
+	addedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	repo := Repository{
+		Name:        "custom-name",
+		URL:         "git@github.com:user/repo.git",
+		Path:        "/path/to/github.com/user/repo",
+		Platform:    "github.com",
+		AddedAt:     addedAt,
+		Tags:        []string{"infra", "payments"},
+		Description: "支付网关",
+	}
+	
+	tests := []struct {
+		format   string
+		nodeKeys bool // 是否以路径为键
+		keepMeta bool // 是否保留 projj-go 的字段
+	}{
+		{config.CacheFormatGo, false, true},
+		{config.CacheFormatNode, true, false},
+		{config.CacheFormatBoth, true, true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			cache := &Cache{Format: tt.format}
+			cache.Add(repo)
+			if err := cache.Save(); err != nil {
+				t.Fatalf("Save() failed: %v", err)
+			}
+			
+			data, err := os.ReadFile(GetCachePath())
+			if err != nil {
+				t.Fatalf("Failed to read cache: %v", err)
+			}
+			var doc map[string]json.RawMessage
+			if err := json.Unmarshal(data, &doc); err != nil {
+				t.Fatalf("Cache file is not valid JSON: %v", err)
+			}
+			
+			if tt.nodeKeys {
+				// 原版 projj 只读取 repo 字段
+				var entry struct {
+					Repo string `json:"repo"`
+				}
+				if err := json.Unmarshal(doc[repo.Path], &entry); err != nil || entry.Repo != repo.URL {
+					t.Errorf("Expected path-keyed entry with repo %s, got %s", repo.URL, doc[repo.Path])
+				}
+				if _, ok := doc["version"]; !ok {
+					t.Error("Node format should contain version")
+				}
+			} else if _, ok := doc["repositories"]; !ok {
+				t.Error("projj-go format should contain repositories")
+			}
+			
+			loaded, err := Load()
+			if err != nil {
+				t.Fatalf("Load() failed: %v", err)
+			}
+			if len(loaded.Repositories) != 1 {
+				t.Fatalf("Expected 1 repository, got %d", len(loaded.Repositories))
+			}
+			
+			got := loaded.Repositories[0]
+			if got.Path != repo.Path || got.URL != repo.URL {
+				t.Errorf("Path or URL lost in round trip: %+v", got)
+			}
+			if tt.keepMeta {
+				if got.Name != repo.Name || got.Platform != repo.Platform || !got.AddedAt.Equal(addedAt) ||
+					len(got.Tags) != 2 || got.Description != repo.Description {
+					t.Errorf("Metadata lost in round trip: %+v", got)
+				}
+			} else if got.Name != "repo" || got.Platform != repo.Platform || !got.AddedAt.IsZero() {
+				// 原版格式没有添加时间，名称和平台按路径和地址推断
+				t.Errorf("Expected metadata derived from path and URL, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestJSONStoreFormat(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "projj-cache-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	originalConfigDir := os.Getenv("PROJJ_CONFIG_DIR")
+	os.Setenv("PROJJ_CONFIG_DIR", tempDir)
+	defer os.Setenv("PROJJ_CONFIG_DIR", originalConfigDir)
+	
+	// 写入格式由打开存储的调用方指定，不读取配置
+	store, err := OpenJSON(config.CacheFormatNode)
+	if err != nil {
+		t.Fatalf("OpenJSON() failed: %v", err)
+	}
+	if err := store.Put(Repository{Name: "repo", URL: "git@github.com:user/repo.git", Path: "/path/to/repo"}); err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+	
+	data, err := os.ReadFile(GetCachePath())
+	if err != nil {
+		t.Fatalf("Failed to read cache: %v", err)
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Cache file is not valid JSON: %v", err)
+	}
+	if _, ok := doc["/path/to/repo"]; !ok {
+		t.Errorf("Expected node format keyed by path, got %s", data)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"sort"
+	"time"
+)
+
+// Frecency 按访问次数和最近访问时间计算仓库的常用程度，算法与 zoxide 相同
+func Frecency(repo Repository, now time.Time) float64 {
+	if repo.Visits == 0 || repo.LastVisitedAt.IsZero() {
+		return 0
+	}
+	
+	visits := float64(repo.Visits)
+	switch age := now.Sub(repo.LastVisitedAt); {
+	case age < time.Hour:
+		return visits * 4
+	case age < 24*time.Hour:
+		return visits * 2
+	case age < 7*24*time.Hour:
+		return visits / 2
+	default:
+		return visits / 4
+	}
+}
+
+// SortByFrecency 按常用程度从高到低排序匹配结果，相同时保持匹配分数的顺序
+func SortByFrecency(matches []Match, now time.Time) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return Frecency(matches[i].Repo, now) > Frecency(matches[j].Repo, now)
+	})
+}
\ No newline at end of file
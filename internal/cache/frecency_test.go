@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFrecency(t *testing.T) {
+	now := time.Now()
+	
+	tests := []struct {
+		visits   int
+		age      time.Duration
+		expected float64
+	}{
+		{0, 0, 0},
+		{3, 10 * time.Minute, 12},
+		{3, 5 * time.Hour, 6},
+		{4, 3 * 24 * time.Hour, 2},
+		{4, 30 * 24 * time.Hour, 1},
+	}
+	
+	for _, tt := range tests {
+		repo := Repository{Visits: tt.visits, LastVisitedAt: now.Add(-tt.age)}
+		if got := Frecency(repo, now); got != tt.expected {
+			t.Errorf("Frecency(visits=%d, age=%v) = %v, expected %v", tt.visits, tt.age, got, tt.expected)
+		}
+	}
+}
+
+func TestSortByFrecency(t *testing.T) {
+	now := time.Now()
+	matches := []Match{
+		{Repo: Repository{Name: "exact"}, Kind: MatchExactName},
+		{Repo: Repository{Name: "old", Visits: 20, LastVisitedAt: now.Add(-60 * 24 * time.Hour)}, Kind: MatchPrefix},
+		{Repo: Repository{Name: "hot", Visits: 3, LastVisitedAt: now.Add(-time.Minute)}, Kind: MatchContains},
+	}
+	
+	SortByFrecency(matches, now)
+	
+	names := []string{matches[0].Repo.Name, matches[1].Repo.Name, matches[2].Repo.Name}
+	if names[0] != "hot" || names[1] != "old" || names[2] != "exact" {
+		t.Errorf("Unexpected order: %v", names)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"sort"
+	"strings"
+)
+
+// MatchKind 表示匹配类型，值越大越相关
+type MatchKind int
+
+const (
+	MatchFuzzy     MatchKind = iota + 1 // 查询词是名称或 owner/name 的子序列
+	MatchContains                       // 名称、路径或 URL 包含查询词
+	MatchWord                           // 查询词出现在名称、owner/name 或路径的单词边界
+	MatchPrefix                         // 名称或 owner/name 以查询词开头
+	MatchExactName                      // 名称与查询词相同
+	MatchExact                          // owner/name、路径或 URL 与查询词相同
+)
+
+// kindWeight 不同匹配类型之间的分数间隔，同类型内按长度、位置等扣分
+const kindWeight = 1000
+
+// Match 表示带评分的匹配结果
+type Match struct {
+	Repo  Repository
+	Kind  MatchKind
+	Score int
+}
+
+// Index 表示内存中的搜索索引，预先计算好小写的名称、owner/name、路径和规范化 URL
+type Index struct {
+	entries []indexEntry
+}
+
+// indexEntry 表示索引中的一个仓库
+type indexEntry struct {
+	repo      Repository
+	name      string
+	ownerName string
+	path      string
+	url       string
+	canonical string
+}
+
+// NewIndex 为仓库列表建立搜索索引
+func NewIndex(repos []Repository) *Index {
+	idx := &Index{entries: make([]indexEntry, 0, len(repos))}
+	for _, repo := range repos {
+		canonical := CanonicalURL(repo.URL)
+		idx.entries = append(idx.entries, indexEntry{
+			repo:      repo,
+			name:      strings.ToLower(repo.Name),
+			ownerName: ownerName(canonical, strings.ToLower(repo.Path)),
+			path:      strings.ToLower(repo.Path),
+			url:       strings.ToLower(repo.URL),
+			canonical: canonical,
+		})
+	}
+	return idx
+}
+
+// Search 返回匹配查询的仓库，按分数从高到低排序，分数相同时按路径排序；空查询按原顺序返回全部
+func (idx *Index) Search(query string) []Match {
+	q := strings.ToLower(strings.TrimSpace(query))
+	
+	var matches []Match
+	for _, e := range idx.entries {
+		if q == "" {
+			matches = append(matches, Match{Repo: e.repo})
+			continue
+		}
+		if m, ok := e.match(q); ok {
+			matches = append(matches, m)
+		}
+	}
+	
+	if q != "" {
+		sort.SliceStable(matches, func(i, j int) bool {
+			if matches[i].Score != matches[j].Score {
+				return matches[i].Score > matches[j].Score
+			}
+			return matches[i].Repo.Path < matches[j].Repo.Path
+		})
+	}
+	return matches
+}
+
+// ClearWinner 返回明显优于其他结果的匹配：只有一个结果，或第一名的匹配类型高于第二名
+func ClearWinner(matches []Match) (*Match, bool) {
+	if len(matches) == 0 {
+		return nil, false
+	}
+	if len(matches) == 1 || matches[0].Kind > matches[1].Kind {
+		return &matches[0], true
+	}
+	return nil, false
+}
+
+// match 计算仓库与小写查询词的匹配类型和分数
+func (e *indexEntry) match(q string) (Match, bool) {
+	kind, penalty := e.classify(q)
+	if kind == 0 {
+		return Match{}, false
+	}
+	if penalty > kindWeight-1 {
+		penalty = kindWeight - 1
+	}
+	return Match{Repo: e.repo, Kind: kind, Score: int(kind)*kindWeight - penalty}, true
+}
+
+// classify 返回匹配类型和同类型内的扣分，不匹配时类型为 0
+func (e *indexEntry) classify(q string) (MatchKind, int) {
+	switch {
+	case q == e.ownerName || q == e.path || CanonicalURL(q) == e.canonical:
+		return MatchExact, 0
+	case q == e.name:
+		return MatchExactName, 0
+	case strings.HasPrefix(e.name, q):
+		return MatchPrefix, len(e.name) - len(q)
+	case strings.HasPrefix(e.ownerName, q):
+		return MatchPrefix, len(e.ownerName) - len(q)
+	}
+	
+	for _, s := range []string{e.name, e.ownerName, e.path} {
+		if pos := wordIndex(s, q); pos >= 0 {
+			return MatchWord, pos + len(s) - len(q)
+		}
+	}
+	
+	for _, s := range []string{e.name, e.path, e.url} {
+		if pos := strings.Index(s, q); pos >= 0 {
+			return MatchContains, pos + len(s) - len(q)
+		}
+	}
+	
+	for _, s := range []string{e.name, e.ownerName} {
+		if gaps, ok := subsequence(s, q); ok {
+			return MatchFuzzy, gaps
+		}
+	}
+	return 0, 0
+}
+
+// wordIndex 返回查询词在单词边界处出现的位置，没有时返回 -1
+func wordIndex(s, q string) int {
+	for start := 0; start < len(s); {
+		pos := strings.Index(s[start:], q)
+		if pos < 0 {
+			return -1
+		}
+		pos += start
+		if pos == 0 || strings.ContainsRune("-_./ ", rune(s[pos-1])) {
+			return pos
+		}
+		start = pos + 1
+	}
+	return -1
+}
+
+// subsequence 检查查询词是否按顺序出现在字符串中，返回字符之间跳过的总长度
+func subsequence(s, q string) (int, bool) {
+	gaps, last := 0, -1
+	i := 0
+	for j := 0; j < len(s) && i < len(q); j++ {
+		if s[j] != q[i] {
+			continue
+		}
+		if last >= 0 {
+			gaps += j - last - 1
+		}
+		last = j
+		i++
+	}
+	return gaps, i == len(q)
+}
+
+// ownerName 返回仓库的 owner/name，优先取规范化 URL 的最后两段，没有 URL 时取路径
+func ownerName(canonical, path string) string {
+	source := canonical
+	if source == "" {
+		source = strings.ReplaceAll(path, "\\", "/")
+	}
+	parts := strings.Split(strings.Trim(source, "/"), "/")
+	if len(parts) < 2 {
+		return strings.Join(parts, "/")
+	}
+	return parts[len(parts)-2] + "/" + parts[len(parts)-1]
+}
\ No newline at end of file
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"testing"
+)
+
+func matchRepos() []Repository {
+	return []Repository{
+		{Name: "django", Path: "/projj/github.com/django/django", URL: "git@github.com:django/django.git"},
+		{Name: "go-toolkit", Path: "/projj/github.com/user/go-toolkit", URL: "git@github.com:user/go-toolkit.git"},
+		{Name: "go", Path: "/projj/github.com/golang/go", URL: "https://github.com/golang/go.git"},
+		{Name: "gopls", Path: "/projj/github.com/golang/gopls", URL: "https://github.com/golang/gopls.git"},
+		{Name: "my-go-lib", Path: "/projj/github.com/user/my-go-lib", URL: "git@github.com:user/my-go-lib.git"},
+		{Name: "gitlab-ops", Path: "/projj/gitlab.com/team/gitlab-ops", URL: "git@gitlab.com:team/gitlab-ops.git"},
+	}
+}
+
+func TestIndexSearchRanking(t *testing.T) {
+	idx := NewIndex(matchRepos())
+	
+	matches := idx.Search("go")
+	expected := []struct {
+		name string
+		kind MatchKind
+	}{
+		{"go", MatchExactName},
+		{"gopls", MatchPrefix},
+		{"go-toolkit", MatchPrefix},
+		{"my-go-lib", MatchWord},
+		{"django", MatchContains},
+	}
+	if len(matches) < len(expected) {
+		t.Fatalf("Expected at least %d matches, got %d", len(expected), len(matches))
+	}
+	for i, e := range expected {
+		if matches[i].Repo.Name != e.name || matches[i].Kind != e.kind {
+			t.Errorf("Match %d: expected %s (%d), got %s (%d)", i, e.name, e.kind, matches[i].Repo.Name, matches[i].Kind)
+		}
+	}
+	
+	// owner/name 和 URL 完全匹配
+	for _, query := range []string{"golang/go", "git@github.com:golang/go.git", "GOLANG/GO"} {
+		matches := idx.Search(query)
+		if len(matches) == 0 || matches[0].Repo.Name != "go" || matches[0].Kind != MatchExact {
+			t.Errorf("Search(%q) should rank golang/go first as exact match, got %+v", query, matches)
+		}
+	}
+	
+	// 子序列模糊匹配
+	matches = idx.Search("gtlbops")
+	if len(matches) != 1 || matches[0].Repo.Name != "gitlab-ops" || matches[0].Kind != MatchFuzzy {
+		t.Errorf("Expected fuzzy match on gitlab-ops, got %+v", matches)
+	}
+	
+	// 空查询保持原顺序
+	if matches := idx.Search(""); len(matches) != 6 || matches[0].Repo.Name != "django" {
+		t.Errorf("Empty query should return all repositories in order, got %+v", matches)
+	}
+}
+
+func TestClearWinner(t *testing.T) {
+	idx := NewIndex(matchRepos())
+	
+	if winner, ok := ClearWinner(idx.Search("go")); !ok || winner.Repo.Name != "go" {
+		t.Errorf("Exact name match should be the clear winner, got %+v", winner)
+	}
+	
+	// 唯一的前缀匹配优于模糊匹配
+	if winner, ok := ClearWinner(idx.Search("gop")); !ok || winner.Repo.Name != "gopls" {
+		t.Errorf("Only prefix match should win over fuzzy matches, got %+v", winner)
+	}
+	
+	// 两个同类型的前缀匹配无法区分
+	repos := append(matchRepos(), Repository{Name: "gopher", Path: "/projj/github.com/user/gopher"})
+	if _, ok := ClearWinner(NewIndex(repos).Search("gop")); ok {
+		t.Error("Two prefix matches should not have a clear winner")
+	}
+	
+	if _, ok := ClearWinner(nil); ok {
+		t.Error("No matches should not have a winner")
+	}
+}
\ No newline at end of file
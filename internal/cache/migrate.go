@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atian25/projj-go/internal/config"
+)
+
+// CurrentVersion 当前缓存格式版本：0 为原版 projj 格式，1 为没有 version 字段的早期格式，2 增加 version 字段，3 增加访问记录，4 增加标签和描述
+const CurrentVersion = 4
+
+// ErrUnsupportedVersion 表示缓存文件由更新版本的 projj 写入，不能当作损坏的文件恢复或重建
+var ErrUnsupportedVersion = errors.New("不支持的缓存版本")
+
+// migration 表示从 from 版本升级到 from+1 版本的迁移
+type migration struct {
+	from        int
+	description string
+	apply       func(doc map[string]json.RawMessage) (map[string]json.RawMessage, error)
+}
+
+// migrations 按版本顺序排列的迁移链，修改 Repository 或 Cache 的结构时在末尾追加
+var migrations = []migration{
+	{0, "将原版 projj 格式转换为仓库列表", migrateNodeFormat},
+	{1, "增加 version 字段", keepFields},
+	{2, "增加访问次数和最后访问时间", keepFields},
+	{3, "增加标签和描述", keepFields},
+}
+
+// MigrateResult 表示缓存文件迁移的结果
+type MigrateResult struct {
+	Path         string
+	From         int
+	To           int
+	Steps        []string
+	Repositories int
+}
+
+// Migrate 将缓存文件升级到当前版本并按 format 写回，dryRun 时只检查不写入
+func Migrate(format string, dryRun bool) (*MigrateResult, error) {
+	cachePath := GetCachePath()
+	result := &MigrateResult{Path: cachePath, From: CurrentVersion, To: CurrentVersion}
+	
+	if _, err := os.Stat(cachePath); os.IsNotExist(err) {
+		return result, nil
+	}
+	
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取缓存文件失败: %w", err)
+	}
+	_, version, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := migrate(data)
+	if err != nil {
+		return nil, err
+	}
+	
+	result.From = version
+	result.Steps = pendingSteps(version)
+	result.Repositories = len(cache.Repositories)
+	
+	// 按配置写入原版格式时，目标版本即原版格式
+	if format != "" && format != config.CacheFormatGo {
+		result.To = 0
+		result.Steps = nil
+		if version != 0 {
+			result.Steps = []string{fmt.Sprintf("v%d -> v0: 按 cache_format 转换为 %s 格式", version, format)}
+		}
+	}
+	if dryRun || len(result.Steps) == 0 {
+		return result, nil
+	}
+	
+	// Load 会自动迁移，重新保存即写入当前版本，原文件保留在备份中
+	if _, err := Update(format, func(*Cache) error { return nil }); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// pendingSteps 返回从指定版本升级到当前版本需要执行的迁移说明
+func pendingSteps(version int) []string {
+	var steps []string
+	for _, m := range migrations[version:] {
+		steps = append(steps, fmt.Sprintf("v%d -> v%d: %s", m.from, m.from+1, m.description))
+	}
+	return steps
+}
+
+// decode 解析缓存内容并识别其格式版本
+func decode(data []byte) (map[string]json.RawMessage, int, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, 0, fmt.Errorf("解析缓存文件失败: %w", err)
+	}
+	
+	// 原版 projj 格式的键都是仓库路径，不会出现 repositories
+	if _, ok := doc["repositories"]; !ok {
+		return doc, 0, nil
+	}
+	
+	raw, ok := doc["version"]
+	if !ok {
+		return doc, 1, nil
+	}
+	
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil {
+		return nil, 0, fmt.Errorf("无法识别的缓存版本: %s", raw)
+	}
+	if version < 1 {
+		return nil, 0, fmt.Errorf("无法识别的缓存版本: %d", version)
+	}
+	if version > CurrentVersion {
+		return nil, 0, fmt.Errorf("%w: 缓存文件版本 %d 高于当前支持的版本 %d，请升级 projj", ErrUnsupportedVersion, version, CurrentVersion)
+	}
+	return doc, version, nil
+}
+
+// migrate 依次执行迁移，将缓存内容升级到当前版本
+func migrate(data []byte) (*Cache, error) {
+	doc, version, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	
+	for _, m := range migrations[version:] {
+		if doc, err = m.apply(doc); err != nil {
+			return nil, fmt.Errorf("迁移缓存 v%d -> v%d 失败: %w", m.from, m.from+1, err)
+		}
+	}
+	doc["version"] = json.RawMessage(fmt.Sprint(CurrentVersion))
+	
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("序列化缓存失败: %w", err)
+	}
+	
+	var cache Cache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("解析缓存文件失败: %w", err)
+	}
+	if cache.Repositories == nil {
+		cache.Repositories = make([]Repository, 0)
+	}
+	return &cache, nil
+}
+
+// keepFields 用于只增加可选字段的迁移，已有数据无需转换
+func keepFields(doc map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	return doc, nil
+}
+
+// migrateNodeFormat 将原版 projj 以路径为键的格式转换为仓库列表
+func migrateNodeFormat(doc map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	paths := make([]string, 0, len(doc))
+	for path := range doc {
+		// 跳过 version 等非路径字段
+		if path == "version" || !strings.ContainsAny(path, `/\`) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	
+	repos := make([]Repository, 0, len(paths))
+	for _, path := range paths {
+		var entry nodeEntry
+		if err := json.Unmarshal(doc[path], &entry); err != nil || entry.Repo == "" {
+			continue
+		}
+		
+		// both 格式会保留 projj-go 的字段，原版格式缺失的字段按路径和 URL 推断
+		repo := Repository{
+			Path:     path,
+			URL:      entry.Repo,
+			Name:     entry.Name,
+			Platform: entry.Platform,
+		}
+		if repo.Name == "" {
+			repo.Name = extractRepoName(path)
+		}
+		if repo.Platform == "" {
+			repo.Platform = extractPlatform(entry.Repo)
+		}
+		// 原版格式没有记录添加时间，保持为空而不是伪造为当前时间
+		if entry.AddedAt != nil {
+			repo.AddedAt = *entry.AddedAt
+		}
+		repo.Visits = entry.Visits
+		if entry.LastVisitedAt != nil {
+			repo.LastVisitedAt = *entry.LastVisitedAt
+		}
+		repo.Tags = entry.Tags
+		repo.Description = entry.Description
+		repos = append(repos, repo)
+	}
+	
+	reposData, err := json.Marshal(repos)
+	if err != nil {
+		return nil, err
+	}
+	updatedAt, err := json.Marshal(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	
+	return map[string]json.RawMessage{
+		"repositories": reposData,
+		"updated_at":   updatedAt,
+	}, nil
+}
\ No newline at end of file
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestMigrateNodeFormat(t *testing.T) {
+	data := []byte(`{
+  "/home/user/projj/github.com/user/repo": {"repo": "git@github.com:user/repo.git"},
+  "version": "v1"
+}`)
+	
+	cache, err := migrate(data)
+	if err != nil {
+		t.Fatalf("migrate() failed: %v", err)
+	}
+	
+	if cache.Version != CurrentVersion {
+		t.Errorf("Expected version %d, got %d", CurrentVersion, cache.Version)
+	}
+	if len(cache.Repositories) != 1 {
+		t.Fatalf("Expected 1 repository, got %d", len(cache.Repositories))
+	}
+	
+	repo := cache.Repositories[0]
+	if repo.Name != "repo" || repo.URL != "git@github.com:user/repo.git" || repo.Platform != "github.com" {
+		t.Errorf("Unexpected repository: %+v", repo)
+	}
+	// 原版格式没有添加时间，不应伪造
+	if !repo.AddedAt.IsZero() {
+		t.Errorf("Expected zero AddedAt, got %v", repo.AddedAt)
+	}
+}
+
+func TestMigrateVersions(t *testing.T) {
+	// 早期格式的添加时间应保留
+	unversioned := []byte(`{"repositories": [{"name": "repo", "path": "/path/to/repo", "added_at": "2020-01-02T03:04:05Z"}], "updated_at": "2020-01-02T03:04:05Z"}`)
+	cache, err := migrate(unversioned)
+	if err != nil {
+		t.Fatalf("migrate() failed: %v", err)
+	}
+	if cache.Version != CurrentVersion || len(cache.Repositories) != 1 {
+		t.Fatalf("Unexpected cache: %+v", cache)
+	}
+	if cache.Repositories[0].AddedAt.Year() != 2020 {
+		t.Errorf("AddedAt should be preserved, got %v", cache.Repositories[0].AddedAt)
+	}
+	
+	// 空的早期格式不应被误认为原版格式
+	cache, err = migrate([]byte(`{"repositories": [], "updated_at": "2020-01-02T03:04:05Z"}`))
+	if err != nil || len(cache.Repositories) != 0 {
+		t.Errorf("Empty cache should migrate cleanly: %v", err)
+	}
+	
+	// 更高的版本无法识别
+	if _, err := migrate([]byte(`{"version": 99, "repositories": []}`)); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Expected ErrUnsupportedVersion, got %v", err)
+	}
+}
+
+func TestLoadUnsupportedVersion(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "projj-cache-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	originalConfigDir := os.Getenv("PROJJ_CONFIG_DIR")
+	os.Setenv("PROJJ_CONFIG_DIR", tempDir)
+	defer os.Setenv("PROJJ_CONFIG_DIR", originalConfigDir)
+	
+	// 更新版本写入的缓存不能被当作损坏的文件移走
+	newer := []byte(`{"version": 99, "repositories": [{"name": "repo", "path": "/path/to/repo", "tags": ["work"]}]}`)
+	if err := os.WriteFile(GetCachePath(), newer, 0644); err != nil {
+		t.Fatalf("Failed to write cache: %v", err)
+	}
+	if _, err := Load(); !errors.Is(err, ErrUnsupportedVersion) || errors.Is(err, ErrCorrupt) {
+		t.Errorf("Expected ErrUnsupportedVersion, got %v", err)
+	}
+	if _, err := Update("", func(*Cache) error { return nil }); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Update() should fail with ErrUnsupportedVersion, got %v", err)
+	}
+	if data, _ := os.ReadFile(GetCachePath()); string(data) != string(newer) {
+		t.Error("Cache file with a newer version should be left untouched")
+	}
+	if _, err := os.Stat(GetCachePath() + ".corrupt"); !os.IsNotExist(err) {
+		t.Error("Cache file with a newer version should not be treated as corrupt")
+	}
+}
+
+func TestMigrateCommand(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "projj-cache-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	originalConfigDir := os.Getenv("PROJJ_CONFIG_DIR")
+	os.Setenv("PROJJ_CONFIG_DIR", tempDir)
+	defer os.Setenv("PROJJ_CONFIG_DIR", originalConfigDir)
+	
+	original := []byte(`{"/path/to/repo": {"repo": "git@github.com:user/repo.git"}}`)
+	if err := os.WriteFile(GetCachePath(), original, 0644); err != nil {
+		t.Fatalf("Failed to write cache: %v", err)
+	}
+	
+	result, err := Migrate("", true)
+	if err != nil {
+		t.Fatalf("Migrate(dryRun) failed: %v", err)
+	}
+	if result.From != 0 || len(result.Steps) != CurrentVersion || result.Repositories != 1 {
+		t.Errorf("Unexpected dry-run result: %+v", result)
+	}
+	if data, _ := os.ReadFile(GetCachePath()); string(data) != string(original) {
+		t.Error("Dry-run should not modify the cache file")
+	}
+	
+	if _, err := Migrate("", false); err != nil {
+		t.Fatalf("Migrate() failed: %v", err)
+	}
+	data, _ := os.ReadFile(GetCachePath())
+	if _, version, err := decode(data); err != nil || version != CurrentVersion {
+		t.Errorf("Expected migrated file at version %d, got %d (%v)", CurrentVersion, version, err)
+	}
+	
+	result, err = Migrate("", false)
+	if err != nil || len(result.Steps) != 0 {
+		t.Errorf("Migrated cache should need no steps: %+v (%v)", result, err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,195 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atian25/projj-go/internal/config"
+)
+
+// Store 表示仓库缓存的存储后端
+type Store interface {
+	// List 返回所有仓库
+	List() ([]Repository, error)
+	// Find 返回匹配查询的仓库，按匹配程度排序，空查询返回全部
+	Find(query string) ([]Match, error)
+	// GetByPath 根据路径获取仓库，不存在时返回 nil
+	GetByPath(path string) (*Repository, error)
+	// GetByDir 获取包含指定目录的仓库，存在嵌套时返回最深的一个
+	GetByDir(dir string) (*Repository, error)
+	// GetByURL 返回规范化 URL 相同的仓库
+	GetByURL(url string) ([]Repository, error)
+	// Put 添加或更新仓库，已存在时保留原记录的添加时间，以及新记录中没有的标签、描述和访问记录
+	Put(repos ...Repository) error
+	// Remove 移除仓库，返回是否存在
+	Remove(path string) (bool, error)
+	// Visit 记录一次访问，返回仓库是否存在
+	Visit(path string, at time.Time) (bool, error)
+	// Modify 在锁内读取并修改仓库，fn 返回错误时放弃修改，返回仓库是否存在
+	Modify(path string, fn func(repo *Repository) error) (bool, error)
+	// Sync 移除目录已不存在的仓库并添加新发现的仓库
+	Sync(discovered []Repository) (added, removed int, err error)
+	// Notice 返回打开存储时发生的恢复或迁移说明，没有时为空
+	Notice() string
+}
+
+// Open 按配置中的 cache_backend 打开缓存存储，cache.json 按配置中的 cache_format 写入
+func Open() (Store, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("加载配置失败: %w", err)
+	}
+	if !config.ValidCacheFormat(cfg.CacheFormat) {
+		return nil, fmt.Errorf("未知的缓存格式: %s", cfg.CacheFormat)
+	}
+	
+	switch cfg.GetCacheBackend() {
+	case config.CacheBackendJSON:
+		return OpenJSON(cfg.GetCacheFormat())
+	case config.CacheBackendBolt:
+		return OpenBolt(GetDBPath())
+	default:
+		return nil, fmt.Errorf("未知的缓存存储后端: %s", cfg.CacheBackend)
+	}
+}
+
+// JSONStore 基于 cache.json 的存储，每次修改都重写整个文件
+type JSONStore struct {
+	cache  *Cache
+	format string // 写入格式
+	index  *Index // 首次查找时建立，缓存修改后失效
+}
+
+// OpenJSON 加载 cache.json，修改时按 format 写入，为空时为 projj-go 格式
+func OpenJSON(format string) (*JSONStore, error) {
+	cache, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return &JSONStore{cache: cache, format: format}, nil
+}
+
+func (s *JSONStore) List() ([]Repository, error) {
+	return s.cache.Repositories, nil
+}
+
+func (s *JSONStore) Find(query string) ([]Match, error) {
+	if s.index == nil {
+		s.index = NewIndex(s.cache.Repositories)
+	}
+	return s.index.Search(query), nil
+}
+
+func (s *JSONStore) GetByPath(path string) (*Repository, error) {
+	return s.cache.GetByPath(path), nil
+}
+
+func (s *JSONStore) GetByDir(dir string) (*Repository, error) {
+	return s.cache.GetByDir(dir), nil
+}
+
+func (s *JSONStore) GetByURL(url string) ([]Repository, error) {
+	canonical := CanonicalURL(url)
+	var results []Repository
+	for _, repo := range s.cache.Repositories {
+		if CanonicalURL(repo.URL) == canonical {
+			results = append(results, repo)
+		}
+	}
+	return results, nil
+}
+
+func (s *JSONStore) Put(repos ...Repository) error {
+	return s.update(func(c *Cache) error {
+		for _, repo := range repos {
+			c.Add(repo)
+		}
+		return nil
+	})
+}
+
+func (s *JSONStore) Remove(path string) (bool, error) {
+	var removed bool
+	err := s.update(func(c *Cache) error {
+		removed = c.Remove(path)
+		return nil
+	})
+	return removed, err
+}
+
+func (s *JSONStore) Visit(path string, at time.Time) (bool, error) {
+	var visited bool
+	err := s.update(func(c *Cache) error {
+		visited = c.Visit(path, at)
+		return nil
+	})
+	return visited, err
+}
+
+func (s *JSONStore) Modify(path string, fn func(repo *Repository) error) (bool, error) {
+	var found bool
+	err := s.update(func(c *Cache) error {
+		for i := range c.Repositories {
+			if c.Repositories[i].Path == path {
+				found = true
+				if err := fn(&c.Repositories[i]); err != nil {
+					return err
+				}
+				c.Repositories[i].Path = path
+				return nil
+			}
+		}
+		return nil
+	})
+	return found, err
+}
+
+func (s *JSONStore) Sync(discovered []Repository) (int, int, error) {
+	var added, removed int
+	err := s.update(func(c *Cache) error {
+		var err error
+		added, removed, err = c.Sync(discovered)
+		return err
+	})
+	return added, removed, err
+}
+
+func (s *JSONStore) Notice() string {
+	return s.cache.Recovered
+}
+
+// update 在文件锁内重新加载缓存并修改保存，完成后更新持有的缓存
+func (s *JSONStore) update(fn func(*Cache) error) error {
+	cache, err := Update(s.format, fn)
+	if err != nil {
+		return err
+	}
+	s.cache = cache
+	s.index = nil
+	return nil
+}
+
+// CanonicalURL 将仓库 URL 规范化为 host/owner/repo 的形式，用于比较不同协议的同一仓库
+func CanonicalURL(url string) string {
+	u := strings.ToLower(strings.TrimSpace(url))
+	
+	hasScheme := false
+	if i := strings.Index(u, "://"); i >= 0 {
+		u = u[i+3:]
+		hasScheme = true
+	}
+	
+	// 去掉 git@ 等用户名
+	if i := strings.Index(u, "@"); i >= 0 && i < strings.IndexAny(u+"/", ":/") {
+		u = u[i+1:]
+	}
+	
+	// scp 形式的 host:owner/repo
+	if !hasScheme {
+		u = strings.Replace(u, ":", "/", 1)
+	}
+	
+	u = strings.TrimSuffix(strings.TrimSuffix(u, "/"), ".git")
+	return u
+}
\ No newline at end of file
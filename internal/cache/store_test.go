@@ -0,0 +1,339 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/atian25/projj-go/internal/config"
+)
+
+func setupStoreEnv(t testing.TB) (string, func()) {
+	tempDir, err := os.MkdirTemp("", "projj-store-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	
+	originalConfigDir := os.Getenv("PROJJ_CONFIG_DIR")
+	os.Setenv("PROJJ_CONFIG_DIR", tempDir)
+	
+	return tempDir, func() {
+		os.Setenv("PROJJ_CONFIG_DIR", originalConfigDir)
+		os.RemoveAll(tempDir)
+	}
+}
+
+func openStore(t testing.TB, backend string) Store {
+	t.Helper()
+	
+	cfg := config.DefaultConfig()
+	cfg.CacheBackend = backend
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	
+	store, err := Open()
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	return store
+}
+
+func TestStore(t *testing.T) {
+	for _, backend := range []string{config.CacheBackendJSON, config.CacheBackendBolt} {
+		t.Run(backend, func(t *testing.T) {
+			tempDir, cleanup := setupStoreEnv(t)
+			defer cleanup()
+			
+			store := openStore(t, backend)
+			
+			repoPath := filepath.Join(tempDir, "github.com", "user", "repo")
+			otherPath := filepath.Join(tempDir, "gitlab.com", "team", "other")
+			err := store.Put(
+				Repository{Name: "alpha", URL: "git@github.com:user/alpha.git", Path: repoPath, Platform: "github.com"},
+				Repository{Name: "other", URL: "https://gitlab.com/team/other", Path: otherPath, Platform: "gitlab.com"},
+			)
+			if err != nil {
+				t.Fatalf("Put() failed: %v", err)
+			}
+			
+			repos, err := store.List()
+			if err != nil || len(repos) != 2 {
+				t.Fatalf("Expected 2 repositories, got %d (%v)", len(repos), err)
+			}
+			
+			repo, err := store.GetByPath(repoPath)
+			if err != nil || repo == nil || repo.Name != "alpha" {
+				t.Errorf("GetByPath() returned %+v (%v)", repo, err)
+			}
+			if repo != nil && repo.AddedAt.IsZero() {
+				t.Error("AddedAt should be set on put")
+			}
+			
+			repo, err = store.GetByDir(filepath.Join(repoPath, "src", "pkg"))
+			if err != nil || repo == nil || repo.Path != repoPath {
+				t.Errorf("GetByDir() returned %+v (%v)", repo, err)
+			}
+			
+			// 不同协议的同一仓库
+			byURL, err := store.GetByURL("https://github.com/User/alpha")
+			if err != nil || len(byURL) != 1 || byURL[0].Path != repoPath {
+				t.Errorf("GetByURL() returned %+v (%v)", byURL, err)
+			}
+			
+			found, err := store.Find("team")
+			if err != nil || len(found) != 1 || found[0].Repo.Path != otherPath {
+				t.Errorf("Find() returned %+v (%v)", found, err)
+			}
+			
+			// 更新已有仓库时替换旧的索引
+			if err := store.Put(Repository{Name: "renamed", URL: "git@github.com:user/renamed.git", Path: repoPath}); err != nil {
+				t.Fatalf("Put() failed: %v", err)
+			}
+			if found, _ := store.Find("alpha"); len(found) != 0 {
+				t.Errorf("Stale index entries should be removed, got %+v", found)
+			}
+			if found, _ := store.Find("renamed"); len(found) != 1 {
+				t.Errorf("Expected updated repository to be found, got %+v", found)
+			}
+			
+			// 记录访问
+			for i := 0; i < 2; i++ {
+				if visited, err := store.Visit(otherPath, time.Now()); err != nil || !visited {
+					t.Fatalf("Visit() returned %v (%v)", visited, err)
+				}
+			}
+			if repo, _ := store.GetByPath(otherPath); repo == nil || repo.Visits != 2 || repo.LastVisitedAt.IsZero() {
+				t.Errorf("Expected 2 visits recorded, got %+v", repo)
+			}
+			if visited, _ := store.Visit("/not/managed", time.Now()); visited {
+				t.Error("Visiting an unknown path should return false")
+			}
+			
+			// 修改保留其他字段，返回错误时放弃修改
+			modified, err := store.Modify(otherPath, func(repo *Repository) error {
+				repo.AddTags("payments")
+				repo.Description = "支付服务"
+				return nil
+			})
+			if err != nil || !modified {
+				t.Fatalf("Modify() returned %v (%v)", modified, err)
+			}
+			if repo, _ := store.GetByPath(otherPath); repo == nil || !repo.HasTag("payments") || repo.Description != "支付服务" || repo.Visits != 2 {
+				t.Errorf("Expected tags and description saved with visits kept, got %+v", repo)
+			}
+			if _, err := store.Modify(otherPath, func(repo *Repository) error {
+				repo.Description = "changed"
+				return errors.New("abort")
+			}); err == nil {
+				t.Error("Modify() should return the error from fn")
+			}
+			if repo, _ := store.GetByPath(otherPath); repo == nil || repo.Description != "支付服务" {
+				t.Errorf("Expected aborted modification to be discarded, got %+v", repo)
+			}
+			if modified, _ := store.Modify("/not/managed", func(*Repository) error { return nil }); modified {
+				t.Error("Modifying an unknown path should return false")
+			}
+			
+			// 重新登记已有仓库时合并到原记录
+			before, _ := store.GetByPath(otherPath)
+			if err := store.Put(Repository{Name: "other", URL: "https://gitlab.com/team/other", Path: otherPath, Platform: "gitlab.com"}); err != nil {
+				t.Fatalf("Put() failed: %v", err)
+			}
+			if repo, _ := store.GetByPath(otherPath); repo == nil || !repo.HasTag("payments") || repo.Description != "支付服务" || repo.Visits != 2 || !repo.AddedAt.Equal(before.AddedAt) {
+				t.Errorf("Expected existing record merged on put, got %+v", repo)
+			}
+			
+			removed, err := store.Remove(otherPath)
+			if err != nil || !removed {
+				t.Errorf("Remove() returned %v (%v)", removed, err)
+			}
+			if removed, _ := store.Remove(otherPath); removed {
+				t.Error("Removing a missing repository should return false")
+			}
+			
+			// Sync 移除不存在的目录并添加新仓库
+			if err := os.MkdirAll(filepath.Join(otherPath, ".git"), 0755); err != nil {
+				t.Fatalf("Failed to create repo: %v", err)
+			}
+			added, removedCount, err := store.Sync([]Repository{{Name: "other", Path: otherPath}})
+			if err != nil || added != 1 || removedCount != 1 {
+				t.Errorf("Sync() returned added=%d removed=%d (%v)", added, removedCount, err)
+			}
+			
+			// 重新打开后数据仍在
+			reopened, err := Open()
+			if err != nil {
+				t.Fatalf("Open() failed: %v", err)
+			}
+			repos, _ = reopened.List()
+			if len(repos) != 1 || repos[0].Path != otherPath {
+				t.Errorf("Expected persisted repository, got %+v", repos)
+			}
+		})
+	}
+}
+
+func TestStoreFindConsistent(t *testing.T) {
+	repos := []Repository{
+		{Name: "API", URL: "git@github.com:Team/API.git", Path: "/projj/github.com/team/api"},
+		{Name: "api-gateway", URL: "https://github.com/team/api-gateway", Path: "/projj/github.com/team/api-gateway"},
+		{Name: "web", URL: "git@gitlab.com:team/web.git", Path: "/projj/gitlab.com/team/web"},
+		{Name: "rapid", URL: "https://github.com/other/rapid.git", Path: "/projj/github.com/other/rapid"},
+	}
+	
+	// 两种存储对同样的数据返回同样的匹配类型和顺序
+	results := make(map[string][]Match)
+	for _, backend := range []string{config.CacheBackendJSON, config.CacheBackendBolt} {
+		_, cleanup := setupStoreEnv(t)
+		store := openStore(t, backend)
+		if err := store.Put(repos...); err != nil {
+			cleanup()
+			t.Fatalf("Put() failed: %v", err)
+		}
+		for _, query := range []string{"api", "git@", "https://github", "team/a", "ap"} {
+			found, err := store.Find(query)
+			if err != nil {
+				cleanup()
+				t.Fatalf("Find(%q) failed: %v", query, err)
+			}
+			results[backend+query] = found
+		}
+		cleanup()
+	}
+	
+	for _, query := range []string{"api", "git@", "https://github", "team/a", "ap"} {
+		jsonFound, boltFound := results[config.CacheBackendJSON+query], results[config.CacheBackendBolt+query]
+		if len(jsonFound) == 0 || len(jsonFound) != len(boltFound) {
+			t.Errorf("Find(%q): json returned %d matches, bolt %d", query, len(jsonFound), len(boltFound))
+			continue
+		}
+		for i := range jsonFound {
+			j, b := jsonFound[i], boltFound[i]
+			if j.Repo.Path != b.Repo.Path || j.Kind != b.Kind || j.Score != b.Score || b.Repo.URL != j.Repo.URL {
+				t.Errorf("Find(%q)[%d]: json %+v, bolt %+v", query, i, j, b)
+			}
+		}
+	}
+}
+
+func TestOpenBoltMigratesJSON(t *testing.T) {
+	_, cleanup := setupStoreEnv(t)
+	defer cleanup()
+	
+	cache := &Cache{}
+	for i := 0; i < 3; i++ {
+		cache.Add(Repository{Name: fmt.Sprintf("repo-%d", i), Path: fmt.Sprintf("/path/to/repo-%d", i)})
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	
+	store := openStore(t, config.CacheBackendBolt)
+	if store.Notice() == "" {
+		t.Error("Notice should describe the migration")
+	}
+	repos, err := store.List()
+	if err != nil || len(repos) != 3 {
+		t.Errorf("Expected 3 migrated repositories, got %d (%v)", len(repos), err)
+	}
+	
+	// 只迁移一次
+	store = openStore(t, config.CacheBackendBolt)
+	if store.Notice() != "" {
+		t.Errorf("Existing database should not be migrated again: %s", store.Notice())
+	}
+}
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"git@github.com:user/repo.git", "github.com/user/repo"},
+		{"https://github.com/user/repo.git", "github.com/user/repo"},
+		{"https://github.com/User/Repo/", "github.com/user/repo"},
+		{"ssh://git@gitlab.com/team/repo", "gitlab.com/team/repo"},
+		{"github.com/user/repo", "github.com/user/repo"},
+	}
+	
+	for _, tt := range tests {
+		if got := CanonicalURL(tt.url); got != tt.expected {
+			t.Errorf("CanonicalURL(%q) = %q, expected %q", tt.url, got, tt.expected)
+		}
+	}
+}
+
+// benchmarkEntries 基准测试使用的仓库数量
+const benchmarkEntries = 50000
+
+func benchmarkRepos() []Repository {
+	repos := make([]Repository, benchmarkEntries)
+	for i := range repos {
+		owner := fmt.Sprintf("org-%d", i%500)
+		name := fmt.Sprintf("service-%d", i)
+		repos[i] = Repository{
+			Name:     name,
+			URL:      fmt.Sprintf("git@github.com:%s/%s.git", owner, name),
+			Path:     fmt.Sprintf("/home/user/projj/github.com/%s/%s", owner, name),
+			Platform: "github.com",
+		}
+	}
+	return repos
+}
+
+// setupBenchmarkStore 准备包含 50k 仓库的存储，返回的函数模拟一次命令调用时打开存储
+func setupBenchmarkStore(b *testing.B, backend string) func() Store {
+	_, cleanup := setupStoreEnv(b)
+	b.Cleanup(cleanup)
+	
+	openStore(b, backend).Put(benchmarkRepos()...)
+	return func() Store {
+		store, err := Open()
+		if err != nil {
+			b.Fatalf("Open() failed: %v", err)
+		}
+		return store
+	}
+}
+
+func benchmarkFind(b *testing.B, backend string) {
+	open := setupBenchmarkStore(b, backend)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if found, err := open().Find("service-4242"); err != nil || len(found) == 0 {
+			b.Fatalf("Find() returned %d results (%v)", len(found), err)
+		}
+	}
+}
+
+func benchmarkGetByPath(b *testing.B, backend string) {
+	open := setupBenchmarkStore(b, backend)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if repo, err := open().GetByPath("/home/user/projj/github.com/org-242/service-4242"); err != nil || repo == nil {
+			b.Fatalf("GetByPath() failed: %v", err)
+		}
+	}
+}
+
+func benchmarkPut(b *testing.B, backend string) {
+	open := setupBenchmarkStore(b, backend)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		repo := Repository{Name: fmt.Sprintf("new-%d", i), URL: fmt.Sprintf("git@github.com:new/new-%d.git", i), Path: fmt.Sprintf("/tmp/new-%d", i)}
+		if err := open().Put(repo); err != nil {
+			b.Fatalf("Put() failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkJSONFind50k(b *testing.B)      { benchmarkFind(b, config.CacheBackendJSON) }
+func BenchmarkBoltFind50k(b *testing.B)      { benchmarkFind(b, config.CacheBackendBolt) }
+func BenchmarkJSONGetByPath50k(b *testing.B) { benchmarkGetByPath(b, config.CacheBackendJSON) }
+func BenchmarkBoltGetByPath50k(b *testing.B) { benchmarkGetByPath(b, config.CacheBackendBolt) }
+func BenchmarkJSONPut50k(b *testing.B)       { benchmarkPut(b, config.CacheBackendJSON) }
+func BenchmarkBoltPut50k(b *testing.B)       { benchmarkPut(b, config.CacheBackendBolt) }
\ No newline at end of file
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// NormalizeTag 规范化标签：去掉首尾空白并转为小写，不能包含空白、逗号、引号、冒号和括号
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", fmt.Errorf("标签不能为空")
+	}
+	for _, r := range tag {
+		if unicode.IsSpace(r) || strings.ContainsRune(`,:"()`, r) {
+			return "", fmt.Errorf("标签包含无效字符 %q: %s", r, tag)
+		}
+	}
+	return tag, nil
+}
+
+// HasTag 判断仓库是否带有指定标签
+func (r *Repository) HasTag(tag string) bool {
+	return contains(r.Tags, tag)
+}
+
+// contains 判断列表中是否有不区分大小写相同的标签
+func contains(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTags 添加标签，返回实际新增的标签，标签保持排序
+func (r *Repository) AddTags(tags ...string) []string {
+	var added []string
+	// 复制一份，避免修改与缓存共享的底层数组
+	result := append([]string(nil), r.Tags...)
+	for _, tag := range tags {
+		if !r.HasTag(tag) && !contains(added, tag) {
+			result = append(result, tag)
+			added = append(added, tag)
+		}
+	}
+	sort.Strings(result)
+	r.Tags = result
+	return added
+}
+
+// RemoveTags 移除标签，返回实际移除的标签
+func (r *Repository) RemoveTags(tags ...string) []string {
+	var removed, kept []string
+	for _, t := range r.Tags {
+		if contains(tags, t) {
+			removed = append(removed, t)
+		} else {
+			kept = append(kept, t)
+		}
+	}
+	r.Tags = kept
+	return removed
+}
+
+// CountTags 统计每个标签关联的仓库数量
+func CountTags(repos []Repository) map[string]int {
+	counts := make(map[string]int)
+	for _, repo := range repos {
+		for _, tag := range repo.Tags {
+			counts[tag]++
+		}
+	}
+	return counts
+}
\ No newline at end of file
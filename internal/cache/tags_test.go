@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	valid := map[string]string{
+		"payments":   "payments",
+		" Infra ":    "infra",
+		"team-a/ops": "team-a/ops",
+		"支付":         "支付",
+	}
+	for input, expected := range valid {
+		got, err := NormalizeTag(input)
+		if err != nil || got != expected {
+			t.Errorf("NormalizeTag(%q) = %q, %v; expected %q", input, got, err, expected)
+		}
+	}
+	
+	for _, input := range []string{"", "  ", "a b", "a,b", "a:b", `"a"`, "(a)"} {
+		if _, err := NormalizeTag(input); err == nil {
+			t.Errorf("NormalizeTag(%q) should fail", input)
+		}
+	}
+}
+
+func TestAddRemoveTags(t *testing.T) {
+	shared := []string{"infra", "web"}
+	repo := Repository{Tags: shared[:1]}
+	
+	added := repo.AddTags("payments", "infra", "api", "payments")
+	if len(added) != 2 || added[0] != "payments" || added[1] != "api" {
+		t.Errorf("Expected payments and api added, got %v", added)
+	}
+	if len(repo.Tags) != 3 || repo.Tags[0] != "api" || repo.Tags[1] != "infra" || repo.Tags[2] != "payments" {
+		t.Errorf("Expected sorted tags, got %v", repo.Tags)
+	}
+	// 不能修改与其他仓库共享的底层数组
+	if shared[1] != "web" {
+		t.Errorf("AddTags modified shared slice: %v", shared)
+	}
+	if !repo.HasTag("INFRA") {
+		t.Error("HasTag should be case-insensitive")
+	}
+	
+	removed := repo.RemoveTags("infra", "missing")
+	if len(removed) != 1 || removed[0] != "infra" {
+		t.Errorf("Expected infra removed, got %v", removed)
+	}
+	if repo.HasTag("infra") || len(repo.Tags) != 2 {
+		t.Errorf("Unexpected tags after remove: %v", repo.Tags)
+	}
+	
+	repo.RemoveTags("api", "payments")
+	if repo.Tags != nil {
+		t.Errorf("Expected nil tags after removing all, got %v", repo.Tags)
+	}
+	
+	counts := CountTags([]Repository{
+		{Tags: []string{"infra", "payments"}},
+		{Tags: []string{"infra"}},
+		{},
+	})
+	if counts["infra"] != 2 || counts["payments"] != 1 || len(counts) != 2 {
+		t.Errorf("Unexpected tag counts: %v", counts)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,232 @@
+package campaign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atian25/projj-go/internal/config"
+	"github.com/atian25/projj-go/internal/fsutil"
+	"github.com/atian25/projj-go/internal/lockfile"
+)
+
+// State 表示仓库在活动中的进度，Error 不为空时表示执行下一步时失败
+type State string
+
+const (
+	Pending    State = "pending"     // 尚未开始
+	Branched   State = "branched"    // 已创建并切换到活动分支
+	Committed  State = "committed"   // 脚本产生的修改已提交
+	Unchanged  State = "unchanged"   // 脚本没有产生修改，无需提交
+	Pushed     State = "pushed"      // 已推送到远程
+	RolledBack State = "rolled-back" // 已回滚
+)
+
+// labels 状态在文字输出中的名称
+var labels = map[State]string{
+	Pending:    "待执行",
+	Branched:   "已创建分支",
+	Committed:  "已提交",
+	Unchanged:  "无修改",
+	Pushed:     "已推送",
+	RolledBack: "已回滚",
+}
+
+// Label 返回状态在文字输出中的名称
+func (s State) Label() string {
+	if label, ok := labels[s]; ok {
+		return label
+	}
+	return string(s)
+}
+
+// Repo 表示活动中的一个仓库
+type Repo struct {
+	Name           string    `json:"name"`
+	Path           string    `json:"path"`
+	URL            string    `json:"url"`
+	OriginalBranch string    `json:"original_branch,omitempty"` // 开始前所在的分支，回滚时切换回去
+	CreatedBranch  bool      `json:"created_branch,omitempty"`  // 活动分支是否由活动创建，只有这时回滚才删除
+	State          State     `json:"state"`
+	Commit         string    `json:"commit,omitempty"`        // 活动产生的提交
+	RemoteBranch   bool      `json:"remote_branch,omitempty"` // 远程是否有推送过的活动分支
+	Error          string    `json:"error,omitempty"`         // 最近一次执行失败的原因，成功后清空
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Failed 判断仓库最近一次执行是否失败
+func (r Repo) Failed() bool {
+	return r.Error != ""
+}
+
+// Done 判断仓库是否已经完成，push 为 true 时有提交的仓库需要推送后才算完成
+func (r Repo) Done(push bool) bool {
+	switch r.State {
+	case Unchanged, Pushed, RolledBack:
+		return true
+	case Committed:
+		return !push
+	}
+	return false
+}
+
+// Campaign 表示在多个仓库中执行同一修改的活动
+type Campaign struct {
+	Name      string    `json:"name"`
+	Branch    string    `json:"branch"`  // 在每个仓库中创建的分支
+	Script    string    `json:"script"`  // 在仓库目录中通过 shell 执行的脚本
+	Message   string    `json:"message"` // 提交信息
+	CreatedAt time.Time `json:"created_at"`
+	Repos     []Repo    `json:"repos"`
+}
+
+// Counts 统计各状态的仓库数量，失败的仓库计入 failed 而不是所处的状态
+func (c *Campaign) Counts() (counts map[State]int, failed int) {
+	counts = make(map[State]int)
+	for _, repo := range c.Repos {
+		if repo.Failed() {
+			failed++
+			continue
+		}
+		counts[repo.State]++
+	}
+	return counts, failed
+}
+
+// Set 更新路径相同的仓库
+func (c *Campaign) Set(repo Repo) {
+	for i := range c.Repos {
+		if c.Repos[i].Path == repo.Path {
+			c.Repos[i] = repo
+			return
+		}
+	}
+}
+
+// ErrNotFound 表示活动不存在
+var ErrNotFound = errors.New("活动不存在")
+
+// namePattern 活动名称只能包含字母、数字、点、下划线和连字符，用作文件名
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateName 校验活动名称
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("无效的活动名称 %q: 只能包含字母、数字、点、下划线和连字符", name)
+	}
+	return nil
+}
+
+// GetDir 获取保存活动状态的目录
+func GetDir() string {
+	return filepath.Join(config.GetConfigDir(), "campaigns")
+}
+
+// getPath 获取活动状态文件的路径
+func getPath(name string) string {
+	return filepath.Join(GetDir(), name+".json")
+}
+
+// Load 读取活动状态
+func Load(name string) (*Campaign, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	
+	data, err := os.ReadFile(getPath(name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取活动 %s 失败: %w", name, err)
+	}
+	
+	var c Campaign
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("解析活动 %s 失败: %w", name, err)
+	}
+	return &c, nil
+}
+
+// List 读取所有活动，按创建时间排序
+func List() ([]*Campaign, error) {
+	entries, err := os.ReadDir(GetDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取活动目录失败: %w", err)
+	}
+	
+	var campaigns []*Campaign
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() || ValidateName(name) != nil {
+			continue
+		}
+		c, err := Load(name)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, c)
+	}
+	
+	sort.SliceStable(campaigns, func(i, j int) bool {
+		return campaigns[i].CreatedAt.Before(campaigns[j].CreatedAt)
+	})
+	return campaigns, nil
+}
+
+// Create 保存新的活动，同名活动已存在时返回错误
+func Create(c *Campaign) error {
+	if err := ValidateName(c.Name); err != nil {
+		return err
+	}
+	if fsutil.Exists(getPath(c.Name)) {
+		return fmt.Errorf("活动已存在: %s", c.Name)
+	}
+	return c.Save()
+}
+
+// Save 保存活动状态
+func (c *Campaign) Save() error {
+	path := getPath(c.Name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建活动目录失败: %w", err)
+	}
+	
+	lock, err := lockfile.Acquire(path, lockfile.DefaultTimeout)
+	if err != nil {
+		return fmt.Errorf("锁定活动文件失败: %w", err)
+	}
+	defer lock.Release()
+	
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化活动失败: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("写入活动文件失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除活动状态文件
+func Delete(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if err := os.Remove(getPath(name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		return fmt.Errorf("删除活动 %s 失败: %w", name, err)
+	}
+	return nil
+}
\ No newline at end of file
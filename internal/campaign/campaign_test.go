@@ -0,0 +1,123 @@
+package campaign
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func setupCampaignDir(t *testing.T) func() {
+	tempDir, err := os.MkdirTemp("", "projj-campaign-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	
+	originalConfigDir := os.Getenv("PROJJ_CONFIG_DIR")
+	os.Setenv("PROJJ_CONFIG_DIR", tempDir)
+	
+	return func() {
+		os.Setenv("PROJJ_CONFIG_DIR", originalConfigDir)
+		os.RemoveAll(tempDir)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"bump-lib", "v1.2_fix", "A"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("Expected %q to be valid: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "../x", "a/b", ".hidden", "有中文"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("Expected %q to be invalid", name)
+		}
+	}
+}
+
+func TestCreateLoadList(t *testing.T) {
+	cleanup := setupCampaignDir(t)
+	defer cleanup()
+	
+	if campaigns, err := List(); err != nil || len(campaigns) != 0 {
+		t.Fatalf("Expected no campaigns, got %v (%v)", campaigns, err)
+	}
+	
+	now := time.Now()
+	second := &Campaign{Name: "second", Branch: "b", CreatedAt: now}
+	first := &Campaign{
+		Name:      "first",
+		Branch:    "bump",
+		Script:    "make bump",
+		Message:   "bump",
+		CreatedAt: now.Add(-time.Hour),
+		Repos:     []Repo{{Name: "api", Path: "/base/api", State: Pending}},
+	}
+	for _, c := range []*Campaign{second, first} {
+		if err := Create(c); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+	}
+	if err := Create(first); err == nil {
+		t.Error("Create() should fail for existing campaign")
+	}
+	
+	loaded, err := Load("first")
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if loaded.Branch != "bump" || len(loaded.Repos) != 1 || loaded.Repos[0].State != Pending {
+		t.Errorf("Unexpected campaign: %+v", loaded)
+	}
+	
+	loaded.Set(Repo{Name: "api", Path: "/base/api", State: Committed, Commit: "abc123"})
+	if err := loaded.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	
+	campaigns, err := List()
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if len(campaigns) != 2 || campaigns[0].Name != "first" || campaigns[1].Name != "second" {
+		t.Fatalf("Expected campaigns ordered by creation time, got %+v", campaigns)
+	}
+	if campaigns[0].Repos[0].Commit != "abc123" {
+		t.Errorf("Expected saved repo state, got %+v", campaigns[0].Repos[0])
+	}
+	
+	if err := Delete("first"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := Load("first"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if err := Delete("first"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRepoState(t *testing.T) {
+	c := &Campaign{Repos: []Repo{
+		{Path: "a", State: Pending, Error: "有未提交的修改"},
+		{Path: "b", State: Committed},
+		{Path: "c", State: Unchanged},
+		{Path: "d", State: Pushed},
+	}}
+	
+	counts, failed := c.Counts()
+	if failed != 1 || counts[Committed] != 1 || counts[Unchanged] != 1 || counts[Pushed] != 1 || counts[Pending] != 0 {
+		t.Errorf("Unexpected counts: %v, failed %d", counts, failed)
+	}
+	
+	if c.Repos[1].Done(true) || !c.Repos[1].Done(false) {
+		t.Error("Committed repo should be done only without push")
+	}
+	if c.Repos[0].Done(false) || !c.Repos[2].Done(true) {
+		t.Error("Unexpected done state")
+	}
+	
+	if Pushed.Label() != "已推送" || State("unknown").Label() != "unknown" {
+		t.Error("Unexpected state label")
+	}
+}
\ No newline at end of file
@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	
+	"github.com/atian25/projj-go/internal/fsutil"
+	"github.com/atian25/projj-go/internal/lockfile"
 )
 
 // Config 表示 projj 的配置结构
@@ -14,6 +18,64 @@ type Config struct {
 	Alias           map[string]string            `json:"alias"`
 	Hooks           map[string]string            `json:"hooks"`
 	PostAdd         map[string]map[string]string `json:"postadd"`
+	ScanWorkers     int                          `json:"scan_workers,omitempty"`
+	ScanIgnore      []string                     `json:"scan_ignore,omitempty"`
+	CacheFormat     string                       `json:"cache_format,omitempty"`
+	CacheBackend    string                       `json:"cache_backend,omitempty"`
+	Templates       map[string]string            `json:"templates,omitempty"`
+}
+
+// 缓存的存储后端
+const (
+	CacheBackendJSON = "json" // cache.json 文件
+	CacheBackendBolt = "bolt" // 嵌入式数据库 cache.db，适合大量仓库
+)
+
+// 缓存文件的写入格式
+const (
+	CacheFormatGo   = "projj-go"   // 仓库列表格式
+	CacheFormatNode = "projj-node" // 原版 projj 以路径为键的格式
+	CacheFormatBoth = "both"       // 原版格式，并在每一项中保留 projj-go 的字段
+)
+
+// GetCacheFormat 获取缓存文件的写入格式，未设置时为 projj-go
+func (c *Config) GetCacheFormat() string {
+	if c.CacheFormat == "" {
+		return CacheFormatGo
+	}
+	return c.CacheFormat
+}
+
+// GetCacheBackend 获取缓存的存储后端，未设置时为 json
+func (c *Config) GetCacheBackend() string {
+	if c.CacheBackend == "" {
+		return CacheBackendJSON
+	}
+	return c.CacheBackend
+}
+
+// CacheKeepsMetadata 判断缓存是否保存添加时间、访问记录、标签和描述：
+// json 存储使用 projj-node 格式时只写入仓库地址，这些字段在保存时丢失
+func (c *Config) CacheKeepsMetadata() bool {
+	return c.GetCacheBackend() != CacheBackendJSON || c.GetCacheFormat() != CacheFormatNode
+}
+
+// ValidCacheFormat 检查缓存格式是否有效，空值表示默认的 projj-go 格式
+func ValidCacheFormat(format string) bool {
+	switch format {
+	case "", CacheFormatGo, CacheFormatNode, CacheFormatBoth:
+		return true
+	}
+	return false
+}
+
+// ValidCacheBackend 检查缓存存储后端是否有效，空值表示默认的 json
+func ValidCacheBackend(backend string) bool {
+	switch backend {
+	case "", CacheBackendJSON, CacheBackendBolt:
+		return true
+	}
+	return false
 }
 
 // DefaultConfig 返回默认配置
@@ -71,6 +133,41 @@ func Load() (*Config, error) {
 
 // Save 保存配置文件
 func (c *Config) Save() error {
+	lock, err := lockfile.Acquire(GetConfigPath(), lockfile.DefaultTimeout)
+	if err != nil {
+		return fmt.Errorf("锁定配置文件失败: %w", err)
+	}
+	defer lock.Release()
+	
+	return c.save()
+}
+
+// Update 在文件锁内重新加载配置、修改并保存，避免并发修改相互覆盖
+func Update(fn func(*Config) error) (*Config, error) {
+	lock, err := lockfile.Acquire(GetConfigPath(), lockfile.DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("锁定配置文件失败: %w", err)
+	}
+	defer lock.Release()
+	
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	
+	if err := fn(cfg); err != nil {
+		return nil, err
+	}
+	
+	if err := cfg.save(); err != nil {
+		return nil, err
+	}
+	
+	return cfg, nil
+}
+
+// save 写入配置文件，调用方需持有锁
+func (c *Config) save() error {
 	configDir := GetConfigDir()
 	
 	// 确保配置目录存在
@@ -84,7 +181,7 @@ func (c *Config) Save() error {
 		return fmt.Errorf("序列化配置失败: %w", err)
 	}
 	
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	if err := fsutil.WriteFileAtomic(configPath, data, 0644); err != nil {
 		return fmt.Errorf("写入配置文件失败: %w", err)
 	}
 	
@@ -104,6 +201,25 @@ func (c *Config) ExpandPath(path string) string {
 	return path
 }
 
+// TemplatePrefix 配置键中模板预设的前缀，如 templates.short
+const TemplatePrefix = "templates."
+
+// SetTemplate 设置模板预设，内容为空时删除
+func (c *Config) SetTemplate(name, text string) error {
+	if name == "" || strings.ContainsAny(name, "{} \t") {
+		return fmt.Errorf("无效的模板名称: %q", name)
+	}
+	if text == "" {
+		delete(c.Templates, name)
+		return nil
+	}
+	if c.Templates == nil {
+		c.Templates = make(map[string]string)
+	}
+	c.Templates[name] = text
+	return nil
+}
+
 // GetBasePath 获取展开后的基础路径
 func (c *Config) GetBasePath() string {
 	return c.ExpandPath(c.Base)
@@ -182,4 +182,50 @@ func TestConfigJSON(t *testing.T) {
 	if len(newConfig.Alias) != len(config.Alias) {
 		t.Errorf("Alias count mismatch after JSON round-trip")
 	}
+}
+
+func TestCacheKeepsMetadata(t *testing.T) {
+	tests := []struct {
+		backend  string
+		format   string
+		expected bool
+	}{
+		{"", "", true},
+		{CacheBackendJSON, CacheFormatBoth, true},
+		{CacheBackendJSON, CacheFormatNode, false},
+		{"", CacheFormatNode, false},
+		{CacheBackendBolt, CacheFormatNode, true},
+	}
+	
+	for _, tt := range tests {
+		config := &Config{CacheBackend: tt.backend, CacheFormat: tt.format}
+		if got := config.CacheKeepsMetadata(); got != tt.expected {
+			t.Errorf("CacheKeepsMetadata() with %q/%q = %v, expected %v", tt.backend, tt.format, got, tt.expected)
+		}
+	}
+}
+
+func TestSetTemplate(t *testing.T) {
+	config := DefaultConfig()
+	
+	if err := config.SetTemplate("short", "{{.Owner}}/{{.Name}}"); err != nil {
+		t.Fatalf("SetTemplate failed: %v", err)
+	}
+	if config.Templates["short"] != "{{.Owner}}/{{.Name}}" {
+		t.Errorf("Expected template to be saved, got %v", config.Templates)
+	}
+	
+	// 内容为空时删除预设
+	if err := config.SetTemplate("short", ""); err != nil {
+		t.Fatalf("SetTemplate failed: %v", err)
+	}
+	if _, ok := config.Templates["short"]; ok {
+		t.Error("Expected template to be deleted")
+	}
+	
+	for _, name := range []string{"", "a b", "{{.Name}}"} {
+		if err := config.SetTemplate(name, "x"); err == nil {
+			t.Errorf("Expected error for template name %q", name)
+		}
+	}
 }
\ No newline at end of file
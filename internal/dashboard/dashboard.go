@@ -0,0 +1,365 @@
+package dashboard
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"golang.org/x/term"
+)
+
+// Backend 提供界面需要的数据和操作
+type Backend interface {
+	List() ([]cache.Repository, error)
+	Status(path string) Status
+	Pull(repo cache.Repository) error
+	Remove(repo cache.Repository) error
+	RunHook(name string, repo cache.Repository) error
+	Hooks() []string
+}
+
+// statusWorkers 同时读取 git 状态的数量
+const statusWorkers = 8
+
+// statusResult 表示一个仓库的状态读取结果
+type statusResult struct {
+	path   string
+	status Status
+}
+
+// Run 在终端中显示仓库面板，直到用户退出
+func Run(b Backend) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return fmt.Errorf("projj ui 需要在终端中运行")
+	}
+	
+	repos, err := b.List()
+	if err != nil {
+		return fmt.Errorf("获取仓库列表失败: %w", err)
+	}
+	
+	d := &dashboard{
+		backend:  b,
+		model:    NewModel(repos, b.Hooks()),
+		queue:    make(chan string, 1024),
+		results:  make(chan statusResult, 64),
+		keys:     make(chan []byte),
+		wantKeys: make(chan struct{}),
+	}
+	for i := 0; i < statusWorkers; i++ {
+		go d.statusWorker()
+	}
+	go d.readKeys()
+	
+	if err := d.enter(); err != nil {
+		return err
+	}
+	defer d.leave()
+	return d.loop()
+}
+
+// dashboard 表示运行中的界面
+type dashboard struct {
+	backend  Backend
+	model    *Model
+	state    *term.State
+	queue    chan string       // 待读取状态的仓库路径
+	results  chan statusResult // 读取完成的状态
+	keys     chan []byte       // 读取到的按键
+	wantKeys chan struct{}     // 请求读取下一次按键
+	reading  bool              // 是否已有进行中的读取
+}
+
+// statusWorker 读取队列中仓库的 git 状态
+func (d *dashboard) statusWorker() {
+	for path := range d.queue {
+		d.results <- statusResult{path: path, status: d.backend.Status(path)}
+	}
+}
+
+// readKeys 只在收到请求后读取标准输入，避免暂停界面执行 shell 等命令时抢占输入
+func (d *dashboard) readKeys() {
+	buf := make([]byte, 64)
+	for range d.wantKeys {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			close(d.keys)
+			return
+		}
+		chunk := make([]byte, n)
+		copy(chunk, buf[:n])
+		d.keys <- chunk
+	}
+}
+
+// enter 切换到备用屏幕并进入原始模式
+func (d *dashboard) enter() error {
+	state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return fmt.Errorf("切换终端模式失败: %w", err)
+	}
+	d.state = state
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	return nil
+}
+
+// leave 恢复终端
+func (d *dashboard) leave() {
+	fmt.Print("\x1b[?25h\x1b[?1049l")
+	if d.state != nil {
+		term.Restore(int(os.Stdin.Fd()), d.state)
+		d.state = nil
+	}
+}
+
+// loop 重绘界面并处理按键和状态更新
+func (d *dashboard) loop() error {
+	for {
+		d.render()
+		if !d.reading {
+			d.wantKeys <- struct{}{}
+			d.reading = true
+		}
+		
+		select {
+		case result := <-d.results:
+			d.model.SetStatus(result.path, result.status)
+			// 合并同时到达的结果，减少重绘
+			for drained := false; !drained; {
+				select {
+				case result := <-d.results:
+					d.model.SetStatus(result.path, result.status)
+				default:
+					drained = true
+				}
+			}
+		case chunk, ok := <-d.keys:
+			d.reading = false
+			if !ok {
+				return nil
+			}
+			for _, key := range splitKeys(chunk) {
+				action := d.model.Handle(key)
+				if action.Kind == ActionQuit {
+					return nil
+				}
+				if err := d.perform(action); err != nil {
+					d.model.Message = err.Error()
+				}
+			}
+		}
+	}
+}
+
+// render 按终端大小绘制界面，并将新出现的仓库加入状态读取队列
+func (d *dashboard) render() {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, height = 80, 24
+	}
+	
+	lines, missing := d.model.Render(width, height)
+	for _, path := range missing {
+		select {
+		case d.queue <- path:
+		default:
+			// 队列已满时稍后重新请求
+			d.model.Invalidate(path)
+		}
+	}
+	
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\x1b[K")
+	}
+	b.WriteString("\x1b[J")
+	fmt.Print(b.String())
+}
+
+// perform 执行按键产生的操作
+func (d *dashboard) perform(action Action) error {
+	switch action.Kind {
+	case ActionShell:
+		repo := action.Repos[0]
+		return d.suspend(false, func() error {
+			fmt.Printf("在 %s 中打开 shell，退出 shell 后返回 projj ui\n", repo.Path)
+			return openShell(repo.Path)
+		}, repo.Path)
+	case ActionPull:
+		return d.suspend(true, func() error {
+			return forEach(action.Repos, func(repo cache.Repository) error {
+				return d.backend.Pull(repo)
+			})
+		}, paths(action.Repos)...)
+	case ActionHook:
+		return d.suspend(true, func() error {
+			return forEach(action.Repos, func(repo cache.Repository) error {
+				return d.backend.RunHook(action.Hook, repo)
+			})
+		}, paths(action.Repos)...)
+	case ActionRemove:
+		for _, repo := range action.Repos {
+			if err := d.backend.Remove(repo); err != nil {
+				return err
+			}
+		}
+		d.model.Message = fmt.Sprintf("已移除 %d 个仓库", len(action.Repos))
+		return d.reload()
+	case ActionCopy:
+		text := strings.Join(paths(action.Repos), "\n")
+		if err := copyToClipboard(text); err != nil {
+			return err
+		}
+		d.model.Message = fmt.Sprintf("已复制 %d 个路径", len(action.Repos))
+	case ActionRefresh:
+		return d.reload()
+	}
+	return nil
+}
+
+// suspend 暂时离开界面执行命令，wait 为 true 时等待按 Enter 后返回，结束后刷新相关仓库的状态
+func (d *dashboard) suspend(wait bool, fn func() error, changed ...string) error {
+	d.leave()
+	err := fn()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+	}
+	if wait {
+		fmt.Print("\n按 Enter 返回 projj ui")
+		bufio.NewReader(os.Stdin).ReadString('\n')
+	}
+	
+	d.model.Invalidate(changed...)
+	if enterErr := d.enter(); enterErr != nil {
+		return enterErr
+	}
+	return err
+}
+
+// reload 重新读取仓库列表
+func (d *dashboard) reload() error {
+	repos, err := d.backend.List()
+	if err != nil {
+		return fmt.Errorf("获取仓库列表失败: %w", err)
+	}
+	d.model.SetRepos(repos)
+	return nil
+}
+
+// forEach 依次处理仓库，单个失败不中断，最后汇总
+func forEach(repos []cache.Repository, fn func(repo cache.Repository) error) error {
+	failed := 0
+	for _, repo := range repos {
+		fmt.Printf("\n==> %s (%s)\n", repo.Name, repo.Path)
+		if err := fn(repo); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d 个仓库执行失败", failed)
+	}
+	return nil
+}
+
+// paths 返回仓库路径列表
+func paths(repos []cache.Repository) []string {
+	result := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		result = append(result, repo.Path)
+	}
+	return result
+}
+
+// openShell 在指定目录中启动交互式 shell
+func openShell(dir string) error {
+	shell := os.Getenv("SHELL")
+	if runtime.GOOS == "windows" {
+		shell = os.Getenv("COMSPEC")
+	}
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	
+	cmd := exec.Command(shell)
+	cmd.Dir = dir
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "PROJJ_REPO_PATH="+dir)
+	if err := cmd.Run(); err != nil {
+		// shell 中最后一条命令失败也会体现为非零退出码，不视为错误
+		if _, ok := err.(*exec.ExitError); !ok {
+			return fmt.Errorf("启动 shell 失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// clipboardCommands 按顺序尝试的剪贴板命令
+var clipboardCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// copyToClipboard 使用系统剪贴板命令复制文本，都不可用时通过 OSC 52 控制序列让终端复制
+func copyToClipboard(text string) error {
+	for _, args := range clipboardCommands {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err == nil {
+			return nil
+		}
+	}
+	return writeOSC52(os.Stdout, text)
+}
+
+// writeOSC52 输出 OSC 52 控制序列
+func writeOSC52(w io.Writer, text string) error {
+	_, err := fmt.Fprintf(w, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
+
+// splitKeys 将一次读取的输入拆分为按键，控制序列作为一个按键
+func splitKeys(chunk []byte) []string {
+	var keys []string
+	s := string(chunk)
+	for len(s) > 0 {
+		if s[0] == '\x1b' && len(s) > 1 && (s[1] == '[' || s[1] == 'O') {
+			// CSI 序列以 0x40-0x7e 之间的字符结束
+			end := 2
+			for end < len(s) && (s[end] < 0x40 || s[end] > 0x7e) {
+				end++
+			}
+			if end < len(s) {
+				end++
+			}
+			keys = append(keys, s[:end])
+			s = s[end:]
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s)
+		keys = append(keys, s[:size])
+		s = s[size:]
+	}
+	return keys
+}
\ No newline at end of file
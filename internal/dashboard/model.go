@@ -0,0 +1,558 @@
+package dashboard
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/query"
+	"github.com/atian25/projj-go/internal/termutil"
+)
+
+// Status 表示仓库的 git 状态
+type Status struct {
+	Branch string
+	Dirty  bool
+	Err    error
+}
+
+// GroupBy 表示仓库的分组方式
+type GroupBy int
+
+const (
+	GroupByOwner GroupBy = iota // 按 host/owner 分组
+	GroupByTag                  // 按标签分组，一个仓库可以出现在多个分组中
+)
+
+// ActionKind 表示需要在界面外执行的操作
+type ActionKind int
+
+const (
+	ActionNone    ActionKind = iota
+	ActionQuit               // 退出
+	ActionShell              // 在仓库目录中打开 shell
+	ActionPull               // 拉取更新
+	ActionRemove             // 从缓存中移除
+	ActionCopy               // 复制路径
+	ActionHook               // 执行 hook
+	ActionRefresh            // 重新读取 git 状态
+)
+
+// Action 表示按键产生的操作，Repos 为选中的仓库，没有选中时为光标所在的仓库
+type Action struct {
+	Kind  ActionKind
+	Repos []cache.Repository
+	Hook  string
+}
+
+// mode 表示当前的输入模式
+type mode int
+
+const (
+	modeNormal  mode = iota
+	modeFilter       // 输入过滤条件
+	modeHook         // 输入 hook 名称
+	modeConfirm      // 等待确认移除
+)
+
+// row 表示列表中的一行，repo 为 -1 时是分组标题
+type row struct {
+	group string
+	repo  int
+}
+
+// Model 表示界面状态，不涉及终端读写，便于测试
+type Model struct {
+	repos    []cache.Repository
+	statuses map[string]Status
+	pending  map[string]bool // 已请求但尚未返回的状态
+	hooks    []string
+	
+	groupBy  GroupBy
+	filter   string
+	compiled *query.Query
+	rows     []row
+	cursor   int
+	offset   int
+	selected map[string]bool
+	
+	mode    mode
+	input   []rune
+	confirm Action
+	Message string
+}
+
+// NewModel 创建界面状态
+func NewModel(repos []cache.Repository, hooks []string) *Model {
+	m := &Model{
+		statuses: make(map[string]Status),
+		pending:  make(map[string]bool),
+		selected: make(map[string]bool),
+		hooks:    hooks,
+	}
+	m.SetRepos(repos)
+	return m
+}
+
+// SetRepos 替换仓库列表，保留仍然存在的选择和状态
+func (m *Model) SetRepos(repos []cache.Repository) {
+	keep := m.currentPath()
+	m.repos = repos
+	exists := make(map[string]bool, len(repos))
+	for _, repo := range repos {
+		exists[repo.Path] = true
+	}
+	for path := range m.selected {
+		if !exists[path] {
+			delete(m.selected, path)
+		}
+	}
+	m.rebuild(keep)
+}
+
+// SetStatus 记录仓库的 git 状态
+func (m *Model) SetStatus(path string, status Status) {
+	m.statuses[path] = status
+	delete(m.pending, path)
+	// 过滤条件依赖 git 状态时需要重新过滤
+	if m.compiled != nil && m.compiled.UsesGit() {
+		m.rebuild(m.currentPath())
+	}
+}
+
+// Invalidate 清除仓库的状态，下次显示时重新读取
+func (m *Model) Invalidate(paths ...string) {
+	for _, path := range paths {
+		delete(m.statuses, path)
+		delete(m.pending, path)
+	}
+}
+
+// Dirty 实现 query.GitState，使用已读取的状态，未读取时视为没有修改
+func (m *Model) Dirty(path string) (bool, error) {
+	return m.statuses[path].Dirty, nil
+}
+
+// rebuild 按过滤条件和分组方式重新生成列表，光标尽量停留在 keep 路径的仓库上
+func (m *Model) rebuild(keep string) {
+	var visible []int
+	for i, repo := range m.repos {
+		if m.compiled != nil {
+			if ok, _ := m.compiled.Match(repo, m); !ok {
+				continue
+			}
+		}
+		visible = append(visible, i)
+	}
+	
+	groups := make(map[string][]int)
+	for _, i := range visible {
+		for _, group := range m.groupsOf(m.repos[i]) {
+			groups[group] = append(groups[group], i)
+		}
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	
+	m.rows = m.rows[:0]
+	for _, name := range names {
+		members := groups[name]
+		sort.SliceStable(members, func(a, b int) bool {
+			return strings.ToLower(m.repos[members[a]].Name) < strings.ToLower(m.repos[members[b]].Name)
+		})
+		m.rows = append(m.rows, row{group: name, repo: -1})
+		for _, i := range members {
+			m.rows = append(m.rows, row{group: name, repo: i})
+		}
+	}
+	
+	m.cursor = -1
+	for i, r := range m.rows {
+		if r.repo >= 0 && m.repos[r.repo].Path == keep {
+			m.cursor = i
+			break
+		}
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+		m.move(1)
+	}
+}
+
+// groupsOf 返回仓库所属的分组
+func (m *Model) groupsOf(repo cache.Repository) []string {
+	if m.groupBy == GroupByTag {
+		if len(repo.Tags) == 0 {
+			return []string{"(无标签)"}
+		}
+		return repo.Tags
+	}
+	
+	// 规范化 URL 为 host/owner/repo，去掉最后一段即为 host/owner
+	if canonical := cache.CanonicalURL(repo.URL); strings.Count(canonical, "/") >= 2 {
+		return []string{canonical[:strings.LastIndex(canonical, "/")]}
+	}
+	dir := filepath.Dir(repo.Path)
+	return []string{filepath.ToSlash(filepath.Join(filepath.Base(filepath.Dir(dir)), filepath.Base(dir)))}
+}
+
+// Current 返回光标所在的仓库，列表为空时返回 nil
+func (m *Model) Current() *cache.Repository {
+	if m.cursor < 0 || m.cursor >= len(m.rows) || m.rows[m.cursor].repo < 0 {
+		return nil
+	}
+	return &m.repos[m.rows[m.cursor].repo]
+}
+
+// currentPath 返回光标所在仓库的路径，没有时为空
+func (m *Model) currentPath() string {
+	if current := m.Current(); current != nil {
+		return current.Path
+	}
+	return ""
+}
+
+// targets 返回操作的仓库：有选中的仓库时为所有选中的，否则为光标所在的
+func (m *Model) targets() []cache.Repository {
+	var repos []cache.Repository
+	for _, repo := range m.repos {
+		if m.selected[repo.Path] {
+			repos = append(repos, repo)
+		}
+	}
+	if len(repos) == 0 {
+		if current := m.Current(); current != nil {
+			repos = append(repos, *current)
+		}
+	}
+	return repos
+}
+
+// move 将光标移动 delta 个仓库，跳过分组标题
+func (m *Model) move(delta int) {
+	step := 1
+	if delta < 0 {
+		step, delta = -1, -delta
+	}
+	for ; delta > 0; delta-- {
+		next := m.cursor + step
+		for next >= 0 && next < len(m.rows) && m.rows[next].repo < 0 {
+			next += step
+		}
+		if next < 0 || next >= len(m.rows) {
+			return
+		}
+		m.cursor = next
+	}
+}
+
+// setFilter 编译过滤条件，出错时保留上一次有效的结果
+func (m *Model) setFilter(filter string) {
+	m.filter = filter
+	if strings.TrimSpace(filter) == "" {
+		m.compiled = nil
+		m.Message = ""
+		m.rebuild(m.currentPath())
+		return
+	}
+	q, err := query.Compile(filter)
+	if err != nil {
+		m.Message = err.Error()
+		return
+	}
+	m.compiled = q
+	m.Message = ""
+	m.rebuild(m.currentPath())
+}
+
+// Handle 处理一个按键，返回需要在界面外执行的操作
+func (m *Model) Handle(key string) Action {
+	switch m.mode {
+	case modeFilter:
+		return m.handleFilter(key)
+	case modeHook:
+		return m.handleHook(key)
+	case modeConfirm:
+		m.mode = modeNormal
+		if key == "y" || key == "Y" {
+			action := m.confirm
+			m.selected = make(map[string]bool)
+			return action
+		}
+		m.Message = "已取消"
+		return Action{}
+	}
+	
+	m.Message = ""
+	switch key {
+	case "q", "\x1b", "\x03":
+		return Action{Kind: ActionQuit}
+	case "\x1b[A", "\x1bOA", "k", "\x10":
+		m.move(-1)
+	case "\x1b[B", "\x1bOB", "j", "\x0e":
+		m.move(1)
+	case "\x1b[5~":
+		m.move(-10)
+	case "\x1b[6~":
+		m.move(10)
+	case "\x1b[H", "\x1bOH":
+		m.cursor = 0
+		m.move(1)
+	case "\x1b[F", "\x1bOF":
+		m.cursor = len(m.rows)
+		m.move(-1)
+	case " ":
+		if current := m.Current(); current != nil {
+			if m.selected[current.Path] {
+				delete(m.selected, current.Path)
+			} else {
+				m.selected[current.Path] = true
+			}
+			m.move(1)
+		}
+	case "a":
+		// 已全部选中时取消选择
+		all := true
+		for _, r := range m.rows {
+			if r.repo >= 0 && !m.selected[m.repos[r.repo].Path] {
+				all = false
+				break
+			}
+		}
+		m.selected = make(map[string]bool)
+		if !all {
+			for _, r := range m.rows {
+				if r.repo >= 0 {
+					m.selected[m.repos[r.repo].Path] = true
+				}
+			}
+		}
+	case "/":
+		m.mode = modeFilter
+		m.input = []rune(m.filter)
+	case "g":
+		if m.groupBy == GroupByOwner {
+			m.groupBy = GroupByTag
+		} else {
+			m.groupBy = GroupByOwner
+		}
+		m.rebuild(m.currentPath())
+	case "r":
+		m.statuses = make(map[string]Status)
+		m.pending = make(map[string]bool)
+		return Action{Kind: ActionRefresh}
+	case "\r", "o":
+		if current := m.Current(); current != nil {
+			return Action{Kind: ActionShell, Repos: []cache.Repository{*current}}
+		}
+	case "p":
+		return m.action(ActionPull)
+	case "y":
+		return m.action(ActionCopy)
+	case "d":
+		action := m.action(ActionRemove)
+		if len(action.Repos) > 0 {
+			m.mode = modeConfirm
+			m.confirm = action
+			m.Message = fmt.Sprintf("从 projj 中移除 %d 个仓库（不删除文件）？(y/N)", len(action.Repos))
+		}
+	case "h":
+		if len(m.hooks) == 0 {
+			m.Message = "配置中没有 hook"
+			break
+		}
+		if len(m.targets()) > 0 {
+			m.mode = modeHook
+			m.input = m.input[:0]
+		}
+	}
+	return Action{}
+}
+
+// action 返回作用于目标仓库的操作
+func (m *Model) action(kind ActionKind) Action {
+	repos := m.targets()
+	if len(repos) == 0 {
+		return Action{}
+	}
+	return Action{Kind: kind, Repos: repos}
+}
+
+// handleFilter 处理过滤模式下的按键，每次输入都立即过滤
+func (m *Model) handleFilter(key string) Action {
+	switch key {
+	case "\r":
+		m.mode = modeNormal
+	case "\x1b", "\x03":
+		m.mode = modeNormal
+		m.setFilter("")
+	default:
+		if !m.edit(key) {
+			return Action{}
+		}
+		m.setFilter(string(m.input))
+	}
+	return Action{}
+}
+
+// handleHook 处理输入 hook 名称时的按键，Tab 补全
+func (m *Model) handleHook(key string) Action {
+	switch key {
+	case "\r":
+		m.mode = modeNormal
+		name := strings.TrimSpace(string(m.input))
+		for _, hook := range m.hooks {
+			if hook == name {
+				action := m.action(ActionHook)
+				action.Hook = name
+				return action
+			}
+		}
+		m.Message = "未配置 hook: " + name
+	case "\x1b", "\x03":
+		m.mode = modeNormal
+	case "\t":
+		prefix := string(m.input)
+		for _, hook := range m.hooks {
+			if strings.HasPrefix(hook, prefix) {
+				m.input = []rune(hook)
+				break
+			}
+		}
+	default:
+		m.edit(key)
+	}
+	return Action{}
+}
+
+// edit 处理输入框中的编辑按键，返回内容是否改变
+func (m *Model) edit(key string) bool {
+	switch key {
+	case "\x7f", "\b":
+		if len(m.input) == 0 {
+			return false
+		}
+		m.input = m.input[:len(m.input)-1]
+	case "\x15":
+		m.input = m.input[:0]
+	default:
+		r, size := utf8.DecodeRuneInString(key)
+		if size != len(key) || !unicode.IsPrint(r) {
+			return false
+		}
+		m.input = append(m.input, r)
+	}
+	return true
+}
+
+// Render 按终端大小生成每一行的内容，同时返回可见但还没有状态的仓库路径
+func (m *Model) Render(width, height int) ([]string, []string) {
+	listHeight := height - 3
+	if listHeight < 1 {
+		listHeight = 1
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+		// 光标在分组的第一个仓库时同时显示分组标题
+		if m.offset > 0 && m.rows[m.offset-1].repo < 0 {
+			m.offset--
+		}
+	}
+	if m.cursor >= m.offset+listHeight {
+		m.offset = m.cursor - listHeight + 1
+	}
+	
+	groupName := "host/owner"
+	if m.groupBy == GroupByTag {
+		groupName = "标签"
+	}
+	header := fmt.Sprintf("projj  %d/%d 个仓库  分组: %s", m.visibleCount(), len(m.repos), groupName)
+	if len(m.selected) > 0 {
+		header += fmt.Sprintf("  已选择 %d 个", len(m.selected))
+	}
+	if m.filter != "" {
+		header += "  过滤: " + m.filter
+	}
+	lines := []string{"\x1b[1m" + termutil.Truncate(header, width) + "\x1b[0m"}
+	
+	var missing []string
+	if m.compiled != nil && m.compiled.UsesGit() {
+		// 过滤条件依赖 git 状态时，被过滤掉的仓库也需要读取状态
+		for _, repo := range m.repos {
+			if _, ok := m.statuses[repo.Path]; !ok && !m.pending[repo.Path] {
+				m.pending[repo.Path] = true
+				missing = append(missing, repo.Path)
+			}
+		}
+	}
+	for i := m.offset; i < len(m.rows) && i < m.offset+listHeight; i++ {
+		r := m.rows[i]
+		if r.repo < 0 {
+			lines = append(lines, "\x1b[1;34m"+termutil.Truncate(r.group, width)+"\x1b[0m")
+			continue
+		}
+		
+		repo := m.repos[r.repo]
+		status, ok := m.statuses[repo.Path]
+		if !ok && !m.pending[repo.Path] {
+			m.pending[repo.Path] = true
+			missing = append(missing, repo.Path)
+		}
+		
+		mark := " "
+		if m.selected[repo.Path] {
+			mark = "*"
+		}
+		line := termutil.Truncate(fmt.Sprintf(" %s %s %-24s %-16s %s", mark, statusMark(status, ok), repo.Name, status.Branch, repo.Path), width)
+		if i == m.cursor {
+			line = "\x1b[7m" + line + "\x1b[0m"
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) < listHeight+1 {
+		lines = append(lines, "")
+	}
+	
+	switch m.mode {
+	case modeFilter:
+		lines = append(lines, termutil.Truncate("过滤: "+string(m.input), width))
+	case modeHook:
+		lines = append(lines, termutil.Truncate(fmt.Sprintf("hook (%s，Tab 补全): %s", strings.Join(m.hooks, ", "), string(m.input)), width))
+	default:
+		lines = append(lines, "\x1b[33m"+termutil.Truncate(m.Message, width)+"\x1b[0m")
+	}
+	lines = append(lines, "\x1b[2m"+termutil.Truncate("↑↓ 移动  空格 选择  a 全选  / 过滤  o 打开 shell  p 拉取  d 移除  y 复制路径  h 执行 hook  g 切换分组  r 刷新  q 退出", width)+"\x1b[0m")
+	return lines, missing
+}
+
+// visibleCount 返回满足过滤条件的仓库数量
+func (m *Model) visibleCount() int {
+	seen := make(map[int]bool)
+	for _, r := range m.rows {
+		if r.repo >= 0 {
+			seen[r.repo] = true
+		}
+	}
+	return len(seen)
+}
+
+// statusMark 返回状态标记：… 读取中，✓ 干净，● 有修改，? 读取失败
+func statusMark(status Status, loaded bool) string {
+	switch {
+	case !loaded:
+		return "…"
+	case status.Err != nil:
+		return "?"
+	case status.Dirty:
+		return "\x1b[31m●\x1b[39m"
+	default:
+		return "\x1b[32m✓\x1b[39m"
+	}
+}
\ No newline at end of file
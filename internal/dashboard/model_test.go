@@ -0,0 +1,250 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atian25/projj-go/internal/cache"
+)
+
+func testRepos() []cache.Repository {
+	return []cache.Repository{
+		{Name: "web", Path: "/projj/github.com/team/web", URL: "https://github.com/team/web.git", Tags: []string{"frontend"}},
+		{Name: "api", Path: "/projj/github.com/team/api", URL: "git@github.com:team/api.git", Tags: []string{"backend", "work"}},
+		{Name: "notes", Path: "/projj/gitlab.com/me/notes", URL: "https://gitlab.com/me/notes.git"},
+	}
+}
+
+// press 依次处理按键，返回最后一个操作
+func press(m *Model, keys ...string) Action {
+	var action Action
+	for _, key := range keys {
+		action = m.Handle(key)
+	}
+	return action
+}
+
+// rowNames 返回列表中每一行的文字，分组标题前加 #
+func rowNames(m *Model) []string {
+	var names []string
+	for _, r := range m.rows {
+		if r.repo < 0 {
+			names = append(names, "#"+r.group)
+		} else {
+			names = append(names, m.repos[r.repo].Name)
+		}
+	}
+	return names
+}
+
+func TestGrouping(t *testing.T) {
+	m := NewModel(testRepos(), nil)
+	
+	expected := "#github.com/team,api,web,#gitlab.com/me,notes"
+	if got := strings.Join(rowNames(m), ","); got != expected {
+		t.Errorf("Expected owner groups %q, got %q", expected, got)
+	}
+	if current := m.Current(); current == nil || current.Name != "api" {
+		t.Fatalf("Expected cursor on first repo, got %v", current)
+	}
+	
+	press(m, "g")
+	expected = "#(无标签),notes,#backend,api,#frontend,web,#work,api"
+	if got := strings.Join(rowNames(m), ","); got != expected {
+		t.Errorf("Expected tag groups %q, got %q", expected, got)
+	}
+	// 切换分组后光标停留在原来的仓库上
+	if current := m.Current(); current == nil || current.Name != "api" {
+		t.Errorf("Expected cursor to stay on api, got %v", current)
+	}
+}
+
+func TestMove(t *testing.T) {
+	m := NewModel(testRepos(), nil)
+	
+	press(m, "j", "j")
+	if current := m.Current(); current == nil || current.Name != "notes" {
+		t.Fatalf("Expected header to be skipped, got %v", current)
+	}
+	press(m, "j")
+	if current := m.Current(); current.Name != "notes" {
+		t.Errorf("Expected cursor to stop at end, got %s", current.Name)
+	}
+	press(m, "\x1b[H")
+	if current := m.Current(); current.Name != "api" {
+		t.Errorf("Expected home to move to first repo, got %s", current.Name)
+	}
+	press(m, "\x1b[A")
+	if current := m.Current(); current.Name != "api" {
+		t.Errorf("Expected cursor to stop at first repo, got %s", current.Name)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	m := NewModel(testRepos(), nil)
+	
+	press(m, "/", "w", "e")
+	if got := strings.Join(rowNames(m), ","); got != "#github.com/team,web" {
+		t.Errorf("Expected filter to apply while typing, got %q", got)
+	}
+	
+	// 无效的条件保留上一次的结果
+	press(m, "\x15", "t", "a", "g", ":", "(")
+	if m.Message == "" {
+		t.Error("Expected compile error message")
+	}
+	press(m, "\x7f", "w", "o", "r", "k", "\r")
+	if got := strings.Join(rowNames(m), ","); got != "#github.com/team,api" {
+		t.Errorf("Expected tag filter, got %q", got)
+	}
+	if m.mode != modeNormal || m.filter != "tag:work" {
+		t.Errorf("Expected filter to be kept after enter, got mode %d filter %q", m.mode, m.filter)
+	}
+	
+	// Esc 清除过滤条件
+	press(m, "/", "\x1b")
+	if len(rowNames(m)) != 5 {
+		t.Errorf("Expected filter to be cleared, got %v", rowNames(m))
+	}
+}
+
+func TestFilterDirty(t *testing.T) {
+	m := NewModel(testRepos(), nil)
+	press(m, "/")
+	for _, key := range "dirty:true" {
+		press(m, string(key))
+	}
+	press(m, "\r")
+	if len(rowNames(m)) != 0 {
+		t.Errorf("Expected no repos before status is loaded, got %v", rowNames(m))
+	}
+	
+	// 依赖 git 状态时需要读取所有仓库的状态
+	_, missing := m.Render(80, 20)
+	if len(missing) != 3 {
+		t.Fatalf("Expected status of all repos to be requested, got %v", missing)
+	}
+	
+	m.SetStatus("/projj/gitlab.com/me/notes", Status{Dirty: true})
+	if got := strings.Join(rowNames(m), ","); got != "#gitlab.com/me,notes" {
+		t.Errorf("Expected dirty repo after status update, got %q", got)
+	}
+}
+
+func TestSelection(t *testing.T) {
+	m := NewModel(testRepos(), nil)
+	
+	action := press(m, "p")
+	if action.Kind != ActionPull || len(action.Repos) != 1 || action.Repos[0].Name != "api" {
+		t.Errorf("Expected pull on current repo, got %+v", action)
+	}
+	
+	// 空格选择后光标下移
+	press(m, " ", "j", " ")
+	action = press(m, "y")
+	if action.Kind != ActionCopy || len(action.Repos) != 2 {
+		t.Fatalf("Expected copy on 2 selected repos, got %+v", action)
+	}
+	
+	press(m, "a")
+	if len(m.selected) != 3 {
+		t.Errorf("Expected all repos selected, got %d", len(m.selected))
+	}
+	press(m, "a")
+	if len(m.selected) != 0 {
+		t.Errorf("Expected selection cleared, got %d", len(m.selected))
+	}
+	
+	action = press(m, "\r")
+	if action.Kind != ActionShell || len(action.Repos) != 1 {
+		t.Errorf("Expected shell on current repo, got %+v", action)
+	}
+}
+
+func TestConfirmRemove(t *testing.T) {
+	m := NewModel(testRepos(), nil)
+	
+	if action := press(m, "d", "n"); action.Kind != ActionNone {
+		t.Errorf("Expected remove to be cancelled, got %+v", action)
+	}
+	
+	press(m, " ")
+	if action := press(m, "d"); action.Kind != ActionNone || m.mode != modeConfirm {
+		t.Fatalf("Expected confirmation before remove, got %+v", action)
+	}
+	action := press(m, "y")
+	if action.Kind != ActionRemove || len(action.Repos) != 1 || action.Repos[0].Name != "api" {
+		t.Errorf("Expected remove of selected repo, got %+v", action)
+	}
+	if len(m.selected) != 0 {
+		t.Error("Expected selection to be cleared after remove")
+	}
+	
+	// 移除后重新设置列表，光标停留在仍然存在的仓库上
+	press(m, "j")
+	m.SetRepos(testRepos()[:1])
+	if current := m.Current(); current == nil || current.Name != "web" {
+		t.Errorf("Expected cursor on remaining repo, got %v", current)
+	}
+}
+
+func TestHook(t *testing.T) {
+	m := NewModel(testRepos(), nil)
+	press(m, "h")
+	if m.mode != modeNormal || m.Message == "" {
+		t.Error("Expected message when no hooks configured")
+	}
+	
+	m = NewModel(testRepos(), []string{"build", "lint"})
+	action := press(m, "h", "l", "\t", "\r")
+	if action.Kind != ActionHook || action.Hook != "lint" || len(action.Repos) != 1 {
+		t.Errorf("Expected lint hook on current repo, got %+v", action)
+	}
+	
+	action = press(m, "h", "x", "\r")
+	if action.Kind != ActionNone || !strings.Contains(m.Message, "x") {
+		t.Errorf("Expected unknown hook to be rejected, got %+v (%s)", action, m.Message)
+	}
+}
+
+func TestRender(t *testing.T) {
+	m := NewModel(testRepos(), nil)
+	
+	lines, missing := m.Render(80, 5)
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 lines, got %d", len(lines))
+	}
+	// 列表高度为 2，只有第一个仓库可见
+	if len(missing) != 1 || missing[0] != "/projj/github.com/team/api" {
+		t.Errorf("Expected status of visible repo to be requested, got %v", missing)
+	}
+	if _, missing = m.Render(80, 5); len(missing) != 0 {
+		t.Errorf("Expected pending status not to be requested again, got %v", missing)
+	}
+	
+	m.SetStatus("/projj/github.com/team/api", Status{Branch: "main", Dirty: true})
+	lines, _ = m.Render(80, 5)
+	if !strings.Contains(lines[2], "main") || !strings.Contains(lines[2], "●") {
+		t.Errorf("Expected branch and dirty mark, got %q", lines[2])
+	}
+	
+	// 光标移出可见区域时滚动列表
+	press(m, "j", "j")
+	lines, _ = m.Render(80, 5)
+	if !strings.Contains(lines[2], "notes") {
+		t.Errorf("Expected list to scroll to notes, got %q", lines)
+	}
+	
+	m.Invalidate("/projj/github.com/team/api")
+	press(m, "\x1b[H")
+	if _, missing = m.Render(80, 5); len(missing) != 1 {
+		t.Errorf("Expected invalidated status to be requested again, got %v", missing)
+	}
+}
+
+func TestSplitKeys(t *testing.T) {
+	got := strings.Join(splitKeys([]byte("j\x1b[Bk\x1b[5~中\r")), "|")
+	if got != "j|\x1b[B|k|\x1b[5~|中|\r" {
+		t.Errorf("Unexpected keys: %q", got)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,145 @@
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// rename 便于测试时模拟跨文件系统移动
+var rename = os.Rename
+
+// Exists 检查路径是否存在
+func Exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// Move 移动目录或文件，跨文件系统（EXDEV）时回退为复制后删除
+func Move(src, dst string) error {
+	err := rename(src, dst)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	
+	if err := Copy(src, dst); err != nil {
+		// 清理复制了一半的目标，保留源文件
+		os.RemoveAll(dst)
+		return fmt.Errorf("跨文件系统复制失败: %w", err)
+	}
+	
+	if err := os.RemoveAll(src); err != nil {
+		return fmt.Errorf("删除源目录失败: %w", err)
+	}
+	
+	return nil
+}
+
+// Copy 递归复制目录或文件，保留权限和符号链接
+func Copy(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		return copySymlink(src, dst)
+	case info.IsDir():
+		return copyDir(src, dst, info)
+	default:
+		return copyFile(src, dst, info)
+	}
+}
+
+// copyDir 复制目录
+func copyDir(src, dst string, info os.FileInfo) error {
+	if err := os.MkdirAll(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+	
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	
+	for _, entry := range entries {
+		if err := Copy(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+			return err
+		}
+	}
+	
+	return os.Chmod(dst, info.Mode().Perm())
+}
+
+// copyFile 复制普通文件
+func copyFile(src, dst string, info os.FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	
+	return out.Close()
+}
+
+// copySymlink 复制符号链接本身而不是其指向的内容
+func copySymlink(src, dst string) error {
+	target, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+	return os.Symlink(target, dst)
+}
+
+// WriteFileAtomic 先写入同目录下的临时文件再重命名，避免中途失败留下不完整的文件
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	
+	// 出错时清理临时文件
+	fail := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	
+	if _, err := tmp.Write(data); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
\ No newline at end of file
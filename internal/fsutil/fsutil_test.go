@@ -0,0 +1,156 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+)
+
+func createTree(t *testing.T, root string) {
+	t.Helper()
+	
+	if err := os.MkdirAll(filepath.Join(root, ".git", "objects"), 0755); err != nil {
+		t.Fatalf("Failed to create dirs: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "README.md"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "run.sh"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	if runtime.GOOS != "windows" {
+		if err := os.Symlink("README.md", filepath.Join(root, "link.md")); err != nil {
+			t.Fatalf("Failed to create symlink: %v", err)
+		}
+	}
+}
+
+func checkTree(t *testing.T, root string) {
+	t.Helper()
+	
+	data, err := os.ReadFile(filepath.Join(root, "README.md"))
+	if err != nil || string(data) != "hello" {
+		t.Errorf("README.md not copied correctly: %v", err)
+	}
+	
+	info, err := os.Stat(filepath.Join(root, "run.sh"))
+	if err != nil {
+		t.Fatalf("run.sh missing: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0755 {
+		t.Errorf("Expected run.sh mode 0755, got %v", info.Mode().Perm())
+	}
+	
+	if _, err := os.Stat(filepath.Join(root, ".git", "objects")); err != nil {
+		t.Errorf("Nested directory not copied: %v", err)
+	}
+	
+	if runtime.GOOS != "windows" {
+		target, err := os.Readlink(filepath.Join(root, "link.md"))
+		if err != nil || target != "README.md" {
+			t.Errorf("Symlink not preserved: %v", err)
+		}
+	}
+}
+
+func TestCopy(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "fsutil-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	src := filepath.Join(tempDir, "src")
+	dst := filepath.Join(tempDir, "dst")
+	createTree(t, src)
+	
+	if err := Copy(src, dst); err != nil {
+		t.Fatalf("Copy() failed: %v", err)
+	}
+	
+	checkTree(t, dst)
+	
+	// 源目录应保持不变
+	if !Exists(filepath.Join(src, "README.md")) {
+		t.Error("Source should still exist after copy")
+	}
+}
+
+func TestMove(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "fsutil-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	src := filepath.Join(tempDir, "src")
+	dst := filepath.Join(tempDir, "dst")
+	createTree(t, src)
+	
+	if err := Move(src, dst); err != nil {
+		t.Fatalf("Move() failed: %v", err)
+	}
+	
+	checkTree(t, dst)
+	if Exists(src) {
+		t.Error("Source should not exist after move")
+	}
+}
+
+func TestMoveCrossDevice(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "fsutil-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	// 模拟跨文件系统的 rename 失败
+	originalRename := rename
+	rename = func(oldpath, newpath string) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+	}
+	defer func() { rename = originalRename }()
+	
+	src := filepath.Join(tempDir, "src")
+	dst := filepath.Join(tempDir, "dst")
+	createTree(t, src)
+	
+	if err := Move(src, dst); err != nil {
+		t.Fatalf("Move() should fall back to copy on EXDEV: %v", err)
+	}
+	
+	checkTree(t, dst)
+	if Exists(src) {
+		t.Error("Source should be removed after cross-device move")
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "fsutil-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	path := filepath.Join(tempDir, "cache.json")
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	
+	if err := WriteFileAtomic(path, []byte("new"), 0644); err != nil {
+		t.Fatalf("WriteFileAtomic() failed: %v", err)
+	}
+	
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new" {
+		t.Errorf("Expected file content 'new', got %q (%v)", data, err)
+	}
+	
+	// 不应遗留临时文件
+	entries, _ := os.ReadDir(tempDir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only the target file, got %d entries", len(entries))
+	}
+}
\ No newline at end of file
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"context"
+	"fmt"
+)
+
+// CreateBranch 从当前提交创建分支，不切换过去
+func CreateBranch(repoPath, branch string) error {
+	if _, err := runCaptured(context.Background(), repoPath, "branch", branch); err != nil {
+		return fmt.Errorf("创建分支 %s 失败: %w", branch, err)
+	}
+	return nil
+}
+
+// Checkout 切换到指定的分支或提交
+func Checkout(repoPath, ref string) error {
+	if _, err := runCaptured(context.Background(), repoPath, "checkout", "-q", ref); err != nil {
+		return fmt.Errorf("切换到 %s 失败: %w", ref, err)
+	}
+	return nil
+}
+
+// DeleteBranch 强制删除本地分支
+func DeleteBranch(repoPath, branch string) error {
+	if _, err := runCaptured(context.Background(), repoPath, "branch", "-q", "-D", branch); err != nil {
+		return fmt.Errorf("删除分支 %s 失败: %w", branch, err)
+	}
+	return nil
+}
+
+// BranchExists 判断本地分支是否存在
+func BranchExists(repoPath, branch string) bool {
+	_, err := runCaptured(context.Background(), repoPath, "rev-parse", "-q", "--verify", "refs/heads/"+branch)
+	return err == nil
+}
+
+// CommitAll 暂存工作区的所有修改并提交，返回新提交的短提交号；没有修改时返回空字符串
+func CommitAll(repoPath, message string) (string, error) {
+	if _, err := runCaptured(context.Background(), repoPath, "add", "-A"); err != nil {
+		return "", fmt.Errorf("暂存修改失败: %w", err)
+	}
+	// 退出码为 0 表示暂存区没有修改
+	if _, err := runCaptured(context.Background(), repoPath, "diff", "--cached", "--quiet"); err == nil {
+		return "", nil
+	}
+	if _, err := runCaptured(context.Background(), repoPath, "commit", "-q", "-m", message); err != nil {
+		return "", fmt.Errorf("提交失败: %w", err)
+	}
+	commit, err := runCaptured(context.Background(), repoPath, "rev-parse", "--short", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("获取提交号失败: %w", err)
+	}
+	return commit, nil
+}
+
+// Discard 丢弃工作区中所有未提交的修改和未跟踪的文件
+func Discard(repoPath string) error {
+	if _, err := runCaptured(context.Background(), repoPath, "reset", "-q", "--hard"); err != nil {
+		return fmt.Errorf("丢弃修改失败: %w", err)
+	}
+	if _, err := runCaptured(context.Background(), repoPath, "clean", "-q", "-fd"); err != nil {
+		return fmt.Errorf("清理未跟踪的文件失败: %w", err)
+	}
+	return nil
+}
+
+// Push 推送分支到 remote 并设置为上游分支
+func Push(ctx context.Context, repoPath, remote, branch string) error {
+	if _, err := runCaptured(ctx, repoPath, "push", "-q", "-u", remote, branch); err != nil {
+		return fmt.Errorf("推送分支 %s 失败: %w", branch, err)
+	}
+	return nil
+}
+
+// DeleteRemoteBranch 删除 remote 上的分支
+func DeleteRemoteBranch(ctx context.Context, repoPath, remote, branch string) error {
+	if _, err := runCaptured(ctx, repoPath, "push", "-q", remote, "--delete", branch); err != nil {
+		return fmt.Errorf("删除远程分支 %s 失败: %w", branch, err)
+	}
+	return nil
+}
\ No newline at end of file
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -130,6 +131,140 @@ func GetRemoteURL(repoPath string) (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// ErrNoRemote 表示仓库配置中没有指定的 remote
+var ErrNoRemote = errors.New("未配置 remote")
+
+// ReadRemoteURL 直接读取 .git/config 获取 remote URL，避免启动 git 子进程；
+// 配置中包含 include 等无法直接解析的内容时返回错误，调用方可回退到 GetRemoteURL
+func ReadRemoteURL(repoPath, remote string) (string, error) {
+	gitDir, err := resolveGitDir(repoPath)
+	if err != nil {
+		return "", err
+	}
+	
+	data, err := os.ReadFile(filepath.Join(gitDir, "config"))
+	if err != nil {
+		return "", fmt.Errorf("读取 git 配置失败: %w", err)
+	}
+	
+	section := fmt.Sprintf(`remote "%s"`, remote)
+	var current string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		
+		if strings.HasPrefix(line, "[") {
+			end := strings.Index(line, "]")
+			if end < 0 {
+				return "", fmt.Errorf("无法解析 git 配置: %s", line)
+			}
+			current = strings.TrimSpace(line[1:end])
+			// include 的内容无法在这里解析
+			if strings.HasPrefix(current, "include") {
+				return "", fmt.Errorf("git 配置包含 include，需要使用 git 读取")
+			}
+			line = strings.TrimSpace(line[end+1:])
+			if line == "" {
+				continue
+			}
+		}
+		
+		if current != section {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "url") {
+			return strings.Trim(strings.TrimSpace(value), `"`), nil
+		}
+	}
+	
+	return "", ErrNoRemote
+}
+
+// resolveGitDir 获取仓库的 git 目录，支持 .git 文件（submodule、worktree）
+func resolveGitDir(repoPath string) (string, error) {
+	gitPath := filepath.Join(repoPath, ".git")
+	info, err := os.Stat(gitPath)
+	if err != nil {
+		return "", fmt.Errorf("不是 Git 仓库: %s", repoPath)
+	}
+	if info.IsDir() {
+		return gitPath, nil
+	}
+	
+	data, err := os.ReadFile(gitPath)
+	if err != nil {
+		return "", fmt.Errorf("读取 .git 文件失败: %w", err)
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", fmt.Errorf("无法解析 .git 文件: %s", gitPath)
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(repoPath, gitDir)
+	}
+	
+	// worktree 的配置位于 commondir 指向的主仓库 git 目录中
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+		return filepath.Clean(commonDir), nil
+	}
+	
+	return filepath.Clean(gitDir), nil
+}
+
+// GetLocalConfig 获取仓库本地配置（.git/config）中的值，未设置时返回空字符串
+func GetLocalConfig(repoPath, key string) (string, error) {
+	cmd := exec.Command("git", "config", "--local", "--get", key)
+	cmd.Dir = repoPath
+	
+	output, err := cmd.Output()
+	if err != nil {
+		// 退出码 1 表示该键未设置
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", fmt.Errorf("获取配置 %s 失败: %w", key, err)
+	}
+	
+	return strings.TrimSpace(string(output)), nil
+}
+
+// SetLocalConfig 设置仓库本地配置（.git/config）中的值
+func SetLocalConfig(repoPath, key, value string) error {
+	cmd := exec.Command("git", "config", "--local", key, value)
+	cmd.Dir = repoPath
+	
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("设置配置 %s 失败: %w: %s", key, err, strings.TrimSpace(string(output)))
+	}
+	
+	return nil
+}
+
+// CurrentBranch 获取当前分支名，分离 HEAD 时返回短提交号
+func CurrentBranch(repoPath string) (string, error) {
+	cmd := exec.Command("git", "symbolic-ref", "--short", "-q", "HEAD")
+	cmd.Dir = repoPath
+	if output, err := cmd.Output(); err == nil {
+		return strings.TrimSpace(string(output)), nil
+	}
+	
+	cmd = exec.Command("git", "rev-parse", "--short", "HEAD")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("获取当前分支失败: %w", err)
+	}
+	return "(" + strings.TrimSpace(string(output)) + ")", nil
+}
+
 // Pull 拉取仓库更新
 func Pull(repoPath string) error {
 	cmd := exec.Command("git", "pull")
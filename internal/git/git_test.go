@@ -1,9 +1,13 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -383,4 +387,365 @@ func TestGetStatus(t *testing.T) {
 	if !isClean {
 		t.Error("Repository after commit should be clean")
 	}
+}
+
+func TestLocalConfig(t *testing.T) {
+	// 检查 git 命令是否可用
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	tempDir, err := os.MkdirTemp("", "git-config-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	cmd := exec.Command("git", "init")
+	cmd.Dir = tempDir
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to init git repo: %v", err)
+	}
+	
+	// 未设置的键返回空字符串
+	value, err := GetLocalConfig(tempDir, "user.email")
+	if err != nil {
+		t.Fatalf("GetLocalConfig() failed: %v", err)
+	}
+	if value != "" {
+		t.Errorf("Expected empty value for unset key, got '%s'", value)
+	}
+	
+	// 设置后可以读取
+	if err := SetLocalConfig(tempDir, "user.email", "dev@example.com"); err != nil {
+		t.Fatalf("SetLocalConfig() failed: %v", err)
+	}
+	value, err = GetLocalConfig(tempDir, "user.email")
+	if err != nil {
+		t.Fatalf("GetLocalConfig() failed: %v", err)
+	}
+	if value != "dev@example.com" {
+		t.Errorf("Expected 'dev@example.com', got '%s'", value)
+	}
+}
+
+func TestReadRemoteURL(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "git-read-remote-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	// 普通仓库
+	repoPath := filepath.Join(tempDir, "repo")
+	if err := os.MkdirAll(filepath.Join(repoPath, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git dir: %v", err)
+	}
+	config := `[core]
+	bare = false
+[remote "upstream"]
+	url = git@github.com:other/repo.git
+[remote "origin"]
+	url = git@github.com:user/repo.git
+`
+	if err := os.WriteFile(filepath.Join(repoPath, ".git", "config"), []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	
+	url, err := ReadRemoteURL(repoPath, "origin")
+	if err != nil {
+		t.Fatalf("ReadRemoteURL() failed: %v", err)
+	}
+	if url != "git@github.com:user/repo.git" {
+		t.Errorf("Expected origin URL, got '%s'", url)
+	}
+	
+	// 没有对应 remote
+	if _, err := ReadRemoteURL(repoPath, "missing"); !errors.Is(err, ErrNoRemote) {
+		t.Errorf("Expected ErrNoRemote, got %v", err)
+	}
+	
+	// .git 文件指向其他目录（submodule）
+	subPath := filepath.Join(tempDir, "sub")
+	if err := os.MkdirAll(subPath, 0755); err != nil {
+		t.Fatalf("Failed to create sub dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(subPath, ".git"), []byte("gitdir: ../repo/.git\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .git file: %v", err)
+	}
+	url, err = ReadRemoteURL(subPath, "origin")
+	if err != nil {
+		t.Fatalf("ReadRemoteURL() with gitdir file failed: %v", err)
+	}
+	if url != "git@github.com:user/repo.git" {
+		t.Errorf("Expected origin URL via gitdir, got '%s'", url)
+	}
+	
+	// include 需要回退到 git 命令
+	includePath := filepath.Join(tempDir, "include")
+	if err := os.MkdirAll(filepath.Join(includePath, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(includePath, ".git", "config"), []byte("[include]\n\tpath = other\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := ReadRemoteURL(includePath, "origin"); err == nil || errors.Is(err, ErrNoRemote) {
+		t.Errorf("Expected fallback error for include, got %v", err)
+	}
+}
+
+func TestCurrentBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	tempDir, err := os.MkdirTemp("", "git-branch-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	cmd := exec.Command("git", "init", "-b", "trunk")
+	cmd.Dir = tempDir
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to init git repo: %v", err)
+	}
+	
+	// 还没有提交时也能取得分支名
+	branch, err := CurrentBranch(tempDir)
+	if err != nil {
+		t.Fatalf("CurrentBranch() failed: %v", err)
+	}
+	if branch != "trunk" {
+		t.Errorf("Expected branch trunk, got %s", branch)
+	}
+	
+	if _, err := CurrentBranch(filepath.Join(tempDir, "missing")); err == nil {
+		t.Error("CurrentBranch() should fail outside a repository")
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	output := `# branch.oid 1234567890abcdef
+# branch.head main
+# branch.upstream origin/main
+# branch.ab +2 -3
+1 .M N... 100644 100644 100644 abc abc file.go
+2 R. N... 100644 100644 100644 abc abc R100 new.go	old.go
+u UU N... 100644 100644 100644 100644 abc abc abc conflict.go
+? notes.txt
+? tmp/
+`
+	status := parseStatus([]byte(output))
+	expected := Status{Branch: "main", Upstream: "origin/main", Ahead: 2, Behind: 3, Changed: 2, Untracked: 2, Conflicts: 1}
+	if *status != expected {
+		t.Errorf("Expected %+v, got %+v", expected, *status)
+	}
+	if !status.Dirty() || !status.Unpushed() {
+		t.Error("Expected status to be dirty and unpushed")
+	}
+	
+	detached := parseStatus([]byte("# branch.oid abc\n# branch.head (detached)\n"))
+	if !detached.Detached || detached.NoUpstream() || detached.Unpushed() || detached.Dirty() {
+		t.Errorf("Unexpected detached status: %+v", *detached)
+	}
+	
+	noUpstream := parseStatus([]byte("# branch.oid (initial)\n# branch.head trunk\n"))
+	if !noUpstream.NoUpstream() || !noUpstream.Unpushed() {
+		t.Errorf("Expected missing upstream: %+v", *noUpstream)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	tempDir, err := os.MkdirTemp("", "git-inspect-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	remote := filepath.Join(tempDir, "remote.git")
+	repo := filepath.Join(tempDir, "repo")
+	run := func(dir string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+	run(tempDir, "init", "-q", "--bare", remote)
+	run(tempDir, "init", "-q", "-b", "main", repo)
+	run(repo, "commit", "-q", "--allow-empty", "-m", "first")
+	run(repo, "remote", "add", "origin", remote)
+	run(repo, "push", "-q", "-u", "origin", "main")
+	run(repo, "commit", "-q", "--allow-empty", "-m", "second")
+	os.WriteFile(filepath.Join(repo, "stashed.txt"), []byte("x"), 0644)
+	run(repo, "add", "stashed.txt")
+	run(repo, "stash", "-q")
+	os.WriteFile(filepath.Join(repo, "new.txt"), []byte("x"), 0644)
+	
+	status, err := Inspect(repo)
+	if err != nil {
+		t.Fatalf("Inspect() failed: %v", err)
+	}
+	expected := Status{Branch: "main", Upstream: "origin/main", Ahead: 1, Untracked: 1, Stashes: 1}
+	if *status != expected {
+		t.Errorf("Expected %+v, got %+v", expected, *status)
+	}
+	
+	// 分离 HEAD 时分支为短提交号
+	run(repo, "checkout", "-q", "--detach")
+	status, err = Inspect(repo)
+	if err != nil {
+		t.Fatalf("Inspect() failed: %v", err)
+	}
+	if !status.Detached || !strings.HasPrefix(status.Branch, "(") {
+		t.Errorf("Expected detached HEAD, got %+v", *status)
+	}
+	
+	if _, err := Inspect(filepath.Join(tempDir, "missing")); err == nil {
+		t.Error("Inspect() should fail outside a repository")
+	}
+}
+
+func TestFetchAndPull(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	tempDir, err := os.MkdirTemp("", "git-update-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	remote := filepath.Join(tempDir, "remote.git")
+	repo := filepath.Join(tempDir, "repo")
+	other := filepath.Join(tempDir, "other")
+	run := func(dir string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+	run(tempDir, "init", "-q", "--bare", remote)
+	run(tempDir, "init", "-q", "-b", "main", repo)
+	run(repo, "commit", "-q", "--allow-empty", "-m", "first")
+	run(repo, "remote", "add", "origin", remote)
+	run(repo, "push", "-q", "-u", "origin", "main")
+	run(tempDir, "clone", "-q", "-b", "main", remote, other)
+	run(other, "commit", "-q", "--allow-empty", "-m", "second")
+	run(other, "push", "-q")
+	
+	ctx := context.Background()
+	update, err := Fetch(ctx, repo)
+	if err != nil || !update.Updated {
+		t.Fatalf("Expected fetch to update, got %+v, %v", update, err)
+	}
+	update, err = Fetch(ctx, repo)
+	if err != nil || update.Updated {
+		t.Errorf("Expected fetch to be up to date, got %+v, %v", update, err)
+	}
+	
+	update, err = PullContext(ctx, repo, true)
+	if err != nil || !update.Updated {
+		t.Fatalf("Expected pull to update, got %+v, %v", update, err)
+	}
+	update, err = PullContext(ctx, repo, true)
+	if err != nil || update.Updated {
+		t.Errorf("Expected pull to be up to date, got %+v, %v", update, err)
+	}
+	
+	// 分叉后无法快进合并
+	run(other, "commit", "-q", "--allow-empty", "-m", "third")
+	run(other, "push", "-q")
+	run(repo, "commit", "-q", "--allow-empty", "-m", "local")
+	update, err = PullContext(ctx, repo, true)
+	if err == nil || update.Updated || update.Output == "" {
+		t.Errorf("Expected fast-forward failure with output, got %+v, %v", update, err)
+	}
+	
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := Fetch(cancelled, repo); err == nil {
+		t.Error("Fetch() should fail with cancelled context")
+	}
+}
+
+func TestErrorLine(t *testing.T) {
+	output := "Fetching origin\nfatal: Could not read from remote repository.\n\nPlease make sure you have the correct access rights"
+	if line := errorLine(output); line != "fatal: Could not read from remote repository." {
+		t.Errorf("Unexpected error line: %q", line)
+	}
+	if line := errorLine("Already up to date.\n"); line != "Already up to date." {
+		t.Errorf("Unexpected error line: %q", line)
+	}
+}
+
+func TestGrep(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	repo, err := os.MkdirTemp("", "git-grep-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(repo)
+	
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+	run("init", "-q")
+	os.MkdirAll(filepath.Join(repo, "src"), 0755)
+	os.WriteFile(filepath.Join(repo, "src", "main.go"), []byte("package main\n// TODO: fix\nfunc todoList() {}\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "data.bin"), []byte("TODO\x00binary"), 0644)
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+	os.WriteFile(filepath.Join(repo, "untracked.txt"), []byte("TODO later\n"), 0644)
+	
+	ctx := context.Background()
+	matches, err := Grep(ctx, repo, "TODO", GrepOptions{})
+	if err != nil {
+		t.Fatalf("Grep() failed: %v", err)
+	}
+	expected := []GrepMatch{{Path: "src/main.go", Line: 2, Text: "// TODO: fix"}}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, matches)
+	}
+	
+	// 忽略大小写后匹配 todoList，按单词匹配时排除
+	matches, _ = Grep(ctx, repo, "todo", GrepOptions{IgnoreCase: true})
+	if len(matches) != 2 {
+		t.Errorf("Expected 2 case-insensitive matches, got %+v", matches)
+	}
+	matches, _ = Grep(ctx, repo, "todo", GrepOptions{IgnoreCase: true, Word: true})
+	if len(matches) != 1 {
+		t.Errorf("Expected 1 word match, got %+v", matches)
+	}
+	
+	matches, _ = Grep(ctx, repo, "TODO", GrepOptions{Untracked: true, FilesOnly: true})
+	expected = []GrepMatch{{Path: "src/main.go"}, {Path: "untracked.txt"}}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, matches)
+	}
+	
+	matches, err = Grep(ctx, repo, "fix(", GrepOptions{Fixed: true})
+	if err != nil || len(matches) != 0 {
+		t.Errorf("Expected no matches, got %+v, %v", matches, err)
+	}
+	if _, err := Grep(ctx, repo, "fix(", GrepOptions{}); err == nil {
+		t.Error("Grep() should fail with invalid pattern")
+	}
 }
\ No newline at end of file
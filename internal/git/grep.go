@@ -0,0 +1,107 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// GrepOptions 表示搜索仓库内容的选项
+type GrepOptions struct {
+	IgnoreCase bool // 忽略大小写
+	Fixed      bool // 按固定字符串而不是正则表达式匹配
+	Word       bool // 只匹配完整的单词
+	Untracked  bool // 同时搜索未跟踪但没有被 .gitignore 忽略的文件
+	FilesOnly  bool // 只返回包含匹配的文件，Line 为 0
+}
+
+// GrepMatch 表示一处匹配
+type GrepMatch struct {
+	Path string `json:"path"` // 相对于仓库根目录的路径，以 / 分隔
+	Line int    `json:"line"` // 行号，从 1 开始
+	Text string `json:"text"` // 匹配的行内容
+}
+
+// Grep 使用 git grep 搜索仓库中已跟踪的文件，自动跳过二进制文件；
+// 正则表达式为 POSIX 扩展语法，没有匹配时返回空结果
+func Grep(ctx context.Context, repoPath, pattern string, opts GrepOptions) ([]GrepMatch, error) {
+	args := []string{"-c", "core.quotePath=false", "grep", "-I", "--null", "--no-color", "--full-name"}
+	if opts.FilesOnly {
+		args = append(args, "-l")
+	} else {
+		args = append(args, "-n")
+	}
+	if opts.Fixed {
+		args = append(args, "-F")
+	} else {
+		args = append(args, "-E")
+	}
+	if opts.IgnoreCase {
+		args = append(args, "-i")
+	}
+	if opts.Word {
+		args = append(args, "-w")
+	}
+	if opts.Untracked {
+		args = append(args, "--untracked")
+	}
+	args = append(args, "-e", pattern)
+	
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoPath
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		// 退出码 1 表示没有匹配
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && stderr.Len() == 0 {
+			return nil, nil
+		}
+		if line := errorLine(stderr.String()); line != "" {
+			return nil, fmt.Errorf("搜索失败: %w: %s", err, line)
+		}
+		return nil, fmt.Errorf("搜索失败: %w", err)
+	}
+	
+	return parseGrep(output, opts.FilesOnly), nil
+}
+
+// parseGrep 解析 git grep --null 的输出：每行为 路径\0行号\0内容，只列文件时为 路径\0
+func parseGrep(output []byte, filesOnly bool) []GrepMatch {
+	var matches []GrepMatch
+	if filesOnly {
+		for _, path := range bytes.Split(output, []byte{0}) {
+			if path = bytes.TrimSpace(path); len(path) > 0 {
+				matches = append(matches, GrepMatch{Path: string(path)})
+			}
+		}
+		return matches
+	}
+	
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		parts := bytes.SplitN(line, []byte{0}, 3)
+		if len(parts) != 3 {
+			continue
+		}
+		n, err := strconv.Atoi(string(parts[1]))
+		if err != nil {
+			continue
+		}
+		matches = append(matches, GrepMatch{
+			Path: string(parts[0]),
+			Line: n,
+			Text: string(bytes.TrimSuffix(parts[2], []byte("\r"))),
+		})
+	}
+	return matches
+}
+
+// Available 判断系统中是否可以执行 git
+func Available() bool {
+	_, err := exec.LookPath("git")
+	return err == nil
+}
\ No newline at end of file
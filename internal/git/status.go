@@ -0,0 +1,94 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Status 表示仓库的详细状态
+type Status struct {
+	Branch    string `json:"branch"`    // 当前分支，分离 HEAD 时为括号中的短提交号
+	Detached  bool   `json:"detached"`  // 是否处于分离 HEAD 状态
+	Upstream  string `json:"upstream"`  // 上游分支，未设置时为空
+	Ahead     int    `json:"ahead"`     // 领先上游的提交数
+	Behind    int    `json:"behind"`    // 落后上游的提交数
+	Changed   int    `json:"changed"`   // 已跟踪文件中有修改的数量，包括暂存区
+	Untracked int    `json:"untracked"` // 未跟踪的文件数量
+	Conflicts int    `json:"conflicts"` // 有冲突的文件数量
+	Stashes   int    `json:"stashes"`   // stash 的数量
+}
+
+// Dirty 判断工作区是否有未提交的修改，包括未跟踪的文件
+func (s *Status) Dirty() bool {
+	return s.Changed+s.Untracked+s.Conflicts > 0
+}
+
+// TrackedChanges 判断已跟踪的文件是否有未提交的修改或冲突，不包括未跟踪的文件
+func (s *Status) TrackedChanges() bool {
+	return s.Changed+s.Conflicts > 0
+}
+
+// NoUpstream 判断当前分支是否没有设置上游分支，分离 HEAD 时不算
+func (s *Status) NoUpstream() bool {
+	return !s.Detached && s.Upstream == ""
+}
+
+// Unpushed 判断是否有未推送的提交：领先上游，或当前分支没有上游
+func (s *Status) Unpushed() bool {
+	return s.Ahead > 0 || s.NoUpstream()
+}
+
+// Inspect 读取仓库的分支、修改、上游和 stash 状态
+func Inspect(repoPath string) (*Status, error) {
+	cmd := exec.Command("git", "status", "--porcelain=v2", "--branch")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("获取仓库状态失败: %w", err)
+	}
+	
+	status := parseStatus(output)
+	if status.Detached {
+		if branch, err := CurrentBranch(repoPath); err == nil {
+			status.Branch = branch
+		}
+	}
+	
+	// 没有 stash 时 refs/stash 不存在，命令失败视为 0
+	cmd = exec.Command("git", "rev-list", "--walk-reflogs", "--count", "refs/stash", "--")
+	cmd.Dir = repoPath
+	if output, err := cmd.Output(); err == nil {
+		status.Stashes, _ = strconv.Atoi(strings.TrimSpace(string(output)))
+	}
+	
+	return status, nil
+}
+
+// parseStatus 解析 git status --porcelain=v2 --branch 的输出
+func parseStatus(output []byte) *Status {
+	status := &Status{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "# branch.head "):
+			status.Branch = strings.TrimPrefix(line, "# branch.head ")
+			status.Detached = status.Branch == "(detached)"
+		case strings.HasPrefix(line, "# branch.upstream "):
+			status.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			fmt.Sscanf(strings.TrimPrefix(line, "# branch.ab "), "+%d -%d", &status.Ahead, &status.Behind)
+		case strings.HasPrefix(line, "1 "), strings.HasPrefix(line, "2 "):
+			status.Changed++
+		case strings.HasPrefix(line, "u "):
+			status.Conflicts++
+		case strings.HasPrefix(line, "? "):
+			status.Untracked++
+		}
+	}
+	return status
+}
\ No newline at end of file
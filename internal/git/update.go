@@ -0,0 +1,98 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Update 表示一次 fetch 或 pull 的结果
+type Update struct {
+	Updated bool   // 是否获取到了新的提交
+	Output  string // git 的输出，包括标准错误
+}
+
+// Fetch 从当前分支的上游 remote（没有上游时为 origin）获取更新，输出被捕获而不是写到终端，便于并发执行
+func Fetch(ctx context.Context, repoPath string) (*Update, error) {
+	before := refsState(ctx, repoPath)
+	output, err := runCaptured(ctx, repoPath, "fetch", "--prune")
+	update := &Update{Output: output}
+	if err != nil {
+		return update, fmt.Errorf("获取更新失败: %w", err)
+	}
+	update.Updated = refsState(ctx, repoPath) != before
+	return update, nil
+}
+
+// PullContext 拉取当前分支的更新，ffOnly 为 true 时只允许快进合并；
+// 与 Pull 不同，输出被捕获而不是写到终端，便于并发执行
+func PullContext(ctx context.Context, repoPath string, ffOnly bool) (*Update, error) {
+	args := []string{"pull"}
+	if ffOnly {
+		args = append(args, "--ff-only")
+	}
+	
+	before := headState(ctx, repoPath)
+	output, err := runCaptured(ctx, repoPath, args...)
+	update := &Update{Output: output}
+	if err != nil {
+		return update, fmt.Errorf("拉取更新失败: %w", err)
+	}
+	update.Updated = headState(ctx, repoPath) != before
+	return update, nil
+}
+
+// runCaptured 在仓库中执行 git 命令并返回合并后的输出；
+// 禁止 git 交互式地询问凭据，避免并发执行时卡住，ctx 取消或超时时终止进程
+func runCaptured(ctx context.Context, repoPath string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoPath
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	// ssh 等子进程可能在 git 退出后仍持有输出管道
+	cmd.WaitDelay = time.Second
+	
+	output, err := cmd.CombinedOutput()
+	text := strings.TrimSpace(string(output))
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return text, fmt.Errorf("执行超时")
+		}
+		if last := errorLine(text); last != "" {
+			return text, fmt.Errorf("%w: %s", err, last)
+		}
+		return text, err
+	}
+	return text, nil
+}
+
+// refsState 返回远程分支和标签的当前指向，用于判断 fetch 是否有更新
+func refsState(ctx context.Context, repoPath string) string {
+	cmd := exec.CommandContext(ctx, "git", "for-each-ref", "--format=%(objectname) %(refname)", "refs/remotes", "refs/tags")
+	cmd.Dir = repoPath
+	output, _ := cmd.Output()
+	return string(output)
+}
+
+// headState 返回 HEAD 指向的提交，没有提交时为空
+func headState(ctx context.Context, repoPath string) string {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "-q", "--verify", "HEAD")
+	cmd.Dir = repoPath
+	output, _ := cmd.Output()
+	return strings.TrimSpace(string(output))
+}
+
+// errorLine 返回输出中最后一条 fatal 或 error 信息，没有时为最后一个非空行
+func errorLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "fatal:") || strings.HasPrefix(line, "error:") {
+			return line
+		}
+	}
+	return strings.TrimSpace(lines[len(lines)-1])
+}
\ No newline at end of file
@@ -0,0 +1,119 @@
+package hook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/atian25/projj-go/internal/config"
+)
+
+// Options 表示执行 hook 时的选项
+type Options struct {
+	Dir    string    // 工作目录
+	Env    []string  // 额外的环境变量，格式为 KEY=VALUE
+	Stdin  io.Reader // 为空时使用 os.Stdin
+	Stdout io.Writer // 为空时使用 os.Stdout
+	Stderr io.Writer // 为空时使用 os.Stderr
+}
+
+// WaitDelay Exec 的命令被取消后等待其输出关闭的最长时间
+var WaitDelay = time.Second
+
+// GetHooksDir 获取 hook 脚本目录，与原版 projj 一致位于配置目录下的 hooks
+func GetHooksDir() string {
+	return filepath.Join(config.GetConfigDir(), "hooks")
+}
+
+// Lookup 从配置中查找 hook 命令
+func Lookup(hooks map[string]string, name string) (string, bool) {
+	command, ok := hooks[name]
+	if !ok || command == "" {
+		return "", false
+	}
+	return command, true
+}
+
+// Run 通过系统 shell 执行 hook 命令，输出直接透传给调用方
+func Run(command string, opts Options) error {
+	cmd := shellCommand(context.Background(), command)
+	if err := run(cmd, opts); err != nil {
+		return fmt.Errorf("执行命令 %q 失败: %w", command, err)
+	}
+	
+	return nil
+}
+
+// Exec 执行任意命令，只有一个参数时通过系统 shell 执行以便使用管道和 &&，
+// 否则直接执行；命令在独立的进程组中运行，不能读取终端，ctx 取消时终止整个进程组
+func Exec(ctx context.Context, args []string, opts Options) error {
+	if len(args) == 0 {
+		return fmt.Errorf("没有要执行的命令")
+	}
+	
+	cmd := shellCommand(ctx, args[0])
+	if len(args) > 1 {
+		cmd = exec.CommandContext(ctx, args[0], args[1:]...)
+	}
+	setProcessGroup(cmd)
+	// 终止后仍有子进程持有输出时，最多再等待 WaitDelay 就返回
+	cmd.WaitDelay = WaitDelay
+	return run(cmd, opts)
+}
+
+// ExitCode 返回命令的退出码，命令不存在时为 127，其他无法执行的情况为 1，成功时为 0
+func ExitCode(err error) int {
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr) && exitErr.ExitCode() > 0:
+		return exitErr.ExitCode()
+	case errors.Is(err, exec.ErrNotFound):
+		return 127
+	}
+	return 1
+}
+
+// run 按选项设置命令的工作目录、环境变量和输入输出后执行
+func run(cmd *exec.Cmd, opts Options) error {
+	cmd.Dir = opts.Dir
+	cmd.Env = buildEnv(opts.Env)
+	
+	cmd.Stdin = opts.Stdin
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+	cmd.Stdout = opts.Stdout
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+	cmd.Stderr = opts.Stderr
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	
+	return cmd.Run()
+}
+
+// shellCommand 根据平台构造 shell 命令
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// buildEnv 构造子进程环境变量，hooks 目录会被加入 PATH 以便直接调用其中的脚本
+func buildEnv(extra []string) []string {
+	env := os.Environ()
+	path := GetHooksDir() + string(os.PathListSeparator) + os.Getenv("PATH")
+	env = append(env, "PATH="+path)
+	return append(env, extra...)
+}
\ No newline at end of file
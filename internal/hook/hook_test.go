@@ -0,0 +1,195 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLookup(t *testing.T) {
+	hooks := map[string]string{
+		"postadd": "echo added",
+		"empty":   "",
+	}
+	
+	if command, ok := Lookup(hooks, "postadd"); !ok || command != "echo added" {
+		t.Errorf("Expected postadd hook 'echo added', got '%s' (%v)", command, ok)
+	}
+	
+	if _, ok := Lookup(hooks, "empty"); ok {
+		t.Error("Empty hook should be treated as not configured")
+	}
+	
+	if _, ok := Lookup(hooks, "missing"); ok {
+		t.Error("Missing hook should not be found")
+	}
+	
+	if _, ok := Lookup(nil, "postadd"); ok {
+		t.Error("Nil hooks should not contain any hook")
+	}
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell hook test on windows")
+	}
+	
+	tempDir, err := os.MkdirTemp("", "projj-hook-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	// 测试工作目录、环境变量和输出
+	var stdout bytes.Buffer
+	err = Run("pwd; echo $PROJJ_REPO_PATH", Options{
+		Dir:    tempDir,
+		Env:    []string{"PROJJ_REPO_PATH=/path/to/repo"},
+		Stdout: &stdout,
+	})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	
+	output := stdout.String()
+	realDir, _ := filepath.EvalSymlinks(tempDir)
+	if !strings.Contains(output, realDir) {
+		t.Errorf("Expected output to contain working dir %s, got %s", realDir, output)
+	}
+	if !strings.Contains(output, "/path/to/repo") {
+		t.Errorf("Expected output to contain env value, got %s", output)
+	}
+	
+	// 测试非零退出码
+	err = Run("exit 3", Options{Dir: tempDir, Stdout: &stdout})
+	if err == nil {
+		t.Error("Run() should fail when command exits with non-zero code")
+	}
+}
+
+func TestRunHooksDirInPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell hook test on windows")
+	}
+	
+	tempDir, err := os.MkdirTemp("", "projj-hook-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	originalConfigDir := os.Getenv("PROJJ_CONFIG_DIR")
+	os.Setenv("PROJJ_CONFIG_DIR", tempDir)
+	defer os.Setenv("PROJJ_CONFIG_DIR", originalConfigDir)
+	
+	// 在 hooks 目录中创建脚本
+	hooksDir := GetHooksDir()
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		t.Fatalf("Failed to create hooks dir: %v", err)
+	}
+	script := filepath.Join(hooksDir, "projj-test-hook")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho from-hooks-dir\n"), 0755); err != nil {
+		t.Fatalf("Failed to create hook script: %v", err)
+	}
+	
+	var stdout bytes.Buffer
+	if err := Run("projj-test-hook", Options{Dir: tempDir, Stdout: &stdout}); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	
+	if !strings.Contains(stdout.String(), "from-hooks-dir") {
+		t.Errorf("Expected script in hooks dir to be executed, got %s", stdout.String())
+	}
+}
+
+func TestExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell exec test on windows")
+	}
+	
+	tempDir, err := os.MkdirTemp("", "projj-exec-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	// 多个参数时直接执行，不经过 shell 展开
+	var stdout bytes.Buffer
+	err = Exec(context.Background(), []string{"echo", "$PROJJ_REPO_NAME"}, Options{
+		Dir:    tempDir,
+		Env:    []string{"PROJJ_REPO_NAME=api"},
+		Stdout: &stdout,
+	})
+	if err != nil || strings.TrimSpace(stdout.String()) != "$PROJJ_REPO_NAME" {
+		t.Errorf("Expected literal argument, got %q (%v)", stdout.String(), err)
+	}
+	
+	// 只有一个参数时通过 shell 执行
+	stdout.Reset()
+	err = Exec(context.Background(), []string{"echo $PROJJ_REPO_NAME | tr a-z A-Z"}, Options{
+		Dir:    tempDir,
+		Env:    []string{"PROJJ_REPO_NAME=api"},
+		Stdout: &stdout,
+	})
+	if err != nil || strings.TrimSpace(stdout.String()) != "API" {
+		t.Errorf("Expected shell output 'API', got %q (%v)", stdout.String(), err)
+	}
+	
+	err = Exec(context.Background(), []string{"exit 3"}, Options{Dir: tempDir})
+	if code := ExitCode(err); code != 3 {
+		t.Errorf("Expected exit code 3, got %d (%v)", code, err)
+	}
+	
+	err = Exec(context.Background(), []string{"projj-no-such-command", "arg"}, Options{Dir: tempDir})
+	if code := ExitCode(err); code != 127 {
+		t.Errorf("Expected exit code 127, got %d (%v)", code, err)
+	}
+	
+	if code := ExitCode(nil); code != 0 {
+		t.Errorf("Expected exit code 0, got %d", code)
+	}
+	
+	if err := Exec(context.Background(), nil, Options{}); err == nil {
+		t.Error("Exec() should fail without command")
+	}
+}
+
+func TestExecCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell exec test on windows")
+	}
+	
+	tempDir, err := os.MkdirTemp("", "projj-exec-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	// shell 启动的后台子进程持有输出，取消时需要终止整个进程组
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	
+	var stdout, stderr bytes.Buffer
+	start := time.Now()
+	err = Exec(ctx, []string{"(sleep 1; touch marker; echo done) & wait"}, Options{Dir: tempDir, Stdout: &stdout, Stderr: &stderr})
+	if err == nil {
+		t.Error("Exec() should fail when the context is cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Exec() should return soon after cancel, took %v", elapsed)
+	}
+	
+	// 子进程被终止后不会继续执行
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := os.Stat(filepath.Join(tempDir, "marker")); err == nil {
+		t.Error("Child processes should be killed with the command")
+	}
+	if strings.Contains(stdout.String(), "done") {
+		t.Errorf("Command should be killed, got output %q", stdout.String())
+	}
+}
\ No newline at end of file
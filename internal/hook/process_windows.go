@@ -0,0 +1,11 @@
+//go:build windows
+
+package hook
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup 在 Windows 上不创建进程组，取消时只终止命令本身，
+// 残留的子进程由 WaitDelay 保证不会阻塞等待
+func setProcessGroup(cmd *exec.Cmd) {}
\ No newline at end of file
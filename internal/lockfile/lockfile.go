@@ -0,0 +1,166 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout 获取锁的默认等待时间
+var DefaultTimeout = 10 * time.Second
+
+// StaleAfter 其他主机上的进程持有锁超过该时间时视为失效，这类进程无法检查是否存活；
+// 本机进程只在退出后才视为失效，避免耗时较长的 sync、import 等操作被抢走锁
+var StaleAfter = 10 * time.Minute
+
+// retryInterval 锁被占用时的重试间隔
+const retryInterval = 50 * time.Millisecond
+
+// LockedError 表示等待锁超时
+type LockedError struct {
+	Path     string
+	PID      int
+	Since    time.Time
+	Hostname string
+}
+
+func (e *LockedError) Error() string {
+	holder := "未知进程"
+	if e.PID > 0 {
+		holder = fmt.Sprintf("进程 %d", e.PID)
+	}
+	if e.Hostname != "" {
+		holder += "@" + e.Hostname
+	}
+	if !e.Since.IsZero() {
+		holder += fmt.Sprintf("（自 %s 起）", e.Since.Format("15:04:05"))
+	}
+	return fmt.Sprintf("%s 正被%s占用，等待超时；如确认该进程已不存在，可删除锁文件 %s", strings.TrimSuffix(e.Path, ".lock"), holder, e.Path)
+}
+
+// Lock 表示一个基于锁文件的跨进程建议锁
+type Lock struct {
+	path string
+}
+
+// holder 表示锁文件中记录的持有者信息
+type holder struct {
+	pid      int
+	hostname string
+	since    time.Time
+}
+
+// Acquire 为目标文件获取锁，锁文件为目标文件加 .lock 后缀
+func Acquire(target string, timeout time.Duration) (*Lock, error) {
+	path := target + ".lock"
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("创建锁目录失败: %w", err)
+	}
+	
+	deadline := time.Now().Add(timeout)
+	for {
+		err := tryCreate(path)
+		if err == nil {
+			return &Lock{path: path}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("创建锁文件失败: %w", err)
+		}
+		
+		// 锁已存在，检查是否失效
+		h, readErr := readHolder(path)
+		if readErr == nil && h.stale() {
+			removeIfUnchanged(path, h)
+			continue
+		}
+		
+		if time.Now().After(deadline) {
+			return nil, &LockedError{Path: path, PID: h.pid, Since: h.since, Hostname: h.hostname}
+		}
+		time.Sleep(retryInterval)
+	}
+}
+
+// Release 释放锁
+func (l *Lock) Release() error {
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("释放锁失败: %w", err)
+	}
+	return nil
+}
+
+// tryCreate 以独占方式创建锁文件并写入持有者信息
+func tryCreate(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	
+	hostname, _ := os.Hostname()
+	_, err = fmt.Fprintf(f, "%d\n%s\n%d\n", os.Getpid(), hostname, time.Now().Unix())
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+// readHolder 读取锁文件中的持有者信息
+func readHolder(path string) (holder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return holder{}, err
+	}
+	
+	var h holder
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) > 0 {
+		h.pid, _ = strconv.Atoi(strings.TrimSpace(lines[0]))
+	}
+	if len(lines) > 1 {
+		h.hostname = strings.TrimSpace(lines[1])
+	}
+	if len(lines) > 2 {
+		if sec, err := strconv.ParseInt(strings.TrimSpace(lines[2]), 10, 64); err == nil {
+			h.since = time.Unix(sec, 0)
+		}
+	}
+	if h.since.IsZero() {
+		if info, err := os.Stat(path); err == nil {
+			h.since = info.ModTime()
+		}
+	}
+	return h, nil
+}
+
+// stale 判断锁是否失效：本机的持有进程已退出，或其他主机上的持有时间超过 StaleAfter
+func (h holder) stale() bool {
+	expired := !h.since.IsZero() && time.Since(h.since) > StaleAfter
+	
+	// 其他主机上的进程无法检查，只能依赖超时判断
+	if hostname, _ := os.Hostname(); h.hostname != "" && h.hostname != hostname {
+		return expired
+	}
+	
+	// 内容不完整可能是其他进程正在写入，写入中断留下的文件交给超时判断
+	if h.pid <= 0 {
+		return expired
+	}
+	return !processExists(h.pid)
+}
+
+// removeIfUnchanged 删除失效的锁文件，删除前再次确认其内容未被其他进程替换
+func removeIfUnchanged(path string, h holder) {
+	current, err := readHolder(path)
+	if err != nil || current != h {
+		return
+	}
+	os.Remove(path)
+}
\ No newline at end of file
@@ -0,0 +1,120 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAcquireRelease(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "lockfile-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	target := filepath.Join(tempDir, "cache.json")
+	lock, err := Acquire(target, time.Second)
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	if _, err := os.Stat(target + ".lock"); err != nil {
+		t.Errorf("Lock file should exist: %v", err)
+	}
+	
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	if _, err := os.Stat(target + ".lock"); !os.IsNotExist(err) {
+		t.Error("Lock file should be removed after release")
+	}
+	
+	// 释放后可以再次获取
+	lock, err = Acquire(target, time.Second)
+	if err != nil {
+		t.Fatalf("Acquire() after release failed: %v", err)
+	}
+	lock.Release()
+}
+
+func TestAcquireTimeout(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "lockfile-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	target := filepath.Join(tempDir, "cache.json")
+	lock, err := Acquire(target, time.Second)
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	defer lock.Release()
+	
+	_, err = Acquire(target, 100*time.Millisecond)
+	var lockedErr *LockedError
+	if !errors.As(err, &lockedErr) {
+		t.Fatalf("Expected LockedError, got %v", err)
+	}
+	if lockedErr.PID != os.Getpid() {
+		t.Errorf("Expected holder pid %d, got %d", os.Getpid(), lockedErr.PID)
+	}
+	if !strings.Contains(err.Error(), fmt.Sprint(os.Getpid())) {
+		t.Errorf("Error should mention holder pid: %v", err)
+	}
+}
+
+func TestAcquireStaleLock(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "lockfile-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	target := filepath.Join(tempDir, "cache.json")
+	hostname, _ := os.Hostname()
+	
+	// 模拟已退出进程遗留的锁文件
+	content := fmt.Sprintf("%d\n%s\n%d\n", 1<<30, hostname, time.Now().Unix())
+	if err := os.WriteFile(target+".lock", []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write lock file: %v", err)
+	}
+	
+	lock, err := Acquire(target, time.Second)
+	if err != nil {
+		t.Fatalf("Acquire() should reclaim stale lock: %v", err)
+	}
+	lock.Release()
+	
+	// 本机进程存活时，持有时间再长也不回收
+	content = fmt.Sprintf("%d\n%s\n%d\n", os.Getpid(), hostname, time.Now().Add(-2*StaleAfter).Unix())
+	if err := os.WriteFile(target+".lock", []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write lock file: %v", err)
+	}
+	if _, err := Acquire(target, 200*time.Millisecond); err == nil {
+		t.Fatal("Acquire() should not reclaim a lock held by a live local process")
+	}
+	
+	// 其他主机上的锁只按持有时间判断
+	content = fmt.Sprintf("%d\n%s\n%d\n", os.Getpid(), "other-host.invalid", time.Now().Unix())
+	if err := os.WriteFile(target+".lock", []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write lock file: %v", err)
+	}
+	if _, err := Acquire(target, 200*time.Millisecond); err == nil {
+		t.Fatal("Acquire() should not reclaim a recent lock from another host")
+	}
+	
+	content = fmt.Sprintf("%d\n%s\n%d\n", os.Getpid(), "other-host.invalid", time.Now().Add(-2*StaleAfter).Unix())
+	if err := os.WriteFile(target+".lock", []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write lock file: %v", err)
+	}
+	lock, err = Acquire(target, time.Second)
+	if err != nil {
+		t.Fatalf("Acquire() should reclaim expired lock from another host: %v", err)
+	}
+	lock.Release()
+}
\ No newline at end of file
@@ -0,0 +1,25 @@
+//go:build windows
+
+package lockfile
+
+import (
+	"syscall"
+)
+
+// processExists 检查进程是否存在
+func processExists(pid int) bool {
+	const processQueryLimitedInformation = 0x1000
+	const stillActive = 259
+	
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(h)
+	
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
\ No newline at end of file
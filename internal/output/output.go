@@ -0,0 +1,152 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Format 表示命令的输出格式
+type Format string
+
+const (
+	Text   Format = "text"   // 供人阅读的文字，默认格式
+	JSON   Format = "json"   // 单个 JSON 文档，列表为数组
+	NDJSON Format = "ndjson" // 每行一个 JSON 对象
+	TSV    Format = "tsv"    // 制表符分隔，第一行为列名
+)
+
+// Formats 所有支持的输出格式
+var Formats = []Format{Text, JSON, NDJSON, TSV}
+
+// Parse 解析输出格式，为空时为 text
+func Parse(s string) (Format, error) {
+	if s == "" {
+		return Text, nil
+	}
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(s) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("不支持的输出格式: %s（可选 text、json、ndjson、tsv）", s)
+}
+
+// Structured 判断是否为机器可读的格式
+func (f Format) Structured() bool {
+	return f != Text && f != ""
+}
+
+// List 输出记录列表，columns 为 tsv 格式的列，对应记录的 JSON 字段名
+func List[T any](w io.Writer, f Format, columns []string, records []T) error {
+	if records == nil {
+		// 没有记录时输出空数组而不是 null
+		records = []T{}
+	}
+	switch f {
+	case JSON:
+		return encode(w, records, true)
+	case NDJSON:
+		for _, record := range records {
+			if err := encode(w, record, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	case TSV:
+		rows := make([]any, len(records))
+		for i, record := range records {
+			rows[i] = record
+		}
+		return writeTSV(w, columns, rows)
+	}
+	return fmt.Errorf("不支持的输出格式: %s", f)
+}
+
+// Object 输出单条记录，json 和 ndjson 为一个对象，tsv 为列名和一行值
+func Object(w io.Writer, f Format, columns []string, record any) error {
+	switch f {
+	case JSON:
+		return encode(w, record, true)
+	case NDJSON:
+		return encode(w, record, false)
+	case TSV:
+		return writeTSV(w, columns, []any{record})
+	}
+	return fmt.Errorf("不支持的输出格式: %s", f)
+}
+
+// encode 输出 JSON，不转义 HTML 字符
+func encode(w io.Writer, v any, indent bool) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if indent {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(v)
+}
+
+// writeTSV 按列输出记录，列的值取自记录的 JSON 字段
+func writeTSV(w io.Writer, columns []string, records []any) error {
+	var b strings.Builder
+	b.WriteString(strings.Join(columns, "\t"))
+	b.WriteByte('\n')
+	for _, record := range records {
+		fields, err := toFields(record)
+		if err != nil {
+			return err
+		}
+		for i, column := range columns {
+			if i > 0 {
+				b.WriteByte('\t')
+			}
+			b.WriteString(tsvValue(fields[column]))
+		}
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// toFields 将记录转换为以 JSON 字段名为键的值
+func toFields(record any) (map[string]any, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("序列化输出失败: %w", err)
+	}
+	var fields map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("序列化输出失败: %w", err)
+	}
+	return fields, nil
+}
+
+// tsvValue 将字段值转换为 tsv 单元格，列表以逗号连接，对象为 JSON，制表符和换行会被转义
+func tsvValue(v any) string {
+	var s string
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		s = v
+	case bool:
+		s = strconv.FormatBool(v)
+	case json.Number:
+		s = v.String()
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = tsvValue(item)
+		}
+		s = strings.Join(items, ",")
+	default:
+		data, _ := json.Marshal(v)
+		s = string(data)
+	}
+	return strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
\ No newline at end of file
@@ -0,0 +1,127 @@
+package output
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+type record struct {
+	Name  string   `json:"name"`
+	Tags  []string `json:"tags"`
+	Count int      `json:"count"`
+	Ok    bool     `json:"ok"`
+}
+
+func testRecords() []record {
+	return []record{
+		{Name: "api", Tags: []string{"a", "b"}, Count: 2, Ok: true},
+		{Name: "we\tb\nx", Count: 0},
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Format
+		err      bool
+	}{
+		{"", Text, false},
+		{"text", Text, false},
+		{"JSON", JSON, false},
+		{"ndjson", NDJSON, false},
+		{"tsv", TSV, false},
+		{"xml", "", true},
+	}
+	
+	for _, tt := range tests {
+		f, err := Parse(tt.input)
+		if (err != nil) != tt.err {
+			t.Errorf("Parse(%q) error = %v, expected error %v", tt.input, err, tt.err)
+		}
+		if f != tt.expected {
+			t.Errorf("Parse(%q) = %q, expected %q", tt.input, f, tt.expected)
+		}
+	}
+	
+	if Text.Structured() || !JSON.Structured() || !TSV.Structured() {
+		t.Error("Expected only non-text formats to be structured")
+	}
+}
+
+func TestList(t *testing.T) {
+	columns := []string{"name", "tags", "count", "ok", "missing"}
+	tests := []struct {
+		format   Format
+		records  []record
+		expected string
+	}{
+		{JSON, nil, "[]\n"},
+		{NDJSON, nil, ""},
+		{TSV, nil, "name\ttags\tcount\tok\tmissing\n"},
+		{JSON, testRecords()[:1], "[\n  {\n    \"name\": \"api\",\n    \"tags\": [\n      \"a\",\n      \"b\"\n    ],\n    \"count\": 2,\n    \"ok\": true\n  }\n]\n"},
+		{NDJSON, testRecords(), "{\"name\":\"api\",\"tags\":[\"a\",\"b\"],\"count\":2,\"ok\":true}\n{\"name\":\"we\\tb\\nx\",\"tags\":null,\"count\":0,\"ok\":false}\n"},
+		{TSV, testRecords(), "name\ttags\tcount\tok\tmissing\napi\ta,b\t2\ttrue\t\nwe\\tb\\nx\t\t0\tfalse\t\n"},
+	}
+	
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := List(&buf, tt.format, columns, tt.records); err != nil {
+			t.Fatalf("List(%s) failed: %v", tt.format, err)
+		}
+		if buf.String() != tt.expected {
+			t.Errorf("List(%s) = %q, expected %q", tt.format, buf.String(), tt.expected)
+		}
+	}
+	
+	if err := List(&bytes.Buffer{}, Text, columns, testRecords()); err == nil {
+		t.Error("Expected error for text format")
+	}
+}
+
+func TestObject(t *testing.T) {
+	value := struct {
+		Error string `json:"error"`
+		Items []any  `json:"items"`
+	}{Error: "a & b", Items: []any{map[string]int{"x": 1}}}
+	
+	var buf bytes.Buffer
+	if err := Object(&buf, NDJSON, nil, value); err != nil {
+		t.Fatal(err)
+	}
+	// 不转义 HTML 字符
+	if buf.String() != "{\"error\":\"a & b\",\"items\":[{\"x\":1}]}\n" {
+		t.Errorf("Unexpected ndjson: %q", buf.String())
+	}
+	
+	buf.Reset()
+	if err := Object(&buf, TSV, []string{"error", "items"}, value); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "error\titems\na & b\t{\"x\":1}\n" {
+		t.Errorf("Unexpected tsv: %q", buf.String())
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var buf bytes.Buffer
+	var mu sync.Mutex
+	w := NewPrefixWriter(&buf, "[api] ", &mu)
+	
+	w.Write([]byte("first\nsec"))
+	if got := buf.String(); got != "[api] first\n" {
+		t.Errorf("Expected only complete lines, got %q", got)
+	}
+	
+	w.Write([]byte("ond\n\nthird"))
+	w.Flush()
+	expected := "[api] first\n[api] second\n[api] \n[api] third\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	
+	w.Flush()
+	if got := buf.String(); got != expected {
+		t.Errorf("Flush() without pending content should not write, got %q", got)
+	}
+}
\ No newline at end of file
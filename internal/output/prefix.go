@@ -0,0 +1,60 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// PrefixWriter 在每行输出前加上前缀；多个 PrefixWriter 共用同一个锁时按整行写入，并发输出不会在行中交错
+type PrefixWriter struct {
+	w      io.Writer
+	prefix string
+	mu     *sync.Mutex
+	buf    []byte // 尚未遇到换行的内容
+}
+
+// NewPrefixWriter 创建 PrefixWriter，mu 为写入 w 时使用的锁
+func NewPrefixWriter(w io.Writer, prefix string, mu *sync.Mutex) *PrefixWriter {
+	return &PrefixWriter{w: w, prefix: prefix, mu: mu}
+}
+
+// Write 缓存不完整的行，每凑齐一行就加上前缀写出
+func (p *PrefixWriter) Write(data []byte) (int, error) {
+	p.buf = append(p.buf, data...)
+	end := bytes.LastIndexByte(p.buf, '\n')
+	if end < 0 {
+		return len(data), nil
+	}
+	
+	var out bytes.Buffer
+	for _, line := range bytes.SplitAfter(p.buf[:end+1], []byte("\n")) {
+		if len(line) > 0 {
+			out.WriteString(p.prefix)
+			out.Write(line)
+		}
+	}
+	p.buf = append(p.buf[:0], p.buf[end+1:]...)
+	
+	if err := p.write(out.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// Flush 写出最后一段没有换行结尾的内容，并补上换行
+func (p *PrefixWriter) Flush() error {
+	if len(p.buf) == 0 {
+		return nil
+	}
+	line := append([]byte(p.prefix), p.buf...)
+	p.buf = nil
+	return p.write(append(line, '\n'))
+}
+
+func (p *PrefixWriter) write(data []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := p.w.Write(data)
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,228 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/git"
+	"github.com/atian25/projj-go/internal/termutil"
+)
+
+// Template 表示按行输出仓库的模板
+type Template struct {
+	tmpl *template.Template
+}
+
+// TemplateRepo 表示模板中的一个仓库，git 状态只在模板用到时才读取
+type TemplateRepo struct {
+	cache.Repository
+	Owner string // 仓库所有者，无法解析 URL 时为路径的上一级目录名
+	
+	status    *git.Status
+	statusErr error
+}
+
+// NewTemplateRepo 创建模板中的仓库
+func NewTemplateRepo(repo cache.Repository) *TemplateRepo {
+	owner := filepath.Base(filepath.Dir(repo.Path))
+	if info, err := git.ParseURL(repo.URL, nil); err == nil {
+		owner = info.Owner
+	}
+	return &TemplateRepo{Repository: repo, Owner: owner}
+}
+
+// WithStatus 使用已读取的 git 状态，避免模板中重复执行 git
+func (r *TemplateRepo) WithStatus(status *git.Status, err error) *TemplateRepo {
+	r.status, r.statusErr = status, err
+	if r.status == nil {
+		r.status = &git.Status{}
+	}
+	return r
+}
+
+// gitStatus 读取并缓存仓库的 git 状态，失败时为空状态
+func (r *TemplateRepo) gitStatus() *git.Status {
+	if r.status == nil {
+		r.WithStatus(git.Inspect(r.Path))
+	}
+	return r.status
+}
+
+// Branch 返回当前分支，分离 HEAD 时为括号中的短提交号
+func (r *TemplateRepo) Branch() string { return r.gitStatus().Branch }
+
+// Dirty 判断工作区是否有未提交的修改，包括未跟踪的文件
+func (r *TemplateRepo) Dirty() bool { return r.gitStatus().Dirty() }
+
+// Changed 返回已跟踪文件中有修改的数量
+func (r *TemplateRepo) Changed() int { return r.gitStatus().Changed }
+
+// Untracked 返回未跟踪的文件数量
+func (r *TemplateRepo) Untracked() int { return r.gitStatus().Untracked }
+
+// Upstream 返回上游分支，未设置时为空
+func (r *TemplateRepo) Upstream() string { return r.gitStatus().Upstream }
+
+// Ahead 返回领先上游的提交数
+func (r *TemplateRepo) Ahead() int { return r.gitStatus().Ahead }
+
+// Behind 返回落后上游的提交数
+func (r *TemplateRepo) Behind() int { return r.gitStatus().Behind }
+
+// Stashes 返回 stash 的数量
+func (r *TemplateRepo) Stashes() int { return r.gitStatus().Stashes }
+
+// Detached 判断是否处于分离 HEAD 状态
+func (r *TemplateRepo) Detached() bool { return r.gitStatus().Detached }
+
+// StatusError 返回读取 git 状态的错误信息，没有错误时为空
+func (r *TemplateRepo) StatusError() string {
+	r.gitStatus()
+	if r.statusErr != nil {
+		return r.statusErr.Error()
+	}
+	return ""
+}
+
+// ParseTemplate 解析模板，base 为 rel 函数使用的基础目录；文字中的 \t 和 \n 会转换为制表符和换行
+func ParseTemplate(text, base string) (*Template, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs(base)).Parse(unescape(text))
+	if err != nil {
+		return nil, fmt.Errorf("解析模板失败: %w", err)
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Execute 按模板输出一个仓库，结果不以换行结尾时补上换行
+func (t *Template) Execute(w io.Writer, repo *TemplateRepo) error {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, repo); err != nil {
+		return fmt.Errorf("执行模板失败: %w", err)
+	}
+	line := b.String()
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+	_, err := io.WriteString(w, line)
+	return err
+}
+
+// unescape 转换模板文字中的转义字符，便于在 shell 中用单引号传入；{{ }} 中的内容保持不变
+func unescape(text string) string {
+	replacer := strings.NewReplacer(`\\`, `\`, `\t`, "\t", `\n`, "\n")
+	var b strings.Builder
+	for {
+		start := strings.Index(text, "{{")
+		if start < 0 {
+			b.WriteString(replacer.Replace(text))
+			return b.String()
+		}
+		b.WriteString(replacer.Replace(text[:start]))
+		text = text[start:]
+		end := strings.Index(text, "}}")
+		if end < 0 {
+			// 未闭合的动作留给模板解析时报错
+			b.WriteString(text)
+			return b.String()
+		}
+		b.WriteString(text[:end+2])
+		text = text[end+2:]
+	}
+}
+
+// templateFuncs 返回模板中可用的函数
+func templateFuncs(base string) template.FuncMap {
+	return template.FuncMap{
+		// rel 返回相对于基础目录的路径，不在基础目录下时原样返回
+		"rel": func(path string) string { return RelPath(base, path) },
+		// home 将用户主目录替换为 ~
+		"home": func(path string) string {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return path
+			}
+			return tildePath(path, home)
+		},
+		// date 按 Go 的时间格式输出，零值为空
+		"date": func(layout string, t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.Local().Format(layout)
+		},
+		"ago":   ago,
+		"pad":   func(width int, v any) string { return pad(fmt.Sprint(v), width, false) },
+		"padl":  func(width int, v any) string { return pad(fmt.Sprint(v), width, true) },
+		"trunc": truncate,
+		"join":  func(sep string, items []string) string { return strings.Join(items, sep) },
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}
+}
+
+// RelPath 返回 path 相对于 base 的路径，以 / 分隔；不在 base 下时原样返回
+func RelPath(base, path string) string {
+	rel, err := filepath.Rel(base, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// tildePath 将路径中的主目录前缀替换为 ~，只替换完整的目录，如 /home/user 不会替换 /home/username
+func tildePath(path, home string) string {
+	home = strings.TrimSuffix(home, string(filepath.Separator))
+	if home == "" {
+		return path
+	}
+	if path == home || strings.HasPrefix(path, home+string(filepath.Separator)) {
+		return "~" + strings.TrimPrefix(path, home)
+	}
+	return path
+}
+
+// ago 返回距今的时间，如 "3 天前"，零值为空
+func ago(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "刚刚"
+	case d < time.Hour:
+		return fmt.Sprintf("%d 分钟前", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%d 小时前", int(d/time.Hour))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%d 天前", int(d/(24*time.Hour)))
+	default:
+		return t.Local().Format("2006-01-02")
+	}
+}
+
+// pad 用空格补齐到指定的显示宽度，中文字符占两列；left 为 true 时右对齐
+func pad(s string, width int, left bool) string {
+	n := width - termutil.Width(s)
+	if n <= 0 {
+		return s
+	}
+	if left {
+		return strings.Repeat(" ", n) + s
+	}
+	return s + strings.Repeat(" ", n)
+}
+
+// truncate 截断超过显示宽度的内容，末尾以 … 表示
+func truncate(width int, s string) string {
+	if termutil.Width(s) <= width {
+		return s
+	}
+	return termutil.Truncate(s, width)
+}
\ No newline at end of file
@@ -0,0 +1,135 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/git"
+)
+
+func TestTemplate(t *testing.T) {
+	repo := cache.Repository{
+		Name:     "api",
+		URL:      "git@github.com:team/api.git",
+		Path:     "/projj/github.com/team/api",
+		Platform: "github.com",
+		Tags:     []string{"infra", "payments"},
+		AddedAt:  time.Date(2024, 5, 1, 8, 30, 0, 0, time.Local),
+	}
+	status := &git.Status{Branch: "main", Changed: 2, Ahead: 1}
+	
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"fields", `{{.Platform}}/{{.Owner}}/{{.Name}}\t{{.Path}}`, "github.com/team/api\t/projj/github.com/team/api\n"},
+		{"escape in actions kept", `{{join "\t" .Tags}}\n`, "infra\tpayments\n"},
+		{"escaped backslash", `a\\tb`, "a\\tb\n"},
+		{"rel", `{{rel .Path}}`, "github.com/team/api\n"},
+		{"date", `{{date "2006-01-02" .AddedAt}}|{{date "2006" .LastVisitedAt}}`, "2024-05-01|\n"},
+		{"pad", `{{pad 6 .Name}}|{{padl 6 .Name}}|{{pad 6 "中文"}}|`, "api   |   api|中文  |\n"},
+		{"pipeline", `{{.Name | upper | pad 5}}|`, "API  |\n"},
+		{"trunc", `{{trunc 5 .URL}}|{{trunc 5 .Name}}`, "git@…|api\n"},
+		{"git status", `{{.Branch}}{{if .Dirty}} *{{end}} +{{.Ahead}} -{{.Behind}}`, "main * +1 -0\n"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tt.text, "/projj")
+			if err != nil {
+				t.Fatalf("ParseTemplate failed: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, NewTemplateRepo(repo).WithStatus(status, nil)); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, buf.String())
+			}
+		})
+	}
+	
+	if _, err := ParseTemplate("{{.Name", "/projj"); err == nil {
+		t.Error("Expected parse error")
+	}
+	tmpl, _ := ParseTemplate("{{.Missing}}", "/projj")
+	if err := tmpl.Execute(&bytes.Buffer{}, NewTemplateRepo(repo).WithStatus(status, nil)); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+}
+
+func TestTemplateReadsGitStatus(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	
+	dir, err := os.MkdirTemp("", "projj-template-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	
+	if err := exec.Command("git", "init", "-q", "-b", "trunk", dir).Run(); err != nil {
+		t.Fatalf("git init failed: %v", err)
+	}
+	os.WriteFile(filepath.Join(dir, "file.txt"), []byte("x"), 0644)
+	
+	tmpl, err := ParseTemplate(`{{.Branch}} {{.Dirty}} {{.Untracked}} {{.StatusError}}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, NewTemplateRepo(cache.Repository{Path: dir})); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "trunk true 1" {
+		t.Errorf("Expected live git status, got %q", buf.String())
+	}
+}
+
+func TestRelPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/projj/github.com/team/api", "github.com/team/api"},
+		{"/projj", "."},
+		{"/projj/..foo/api", "..foo/api"},
+		{"/other/api", "/other/api"},
+		{"/projj-old/api", "/projj-old/api"},
+	}
+	
+	for _, tt := range tests {
+		if got := RelPath("/projj", tt.path); got != tt.expected {
+			t.Errorf("RelPath(%q) = %q, expected %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestTildePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/home/user", "~"},
+		{"/home/user/projj/api", "~/projj/api"},
+		{"/home/username/api", "/home/username/api"},
+		{"/opt/api", "/opt/api"},
+	}
+	
+	for _, tt := range tests {
+		if got := tildePath(tt.path, "/home/user"); got != tt.expected {
+			t.Errorf("tildePath(%q) = %q, expected %q", tt.path, got, tt.expected)
+		}
+	}
+	if got := tildePath("/home/user/api", "/home/user/"); got != "~/api" {
+		t.Errorf("Expected trailing separator in home ignored, got %q", got)
+	}
+}
\ No newline at end of file
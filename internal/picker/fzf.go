@@ -0,0 +1,60 @@
+package picker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// FZFAvailable 判断 PATH 中是否有 fzf
+func FZFAvailable() bool {
+	_, err := exec.LookPath("fzf")
+	return err == nil
+}
+
+// PickFZF 通过外部的 fzf 选择，返回选中项的下标；preview 为 fzf 的预览命令，其中的 {key} 会替换为候选项的 Key
+func PickFZF(items []Item, opts Options, preview string) (int, error) {
+	path, err := exec.LookPath("fzf")
+	if err != nil {
+		return -1, fmt.Errorf("未找到 fzf，请先安装或去掉 --fzf 使用内置选择器")
+	}
+	
+	// 每行为 下标\tLabel\tKey，只显示 Label
+	var input bytes.Buffer
+	for i, item := range items {
+		fmt.Fprintf(&input, "%d\t%s\t%s\n", i, item.Label, item.Key)
+	}
+	
+	args := []string{"--delimiter", "\t", "--with-nth", "2", "--no-multi", "--select-1", "--prompt", opts.Prompt}
+	if opts.Query != "" {
+		args = append(args, "--query", opts.Query)
+	}
+	if preview != "" {
+		args = append(args, "--preview", strings.ReplaceAll(preview, "{key}", "{3}"))
+	}
+	
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = &input
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		// 1 表示没有匹配项，130 表示用户取消
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && (exitErr.ExitCode() == 1 || exitErr.ExitCode() == 130) {
+			return -1, ErrCancelled
+		}
+		return -1, fmt.Errorf("执行 fzf 失败: %w", err)
+	}
+	
+	line := strings.TrimRight(string(output), "\r\n")
+	field, _, _ := strings.Cut(line, "\t")
+	index, err := strconv.Atoi(field)
+	if err != nil || index < 0 || index >= len(items) {
+		return -1, fmt.Errorf("无法解析 fzf 的输出: %s", line)
+	}
+	return index, nil
+}
\ No newline at end of file
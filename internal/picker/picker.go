@@ -0,0 +1,257 @@
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/atian25/projj-go/internal/termutil"
+	"golang.org/x/term"
+)
+
+// ErrCancelled 表示用户取消了选择
+var ErrCancelled = errors.New("已取消选择")
+
+// Item 表示一个候选项
+type Item struct {
+	Label string // 列表中显示的文字
+	Key   string // 标识候选项的值，fzf 模式下供预览命令使用
+}
+
+// Options 表示选择器的选项
+type Options struct {
+	Prompt  string                   // 输入行前的提示
+	Query   string                   // 初始的过滤词
+	Height  int                      // 最多显示的候选项数量，为 0 时为 10
+	Filter  func(query string) []int // 返回匹配过滤词的候选项下标，按显示顺序排列；为空时按 Label 包含过滤
+	Preview func(index int) []string // 返回当前候选项的预览内容，只对选中项调用
+}
+
+// Interactive 判断标准输入和标准错误是否都连接到终端，只有此时才能使用内置选择器；
+// 界面绘制在标准错误上，标准输出被 $(projj find ...) 等捕获时仍可选择
+func Interactive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// Pick 在终端中显示选择器，返回选中项的下标；界面绘制在标准错误上，不影响标准输出
+func Pick(items []Item, opts Options) (int, error) {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return -1, fmt.Errorf("切换终端模式失败: %w", err)
+	}
+	defer term.Restore(fd, state)
+	
+	width := 80
+	if w, _, err := term.GetSize(int(os.Stderr.Fd())); err == nil && w > 0 {
+		width = w
+	}
+	return run(os.Stdin, os.Stderr, width, items, opts)
+}
+
+// picker 表示选择器的状态
+type picker struct {
+	items    []Item
+	opts     Options
+	width    int
+	query    []rune
+	matches  []int
+	cursor   int
+	offset   int
+	lines    int // 上次绘制的行数
+	previews map[int][]string
+}
+
+// run 读取按键并重绘界面，直到选中或取消
+func run(in io.Reader, out io.Writer, width int, items []Item, opts Options) (int, error) {
+	if opts.Height <= 0 {
+		opts.Height = 10
+	}
+	p := &picker{
+		items:    items,
+		opts:     opts,
+		width:    width,
+		query:    []rune(opts.Query),
+		previews: make(map[int][]string),
+	}
+	p.filter()
+	
+	// 隐藏光标，结束时清除界面并恢复光标
+	fmt.Fprint(out, "\x1b[?25l")
+	defer func() {
+		p.clear(out)
+		fmt.Fprint(out, "\x1b[?25h")
+	}()
+	
+	buf := make([]byte, 64)
+	for {
+		p.render(out)
+		n, err := in.Read(buf)
+		if n == 0 && err != nil {
+			return -1, ErrCancelled
+		}
+		
+		done, err := p.handle(buf[:n])
+		if err != nil {
+			return -1, err
+		}
+		if done {
+			return p.selected(), nil
+		}
+	}
+}
+
+// handle 处理一次读取到的输入，返回是否结束选择
+func (p *picker) handle(input []byte) (bool, error) {
+	// 单独的 ESC 表示取消，方向键以 ESC [ 开头
+	switch string(input) {
+	case "\x1b", "\x03", "\x07":
+		return true, ErrCancelled
+	case "\x1b[A", "\x1bOA", "\x10", "\x0b":
+		p.move(-1)
+		return false, nil
+	case "\x1b[B", "\x1bOB", "\x0e", "\x0a":
+		p.move(1)
+		return false, nil
+	case "\x1b[5~":
+		p.move(-p.opts.Height)
+		return false, nil
+	case "\x1b[6~":
+		p.move(p.opts.Height)
+		return false, nil
+	case "\r":
+		if len(p.matches) == 0 {
+			return false, nil
+		}
+		return true, nil
+	}
+	if len(input) > 1 && input[0] == '\x1b' {
+		return false, nil
+	}
+	
+	changed := false
+	for len(input) > 0 {
+		r, size := utf8.DecodeRune(input)
+		input = input[size:]
+		switch {
+		case r == '\x7f' || r == '\b':
+			if len(p.query) > 0 {
+				p.query = p.query[:len(p.query)-1]
+				changed = true
+			}
+		case r == '\x15':
+			p.query = p.query[:0]
+			changed = true
+		case r == '\r':
+			// 粘贴的内容可能以回车结尾，先按已输入的内容过滤
+			if changed {
+				p.filter()
+				changed = false
+			}
+			if len(p.matches) > 0 {
+				return true, nil
+			}
+		case unicode.IsPrint(r):
+			p.query = append(p.query, r)
+			changed = true
+		}
+	}
+	if changed {
+		p.filter()
+	}
+	return false, nil
+}
+
+// filter 按当前过滤词重新计算候选项
+func (p *picker) filter() {
+	query := strings.TrimSpace(string(p.query))
+	if p.opts.Filter != nil {
+		p.matches = p.opts.Filter(query)
+	} else {
+		p.matches = p.matches[:0]
+		lower := strings.ToLower(query)
+		for i, item := range p.items {
+			if strings.Contains(strings.ToLower(item.Label), lower) {
+				p.matches = append(p.matches, i)
+			}
+		}
+	}
+	p.cursor, p.offset = 0, 0
+}
+
+// move 移动选中项，到达两端时停止
+func (p *picker) move(delta int) {
+	p.cursor += delta
+	if p.cursor >= len(p.matches) {
+		p.cursor = len(p.matches) - 1
+	}
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+p.opts.Height {
+		p.offset = p.cursor - p.opts.Height + 1
+	}
+}
+
+// selected 返回选中项在 items 中的下标
+func (p *picker) selected() int {
+	if p.cursor < len(p.matches) {
+		return p.matches[p.cursor]
+	}
+	return -1
+}
+
+// render 清除上次的内容后重新绘制输入行、候选列表和预览
+func (p *picker) render(out io.Writer) {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("%s%s", p.opts.Prompt, string(p.query)))
+	
+	end := p.offset + p.opts.Height
+	if end > len(p.matches) {
+		end = len(p.matches)
+	}
+	for i := p.offset; i < end; i++ {
+		label := p.truncate("  " + p.items[p.matches[i]].Label)
+		if i == p.cursor {
+			label = "\x1b[7m" + p.truncate("> "+p.items[p.matches[i]].Label) + "\x1b[0m"
+		}
+		lines = append(lines, label)
+	}
+	lines = append(lines, fmt.Sprintf("\x1b[2m  %d/%d\x1b[0m", len(p.matches), len(p.items)))
+	
+	if index := p.selected(); index >= 0 && p.opts.Preview != nil {
+		preview, ok := p.previews[index]
+		if !ok {
+			preview = p.opts.Preview(index)
+			p.previews[index] = preview
+		}
+		for _, line := range preview {
+			lines = append(lines, "\x1b[2m"+p.truncate("  "+line)+"\x1b[0m")
+		}
+	}
+	
+	p.clear(out)
+	fmt.Fprint(out, strings.Join(lines, "\r\n"))
+	p.lines = len(lines)
+}
+
+// clear 将光标移回第一行并清除之后的内容
+func (p *picker) clear(out io.Writer) {
+	if p.lines > 1 {
+		fmt.Fprintf(out, "\x1b[%dA", p.lines-1)
+	}
+	fmt.Fprint(out, "\r\x1b[J")
+	p.lines = 0
+}
+
+// truncate 截断超出终端宽度的内容
+func (p *picker) truncate(s string) string {
+	return termutil.Truncate(s, p.width)
+}
\ No newline at end of file
@@ -0,0 +1,124 @@
+package picker
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// keys 每次 Read 返回一个按键，模拟终端的输入
+type keys struct {
+	chunks []string
+}
+
+func (k *keys) Read(p []byte) (int, error) {
+	if len(k.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, k.chunks[0])
+	k.chunks = k.chunks[1:]
+	return n, nil
+}
+
+func testItems() []Item {
+	return []Item{
+		{Label: "api (/projj/team/api)", Key: "/projj/team/api"},
+		{Label: "api-gateway (/projj/team/api-gateway)", Key: "/projj/team/api-gateway"},
+		{Label: "web (/projj/team/web)", Key: "/projj/team/web"},
+	}
+}
+
+func TestRunSelect(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected int
+		err      error
+	}{
+		{"enter", []string{"\r"}, 0, nil},
+		{"down", []string{"\x1b[B", "\r"}, 1, nil},
+		{"down past end", []string{"\x1b[B", "\x1b[B", "\x1b[B", "\x1b[B", "\r"}, 2, nil},
+		{"up at top", []string{"\x1b[A", "\r"}, 0, nil},
+		{"ctrl-n ctrl-p", []string{"\x0e", "\x0e", "\x10", "\r"}, 1, nil},
+		{"filter", []string{"w", "e", "\r"}, 2, nil},
+		{"pasted filter", []string{"gate\r"}, 1, nil},
+		{"backspace", []string{"w", "e", "x", "\x7f", "\r"}, 2, nil},
+		{"clear", []string{"web", "\x15", "\r"}, 0, nil},
+		{"no match ignores enter", []string{"zzz", "\r", "\x15", "\r"}, 0, nil},
+		{"escape", []string{"\x1b"}, -1, ErrCancelled},
+		{"ctrl-c", []string{"\x03"}, -1, ErrCancelled},
+		{"eof", nil, -1, ErrCancelled},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			index, err := run(&keys{chunks: tt.input}, &out, 80, testItems(), Options{})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Expected error %v, got %v", tt.err, err)
+			}
+			if index != tt.expected {
+				t.Errorf("Expected index %d, got %d", tt.expected, index)
+			}
+		})
+	}
+}
+
+func TestRunFilterAndPreview(t *testing.T) {
+	var previewed []int
+	opts := Options{
+		Prompt: "> ",
+		Height: 1,
+		// 按 Label 长度倒序，验证使用自定义的过滤结果
+		Filter: func(query string) []int {
+			if query == "" {
+				return []int{1, 0, 2}
+			}
+			return []int{2}
+		},
+		Preview: func(index int) []string {
+			previewed = append(previewed, index)
+			return []string{"preview-" + testItems()[index].Key}
+		},
+	}
+	
+	var out bytes.Buffer
+	index, err := run(&keys{chunks: []string{"\x1b[B", "\x1b[A", "\r"}}, &out, 80, testItems(), opts)
+	if err != nil || index != 1 {
+		t.Fatalf("Expected index 1, got %d (%v)", index, err)
+	}
+	
+	// 每个选中项只取一次预览
+	if len(previewed) != 2 || previewed[0] != 1 || previewed[1] != 0 {
+		t.Errorf("Expected previews for 1 and 0 once each, got %v", previewed)
+	}
+	
+	output := out.String()
+	if !strings.Contains(output, "preview-/projj/team/api-gateway") {
+		t.Error("Expected preview to be rendered")
+	}
+	// 高度为 1 时不应同时显示两个候选项
+	if strings.Contains(output, "api (/projj/team/api)\r\n  api-gateway") {
+		t.Error("Expected list height to be limited")
+	}
+	
+	if index, _ := run(&keys{chunks: []string{"x", "\r"}}, &out, 80, testItems(), opts); index != 2 {
+		t.Errorf("Expected filtered index 2, got %d", index)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	p := &picker{width: 10}
+	if got := p.truncate("short"); got != "short" {
+		t.Errorf("Expected short string kept, got %q", got)
+	}
+	if got := p.truncate("a very long label"); got != "a very lo…" {
+		t.Errorf("Unexpected truncation: %q", got)
+	}
+	// 中文字符占两列
+	if got := p.truncate("中文仓库名称很长"); got != "中文仓库…" {
+		t.Errorf("Unexpected wide truncation: %q", got)
+	}
+}
\ No newline at end of file
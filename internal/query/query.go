@@ -0,0 +1,499 @@
+package query
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/git"
+)
+
+// GitState 提供仓库的实时 git 状态，只在查询用到相关字段时调用
+type GitState interface {
+	Dirty(repoPath string) (bool, error)
+}
+
+// liveState 通过 git 命令获取实时状态
+type liveState struct{}
+
+func (liveState) Dirty(repoPath string) (bool, error) {
+	// 目录已不存在的仓库视为没有修改
+	if !git.IsGitRepository(repoPath) {
+		return false, nil
+	}
+	clean, err := git.GetStatus(repoPath)
+	return !clean, err
+}
+
+// Query 表示编译后的查询
+type Query struct {
+	root  node
+	plain bool
+}
+
+// node 表示查询表达式中的一个节点
+type node interface {
+	eval(r *record) (bool, error)
+	usesGit() bool
+}
+
+// record 表示正在求值的仓库，字段按需计算
+type record struct {
+	repo  cache.Repository
+	state GitState
+	info  *git.RepoInfo
+}
+
+// Compile 编译查询：field:value 为字段匹配，普通关键词匹配名称、路径或 URL，- 或 ! 前缀取反，空格为与，OR 或 | 为或，支持括号分组
+func Compile(input string) (*Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	
+	p := &parser{tokens: tokens}
+	if len(tokens) == 0 {
+		return &Query{root: all{}, plain: true}, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(tokens) {
+		return nil, fmt.Errorf("查询语法错误: 多余的 %q", tokens[p.pos].text)
+	}
+	
+	_, plain := root.(word)
+	return &Query{root: root, plain: plain}, nil
+}
+
+// Plain 判断查询是否只是单个普通关键词，此时可以使用排序的模糊匹配
+func (q *Query) Plain() bool {
+	return q.plain
+}
+
+// Keyword 拆出与其他条件同时满足的唯一普通关键词，使其仍可使用排序的模糊匹配，rest 为其余条件，没有时为 nil
+func (q *Query) Keyword() (keyword string, rest *Query, ok bool) {
+	switch root := q.root.(type) {
+	case all:
+		return "", nil, true
+	case word:
+		return string(root), nil, true
+	case and:
+		index := -1
+		for i, n := range root {
+			if _, isWord := n.(word); isWord {
+				if index >= 0 {
+					return "", nil, false
+				}
+				index = i
+			}
+		}
+		if index < 0 {
+			return "", nil, false
+		}
+		others := make(and, 0, len(root)-1)
+		others = append(others, root[:index]...)
+		others = append(others, root[index+1:]...)
+		if len(others) == 1 {
+			return string(root[index].(word)), &Query{root: others[0]}, true
+		}
+		return string(root[index].(word)), &Query{root: others}, true
+	}
+	return "", nil, false
+}
+
+// UsesGit 判断查询是否需要读取仓库的实时 git 状态
+func (q *Query) UsesGit() bool {
+	return q.root.usesGit()
+}
+
+// Match 判断仓库是否满足查询，state 为空时通过 git 命令获取实时状态
+func (q *Query) Match(repo cache.Repository, state GitState) (bool, error) {
+	if state == nil {
+		state = liveState{}
+	}
+	return q.root.eval(&record{repo: repo, state: state})
+}
+
+// Filter 返回满足查询的仓库，保持原顺序
+func (q *Query) Filter(repos []cache.Repository, state GitState) ([]cache.Repository, error) {
+	var results []cache.Repository
+	for _, repo := range repos {
+		ok, err := q.Match(repo, state)
+		if err != nil {
+			return nil, fmt.Errorf("检查仓库 %s 失败: %w", repo.Path, err)
+		}
+		if ok {
+			results = append(results, repo)
+		}
+	}
+	return results, nil
+}
+
+// token 表示词法单元
+type token struct {
+	text   string
+	quoted bool
+}
+
+// tokenize 按空白和括号切分查询，支持双引号包裹含空格的值
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	var current strings.Builder
+	inQuote, quoted := false, false
+	
+	flush := func() {
+		if current.Len() > 0 || quoted {
+			tokens = append(tokens, token{text: current.String(), quoted: quoted})
+		}
+		current.Reset()
+		quoted = false
+	}
+	
+	for _, r := range input {
+		switch {
+		case r == '"':
+			// 整个词被引号包裹时按普通关键词处理，field:"a b" 仍是字段条件
+			if !inQuote && current.Len() == 0 {
+				quoted = true
+			}
+			inQuote = !inQuote
+		case inQuote:
+			current.WriteRune(r)
+		case unicode.IsSpace(r):
+			flush()
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, token{text: string(r)})
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("查询语法错误: 引号未闭合")
+	}
+	flush()
+	return tokens, nil
+}
+
+// parser 递归下降解析器
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// isOr 判断是否为或运算符
+func isOr(t token) bool {
+	return !t.quoted && (t.text == "|" || t.text == "OR")
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || !isOr(t) {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = or{left, right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	var terms []node
+	for {
+		t, ok := p.peek()
+		if !ok || isOr(t) || (!t.quoted && t.text == ")") {
+			break
+		}
+		if !t.quoted && t.text == "AND" {
+			p.pos++
+			continue
+		}
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	
+	switch len(terms) {
+	case 0:
+		return nil, fmt.Errorf("查询语法错误: 缺少查询条件")
+	case 1:
+		return terms[0], nil
+	}
+	
+	// 短路求值时先检查不需要 git 的条件，减少 git 调用
+	sort.SliceStable(terms, func(i, j int) bool {
+		return !terms[i].usesGit() && terms[j].usesGit()
+	})
+	return and(terms), nil
+}
+
+func (p *parser) parseTerm() (node, error) {
+	t, ok := p.peek()
+	if !ok || (!t.quoted && (t.text == ")" || isOr(t))) {
+		return nil, fmt.Errorf("查询语法错误: 缺少查询条件")
+	}
+	p.pos++
+	
+	if !t.quoted {
+		switch {
+		case t.text == "(":
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if closing, ok := p.peek(); !ok || closing.quoted || closing.text != ")" {
+				return nil, fmt.Errorf("查询语法错误: 括号未闭合")
+			}
+			p.pos++
+			return inner, nil
+		case t.text == "NOT":
+			inner, err := p.parseTerm()
+			if err != nil {
+				return nil, err
+			}
+			return not{inner}, nil
+		case len(t.text) > 1 && (t.text[0] == '-' || t.text[0] == '!'):
+			// 将去掉前缀的部分作为新的词法单元重新解析
+			p.pos--
+			p.tokens[p.pos] = token{text: t.text[1:]}
+			inner, err := p.parseTerm()
+			if err != nil {
+				return nil, err
+			}
+			return not{inner}, nil
+		}
+	}
+	
+	if !t.quoted {
+		// 带协议的 URL 作为普通关键词
+		if name, value, ok := strings.Cut(t.text, ":"); ok && name != "" && !strings.HasPrefix(value, "//") {
+			if f, known := fields[strings.ToLower(name)]; known {
+				return f.compile(strings.ToLower(name), value)
+			}
+			// 形如 git@host:owner/repo 的地址也作为普通关键词
+			if isIdent(name) {
+				return nil, fmt.Errorf("未知的查询字段: %s", name)
+			}
+		}
+	}
+	return word(strings.ToLower(t.text)), nil
+}
+
+// isIdent 判断是否像字段名，只包含字母
+func isIdent(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// field 表示可查询的字段
+type field struct {
+	compile func(name, value string) (node, error)
+}
+
+// fields 支持的查询字段
+var fields = map[string]field{
+	"name":  {stringField(false, func(r *record) string { return r.repo.Name })},
+	"owner": {stringField(false, func(r *record) string { return r.owner() })},
+	"host":  {stringField(false, func(r *record) string { return r.host() })},
+	"path":  {stringField(true, func(r *record) string { return r.repo.Path })},
+	"url":   {stringField(true, func(r *record) string { return r.repo.URL })},
+	"desc":  {stringField(true, func(r *record) string { return r.repo.Description })},
+	"tag":   {listField(func(r *record) []string { return r.repo.Tags })},
+	"dirty": {boolField(true, func(r *record) (bool, error) { return r.state.Dirty(r.repo.Path) })},
+}
+
+// stringField 返回字符串字段的编译函数，contains 为 true 时普通值按包含匹配
+func stringField(contains bool, get func(r *record) string) func(name, value string) (node, error) {
+	return func(name, value string) (node, error) {
+		m, err := compileMatcher(value, contains)
+		if err != nil {
+			return nil, fmt.Errorf("字段 %s 的值无效: %w", name, err)
+		}
+		return fieldNode{match: func(r *record) (bool, error) { return m(get(r)), nil }}, nil
+	}
+}
+
+// listField 返回多值字段的编译函数，任一值完全匹配即满足
+func listField(get func(r *record) []string) func(name, value string) (node, error) {
+	return func(name, value string) (node, error) {
+		m, err := compileMatcher(value, false)
+		if err != nil {
+			return nil, fmt.Errorf("字段 %s 的值无效: %w", name, err)
+		}
+		return fieldNode{match: func(r *record) (bool, error) {
+			for _, v := range get(r) {
+				if m(v) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}}, nil
+	}
+}
+
+// boolField 返回布尔字段的编译函数，git 为 true 表示需要读取实时 git 状态
+func boolField(git bool, get func(r *record) (bool, error)) func(name, value string) (node, error) {
+	return func(name, value string) (node, error) {
+		expected, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("字段 %s 的值必须是 true 或 false: %s", name, value)
+		}
+		return fieldNode{git: git, match: func(r *record) (bool, error) {
+			actual, err := get(r)
+			return actual == expected, err
+		}}, nil
+	}
+}
+
+// compileMatcher 编译字段值：~ 开头为正则，含通配符为 glob，否则为完全匹配或包含，均不区分大小写
+func compileMatcher(value string, contains bool) (func(string) bool, error) {
+	switch {
+	case strings.HasPrefix(value, "~"):
+		re, err := regexp.Compile("(?i)" + value[1:])
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	case strings.ContainsAny(value, "*?["):
+		pattern := strings.ToLower(value)
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, err
+		}
+		return func(s string) bool {
+			matched, _ := path.Match(pattern, strings.ToLower(filepath.ToSlash(s)))
+			return matched
+		}, nil
+	case contains:
+		value = strings.ToLower(value)
+		return func(s string) bool { return strings.Contains(strings.ToLower(s), value) }, nil
+	default:
+		return func(s string) bool { return strings.EqualFold(s, value) }, nil
+	}
+}
+
+// owner 返回仓库的所有者，无法解析 URL 时取路径的上一级目录名
+func (r *record) owner() string {
+	if info := r.repoInfo(); info != nil {
+		return info.Owner
+	}
+	return filepath.Base(filepath.Dir(r.repo.Path))
+}
+
+// host 返回仓库所在的主机名
+func (r *record) host() string {
+	if info := r.repoInfo(); info != nil {
+		return info.Platform
+	}
+	return r.repo.Platform
+}
+
+// repoInfo 解析仓库 URL，失败时返回 nil
+func (r *record) repoInfo() *git.RepoInfo {
+	if r.info == nil && r.repo.URL != "" {
+		if info, err := git.ParseURL(r.repo.URL, nil); err == nil {
+			r.info = info
+		}
+	}
+	return r.info
+}
+
+// all 匹配所有仓库
+type all struct{}
+
+func (all) eval(*record) (bool, error) { return true, nil }
+func (all) usesGit() bool              { return false }
+
+// word 名称、路径或 URL 包含该词
+type word string
+
+func (w word) eval(r *record) (bool, error) {
+	s := string(w)
+	return strings.Contains(strings.ToLower(r.repo.Name), s) ||
+		strings.Contains(strings.ToLower(r.repo.Path), s) ||
+		strings.Contains(strings.ToLower(r.repo.URL), s), nil
+}
+
+func (word) usesGit() bool { return false }
+
+// fieldNode 字段条件
+type fieldNode struct {
+	git   bool
+	match func(r *record) (bool, error)
+}
+
+func (f fieldNode) eval(r *record) (bool, error) { return f.match(r) }
+func (f fieldNode) usesGit() bool                { return f.git }
+
+// not 取反
+type not struct{ inner node }
+
+func (n not) eval(r *record) (bool, error) {
+	ok, err := n.inner.eval(r)
+	return !ok, err
+}
+
+func (n not) usesGit() bool { return n.inner.usesGit() }
+
+// and 所有条件都满足，按顺序短路求值
+type and []node
+
+func (a and) eval(r *record) (bool, error) {
+	for _, n := range a {
+		ok, err := n.eval(r)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (a and) usesGit() bool {
+	for _, n := range a {
+		if n.usesGit() {
+			return true
+		}
+	}
+	return false
+}
+
+// or 任一条件满足
+type or struct{ left, right node }
+
+func (o or) eval(r *record) (bool, error) {
+	ok, err := o.left.eval(r)
+	if err != nil || ok {
+		return ok, err
+	}
+	return o.right.eval(r)
+}
+
+func (o or) usesGit() bool { return o.left.usesGit() || o.right.usesGit() }
\ No newline at end of file
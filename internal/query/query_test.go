@@ -0,0 +1,210 @@
+package query
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/atian25/projj-go/internal/cache"
+)
+
+// fakeState 以固定结果代替实时 git 状态
+type fakeState struct {
+	dirty map[string]bool
+	calls int
+}
+
+func (f *fakeState) Dirty(repoPath string) (bool, error) {
+	f.calls++
+	return f.dirty[repoPath], nil
+}
+
+type errState struct{}
+
+func (errState) Dirty(string) (bool, error) {
+	return false, errors.New("git failed")
+}
+
+func queryRepos() []cache.Repository {
+	return []cache.Repository{
+		{Name: "api-gateway", Path: "/projj/gitlab.com/infra/api-gateway", URL: "git@gitlab.com:infra/api-gateway.git", Platform: "gitlab.com", Tags: []string{"backend", "payments"}, Description: "对外 API 入口"},
+		{Name: "api-docs", Path: "/projj/github.com/infra/api-docs", URL: "https://github.com/infra/api-docs.git", Platform: "github.com"},
+		{Name: "web", Path: "/projj/gitlab.com/infra/web", URL: "git@gitlab.com:infra/web.git", Platform: "gitlab.com", Tags: []string{"frontend"}},
+		{Name: "go", Path: "/projj/github.com/golang/go", URL: "https://github.com/golang/go.git", Platform: "github.com"},
+		{Name: "notes", Path: "/projj/local/notes", Platform: "local"},
+	}
+}
+
+func names(repos []cache.Repository) []string {
+	var result []string
+	for _, repo := range repos {
+		result = append(result, repo.Name)
+	}
+	return result
+}
+
+func TestFilter(t *testing.T) {
+	state := &fakeState{dirty: map[string]bool{
+		"/projj/gitlab.com/infra/api-gateway": true,
+		"/projj/github.com/golang/go":         true,
+	}}
+	
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"", []string{"api-gateway", "api-docs", "web", "go", "notes"}},
+		{"api", []string{"api-gateway", "api-docs"}},
+		{"host:gitlab.com", []string{"api-gateway", "web"}},
+		{"HOST:GitLab.com", []string{"api-gateway", "web"}},
+		{"owner:infra", []string{"api-gateway", "api-docs", "web"}},
+		{"owner:local", []string{"notes"}},
+		{"name:go", []string{"go"}},
+		{"name:~^api-", []string{"api-gateway", "api-docs"}},
+		{"name:api-*", []string{"api-gateway", "api-docs"}},
+		{"path:infra/w", []string{"web"}},
+		{"url:https://", []string{"api-docs", "go"}},
+		{"host:gitlab.com owner:infra name:~^api-", []string{"api-gateway"}},
+		{"host:gitlab.com AND name:web", []string{"web"}},
+		{"-owner:infra", []string{"go", "notes"}},
+		{"!owner:infra", []string{"go", "notes"}},
+		{"NOT owner:infra", []string{"go", "notes"}},
+		{"name:go OR name:web", []string{"web", "go"}},
+		{"name:go | name:web", []string{"web", "go"}},
+		{"owner:infra (name:web OR host:github.com)", []string{"api-docs", "web"}},
+		{"NOT (owner:infra OR name:go)", []string{"notes"}},
+		{"dirty:true", []string{"api-gateway", "go"}},
+		{"dirty:false owner:infra", []string{"api-docs", "web"}},
+		{"git@gitlab.com:infra/web.git", []string{"web"}},
+		{"https://github.com/golang/go.git", []string{"go"}},
+		{`path:"local/notes"`, []string{"notes"}},
+		{"tag:payments", []string{"api-gateway"}},
+		{"tag:*end", []string{"api-gateway", "web"}},
+		{"-tag:backend owner:infra", []string{"api-docs", "web"}},
+		{"desc:api", []string{"api-gateway"}},
+	}
+	
+	for _, tt := range tests {
+		q, err := Compile(tt.query)
+		if err != nil {
+			t.Errorf("Compile(%q) failed: %v", tt.query, err)
+			continue
+		}
+		results, err := q.Filter(queryRepos(), state)
+		if err != nil {
+			t.Errorf("Filter(%q) failed: %v", tt.query, err)
+			continue
+		}
+		got := names(results)
+		if len(got) != len(tt.expected) {
+			t.Errorf("Query %q: expected %v, got %v", tt.query, tt.expected, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("Query %q: expected %v, got %v", tt.query, tt.expected, got)
+				break
+			}
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	invalid := []string{
+		"color:red",
+		"name:~[",
+		"dirty:maybe",
+		"(name:go",
+		"name:go)",
+		"name:go OR",
+		"NOT",
+		"()",
+	}
+	for _, input := range invalid {
+		if _, err := Compile(input); err == nil {
+			t.Errorf("Compile(%q) should fail", input)
+		}
+	}
+}
+
+func TestPlainAndUsesGit(t *testing.T) {
+	tests := []struct {
+		query   string
+		plain   bool
+		usesGit bool
+	}{
+		{"", true, false},
+		{"golang", true, false},
+		{"golang/go", true, false},
+		{"name:go", false, false},
+		{"go web", false, false},
+		{"-go", false, false},
+		{"dirty:true", false, true},
+		{"name:go OR NOT dirty:true", false, true},
+	}
+	for _, tt := range tests {
+		q, err := Compile(tt.query)
+		if err != nil {
+			t.Fatalf("Compile(%q) failed: %v", tt.query, err)
+		}
+		if q.Plain() != tt.plain {
+			t.Errorf("Query %q: expected Plain() = %v", tt.query, tt.plain)
+		}
+		if q.UsesGit() != tt.usesGit {
+			t.Errorf("Query %q: expected UsesGit() = %v", tt.query, tt.usesGit)
+		}
+	}
+}
+
+func TestKeyword(t *testing.T) {
+	tests := []struct {
+		query   string
+		keyword string
+		rest    bool
+		ok      bool
+	}{
+		{"", "", false, true},
+		{"API", "api", false, true},
+		{"(api) tag:payments", "api", true, true},
+		{"tag:payments api host:gitlab.com", "api", true, true},
+		{"tag:payments", "", false, false},
+		{"api web", "", false, false},
+		{"api OR web", "", false, false},
+		{"-api tag:payments", "", false, false},
+	}
+	for _, tt := range tests {
+		q, err := Compile(tt.query)
+		if err != nil {
+			t.Fatalf("Compile(%q) failed: %v", tt.query, err)
+		}
+		keyword, rest, ok := q.Keyword()
+		if keyword != tt.keyword || (rest != nil) != tt.rest || ok != tt.ok {
+			t.Errorf("Query %q: Keyword() = %q, %v, %v", tt.query, keyword, rest != nil, ok)
+		}
+	}
+	
+	q, _ := Compile("api tag:payments host:gitlab.com")
+	_, rest, _ := q.Keyword()
+	repos, err := rest.Filter(queryRepos(), nil)
+	if err != nil || len(repos) != 1 || repos[0].Name != "api-gateway" {
+		t.Errorf("Expected rest to keep the other conditions, got %v (%v)", names(repos), err)
+	}
+}
+
+func TestGitStateEvaluatedLast(t *testing.T) {
+	state := &fakeState{dirty: map[string]bool{}}
+	q, err := Compile("dirty:true name:go")
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if _, err := q.Filter(queryRepos(), state); err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	// 只有名称匹配的仓库才需要读取 git 状态
+	if state.calls != 1 {
+		t.Errorf("Expected 1 git state call, got %d", state.calls)
+	}
+	
+	if _, err := q.Filter(queryRepos(), errState{}); err == nil {
+		t.Error("Expected git state error to be returned")
+	}
+}
\ No newline at end of file
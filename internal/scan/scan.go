@@ -0,0 +1,168 @@
+package scan
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/atian25/projj-go/internal/git"
+)
+
+// DefaultIgnore 默认跳过的目录名，这些目录通常很大且不会包含需要管理的仓库
+var DefaultIgnore = []string{"node_modules", "vendor", ".cache"}
+
+// Options 表示扫描选项
+type Options struct {
+	Workers  int                 // 并发数，小于等于 0 时使用 CPU 数
+	Ignore   []string            // 跳过的目录名，支持通配符，为空时使用 DefaultIgnore
+	Nested   bool                // 继续扫描仓库内部嵌套的仓库和子模块
+	Progress func(Progress)      // 进度回调，串行调用，可为空
+	Warn     func(string, error) // 无法访问目录时的回调，可为空
+}
+
+// Progress 表示扫描进度
+type Progress struct {
+	Dirs  int // 已扫描的目录数
+	Repos int // 已发现的仓库数
+}
+
+// Repo 表示扫描发现的 Git 仓库
+type Repo struct {
+	Path      string
+	RemoteURL string // origin URL，未配置时为空
+	Err       error  // 读取 origin URL 失败的原因
+}
+
+// scanner 保存一次扫描的共享状态
+type scanner struct {
+	opts     Options
+	sem      chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	repos    []Repo
+	progress Progress
+}
+
+// Scan 并发扫描目录下的所有 Git 仓库，结果按路径排序
+func Scan(root string, opts Options) ([]Repo, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+	
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if len(opts.Ignore) == 0 {
+		opts.Ignore = DefaultIgnore
+	}
+	
+	s := &scanner{
+		opts: opts,
+		sem:  make(chan struct{}, opts.Workers),
+	}
+	
+	s.wg.Add(1)
+	go s.visit(root)
+	s.wg.Wait()
+	
+	sort.Slice(s.repos, func(i, j int) bool {
+		return s.repos[i].Path < s.repos[j].Path
+	})
+	
+	return s.repos, nil
+}
+
+// visit 扫描单个目录，子目录交给新的 goroutine 处理，并发数由信号量限制
+func (s *scanner) visit(dir string) {
+	defer s.wg.Done()
+	
+	s.sem <- struct{}{}
+	subdirs, repo := s.readDir(dir)
+	<-s.sem
+	
+	s.mu.Lock()
+	s.progress.Dirs++
+	if repo != nil {
+		s.repos = append(s.repos, *repo)
+		s.progress.Repos++
+	}
+	if s.opts.Progress != nil {
+		s.opts.Progress(s.progress)
+	}
+	s.mu.Unlock()
+	
+	for _, subdir := range subdirs {
+		s.wg.Add(1)
+		go s.visit(subdir)
+	}
+}
+
+// readDir 读取目录，返回需要继续扫描的子目录和该目录对应的仓库
+func (s *scanner) readDir(dir string) ([]string, *Repo) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if s.opts.Warn != nil {
+			s.opts.Warn(dir, err)
+		}
+		return nil, nil
+	}
+	
+	var repo *Repo
+	for _, entry := range entries {
+		if entry.Name() == ".git" {
+			repo = readRepo(dir)
+			break
+		}
+	}
+	
+	// 默认不进入嵌套仓库和子模块
+	if repo != nil && !s.opts.Nested {
+		return nil, repo
+	}
+	
+	var subdirs []string
+	for _, entry := range entries {
+		// 不跟随符号链接，避免循环
+		if !entry.IsDir() || entry.Name() == ".git" || s.ignored(entry.Name()) {
+			continue
+		}
+		subdirs = append(subdirs, filepath.Join(dir, entry.Name()))
+	}
+	
+	return subdirs, repo
+}
+
+// ignored 检查目录名是否在忽略列表中
+func (s *scanner) ignored(name string) bool {
+	for _, pattern := range s.opts.Ignore {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// readRepo 读取仓库的 origin URL，优先直接解析配置文件，失败时回退到 git 命令
+func readRepo(dir string) *Repo {
+	repo := &Repo{Path: dir}
+	
+	remoteURL, err := git.ReadRemoteURL(dir, "origin")
+	if errors.Is(err, git.ErrNoRemote) {
+		repo.Err = err
+		return repo
+	}
+	if err != nil {
+		remoteURL, err = git.GetRemoteURL(dir)
+	}
+	
+	repo.RemoteURL = remoteURL
+	repo.Err = err
+	return repo
+}
\ No newline at end of file
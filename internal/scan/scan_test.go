@@ -0,0 +1,92 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// createRepo 创建带有 origin 配置的模拟仓库
+func createRepo(t *testing.T, dir, remoteURL string) {
+	t.Helper()
+	
+	gitDir := filepath.Join(dir, ".git")
+	if err := os.MkdirAll(gitDir, 0755); err != nil {
+		t.Fatalf("Failed to create .git dir: %v", err)
+	}
+	
+	config := "[core]\n\tbare = false\n"
+	if remoteURL != "" {
+		config += fmt.Sprintf("[remote \"origin\"]\n\turl = %s\n\tfetch = +refs/heads/*:refs/remotes/origin/*\n", remoteURL)
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "config"), []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write git config: %v", err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "projj-scan-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	createRepo(t, filepath.Join(tempDir, "github.com", "user", "repo1"), "git@github.com:user/repo1.git")
+	createRepo(t, filepath.Join(tempDir, "github.com", "user", "repo2"), "https://github.com/user/repo2.git")
+	createRepo(t, filepath.Join(tempDir, "github.com", "user", "repo1", "sub", "nested"), "git@github.com:user/nested.git")
+	createRepo(t, filepath.Join(tempDir, "web", "node_modules", "dep"), "git@github.com:user/dep.git")
+	createRepo(t, filepath.Join(tempDir, "local", "no-remote"), "")
+	
+	var last Progress
+	repos, err := Scan(tempDir, Options{
+		Workers:  4,
+		Progress: func(p Progress) { last = p },
+	})
+	if err != nil {
+		t.Fatalf("Scan() failed: %v", err)
+	}
+	
+	// 默认忽略 node_modules，不进入嵌套仓库
+	if len(repos) != 3 {
+		t.Fatalf("Expected 3 repositories, got %d: %+v", len(repos), repos)
+	}
+	
+	expected := map[string]string{
+		filepath.Join(tempDir, "github.com", "user", "repo1"): "git@github.com:user/repo1.git",
+		filepath.Join(tempDir, "github.com", "user", "repo2"): "https://github.com/user/repo2.git",
+		filepath.Join(tempDir, "local", "no-remote"):          "",
+	}
+	for _, repo := range repos {
+		url, ok := expected[repo.Path]
+		if !ok {
+			t.Errorf("Unexpected repository: %s", repo.Path)
+			continue
+		}
+		if repo.RemoteURL != url {
+			t.Errorf("Expected remote %s for %s, got %s", url, repo.Path, repo.RemoteURL)
+		}
+		if url == "" && repo.Err == nil {
+			t.Errorf("Expected error for repository without origin: %s", repo.Path)
+		}
+	}
+	
+	if last.Repos != 3 || last.Dirs == 0 {
+		t.Errorf("Unexpected final progress: %+v", last)
+	}
+	
+	// 开启 nested 并自定义忽略列表
+	repos, err = Scan(tempDir, Options{Nested: true, Ignore: []string{"loc*"}})
+	if err != nil {
+		t.Fatalf("Scan() failed: %v", err)
+	}
+	if len(repos) != 4 {
+		t.Errorf("Expected 4 repositories with nested and custom ignore, got %d: %+v", len(repos), repos)
+	}
+}
+
+func TestScanMissingRoot(t *testing.T) {
+	if _, err := Scan("/nonexistent/path", Options{}); err == nil {
+		t.Error("Scan() should fail for nonexistent root")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,271 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/atian25/projj-go/internal/git"
+)
+
+// binaryProbe 判断二进制文件时检查的字节数，与 git 一致
+const binaryProbe = 8000
+
+// maxLine 单行的最大长度，超过时停止搜索该文件
+const maxLine = 16 << 20
+
+// Grep 搜索目录下的文本文件，结果按路径和行号排序：
+// 目录是 git 仓库且可以执行 git 时使用 git grep，只搜索已跟踪的文件（opts.Untracked 时包括未跟踪的文件）；
+// 否则遍历目录，遵循各级 .gitignore 并跳过二进制文件
+func Grep(ctx context.Context, root, pattern string, opts git.GrepOptions) ([]git.GrepMatch, error) {
+	if UsesGit(root) {
+		return git.Grep(ctx, root, pattern, opts)
+	}
+	return Walk(ctx, root, pattern, opts)
+}
+
+// UsesGit 判断 Grep 是否使用 git grep 搜索目录，此时模式为扩展正则表达式而不是 Go 正则表达式
+func UsesGit(root string) bool {
+	return git.IsGitRepository(root) && git.Available()
+}
+
+// Walk 不依赖 git，遍历目录搜索文本文件，遵循各级 .gitignore，跳过 .git 目录和二进制文件
+func Walk(ctx context.Context, root, pattern string, opts git.GrepOptions) ([]git.GrepMatch, error) {
+	re, err := Compile(pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+	
+	var ignore ignoreRules
+	var matches []git.GrepMatch
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// 无法访问的子目录和文件直接跳过
+			if p == root {
+				return err
+			}
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		
+		rel, _ := filepath.Rel(root, p)
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel == "." {
+				ignore.load(root, "")
+				return nil
+			}
+			if d.Name() == ".git" || ignore.match(rel, true) {
+				return filepath.SkipDir
+			}
+			ignore.load(p, rel)
+			return nil
+		}
+		if !d.Type().IsRegular() || ignore.match(rel, false) {
+			return nil
+		}
+		
+		found, err := grepFile(p, rel, re, opts.FilesOnly)
+		if err != nil {
+			return nil
+		}
+		matches = append(matches, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("搜索失败: %w", err)
+	}
+	return matches, nil
+}
+
+// Compile 按搜索选项把模式编译为正则表达式
+func Compile(pattern string, opts git.GrepOptions) (*regexp.Regexp, error) {
+	expr := pattern
+	if opts.Fixed {
+		expr = regexp.QuoteMeta(pattern)
+	}
+	if opts.Word {
+		expr = `\b(?:` + expr + `)\b`
+	}
+	if opts.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("无效的搜索模式 %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// grepFile 逐行读取并搜索单个文件，开头 binaryProbe 字节中含有 NUL 的二进制文件返回空结果
+func grepFile(p, rel string, re *regexp.Regexp, filesOnly bool) ([]git.GrepMatch, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	
+	probe := make([]byte, binaryProbe)
+	size, err := io.ReadFull(file, probe)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	probe = probe[:size]
+	if bytes.IndexByte(probe, 0) >= 0 {
+		return nil, nil
+	}
+	
+	var matches []git.GrepMatch
+	scanner := bufio.NewScanner(io.MultiReader(bytes.NewReader(probe), file))
+	scanner.Buffer(nil, maxLine)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if !re.MatchString(line) {
+			continue
+		}
+		if filesOnly {
+			return []git.GrepMatch{{Path: rel}}, nil
+		}
+		matches = append(matches, git.GrepMatch{Path: rel, Line: n, Text: line})
+	}
+	return matches, scanner.Err()
+}
+
+// ignoreRule 表示 .gitignore 中的一条规则
+type ignoreRule struct {
+	base    string         // .gitignore 所在目录相对于搜索根目录的路径，根目录为空
+	re      *regexp.Regexp // 匹配相对于 base 的路径
+	negate  bool           // 以 ! 开头，重新包含之前排除的路径
+	dirOnly bool           // 以 / 结尾，只匹配目录
+}
+
+// ignoreRules 按读取顺序保存的规则，上级目录的规则在前，后面的规则优先
+type ignoreRules []ignoreRule
+
+// load 读取目录中的 .gitignore，不存在时忽略
+func (r *ignoreRules) load(dir, base string) {
+	file, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreRule(base, scanner.Text()); ok {
+			*r = append(*r, rule)
+		}
+	}
+}
+
+// match 判断相对于搜索根目录的路径是否被忽略
+func (r ignoreRules) match(rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range r {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		p := rel
+		if rule.base != "" {
+			var ok bool
+			if p, ok = strings.CutPrefix(rel, rule.base+"/"); !ok {
+				continue
+			}
+		}
+		if rule.re.MatchString(p) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// parseIgnoreRule 解析 .gitignore 中的一行，空行和注释返回 false；
+// 不含 / 的模式匹配任意层级的文件名，其余模式相对于 .gitignore 所在目录匹配
+func parseIgnoreRule(base, line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+	
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, `\`)
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	expr := globToRegexp(line)
+	if !anchored {
+		expr = `(?:.*/)?` + expr
+	}
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// globToRegexp 把 .gitignore 的通配符转换为正则表达式：** 匹配任意层级，* 和 ? 不匹配 /
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if strings.HasPrefix(glob[i:], "**") {
+				i++
+				// a/**/b 同时匹配 a/b
+				if strings.HasPrefix(glob[i+1:], "/") {
+					i++
+					b.WriteString(`(?:.*/)?`)
+				} else {
+					b.WriteString(`.*`)
+				}
+				continue
+			}
+			b.WriteString(`[^/]*`)
+		case '?':
+			b.WriteString(`[^/]`)
+		case '[':
+			if end := strings.IndexByte(glob[i+1:], ']'); end > 0 {
+				class := glob[i+1 : i+1+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				b.WriteString("[" + class + "]")
+				i += end + 1
+				continue
+			}
+			b.WriteString(`\[`)
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
\ No newline at end of file
@@ -0,0 +1,187 @@
+package search
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/atian25/projj-go/internal/git"
+)
+
+func TestWalk(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "projj-search-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	files := map[string]string{
+		".gitignore":     "*.log\nbuild/\n/root.txt\n!keep.log\n",
+		"main.go":        "package main\n// TODO: fix\n",
+		"root.txt":       "TODO root\n",
+		"debug.log":      "TODO log\n",
+		"keep.log":       "TODO keep\n",
+		"data.bin":       "TODO\x00binary",
+		"build/out.go":   "TODO build\n",
+		"sub/root.txt":   "TODO nested\r\n",
+		"sub/.gitignore": "*.md\n",
+		"sub/readme.md":  "TODO readme\n",
+		"docs/readme.md": "TODO docs\n",
+		".git/config":    "TODO git\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	
+	matches, err := Walk(context.Background(), tempDir, "TODO", git.GrepOptions{})
+	if err != nil {
+		t.Fatalf("Walk() failed: %v", err)
+	}
+	expected := []git.GrepMatch{
+		{Path: "docs/readme.md", Line: 1, Text: "TODO docs"},
+		{Path: "keep.log", Line: 1, Text: "TODO keep"},
+		{Path: "main.go", Line: 2, Text: "// TODO: fix"},
+		{Path: "sub/root.txt", Line: 1, Text: "TODO nested"},
+	}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, matches)
+	}
+	
+	matches, _ = Walk(context.Background(), tempDir, "todo:", git.GrepOptions{IgnoreCase: true, FilesOnly: true})
+	if !reflect.DeepEqual(matches, []git.GrepMatch{{Path: "main.go"}}) {
+		t.Errorf("Expected only main.go, got %+v", matches)
+	}
+	
+	if _, err := Walk(context.Background(), tempDir, "fix(", git.GrepOptions{}); err == nil {
+		t.Error("Walk() should fail with invalid pattern")
+	}
+}
+
+func TestGrepGitRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	repo, err := os.MkdirTemp("", "projj-search-git-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(repo)
+	
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+	run("init", "-q")
+	os.MkdirAll(filepath.Join(repo, "src"), 0755)
+	os.WriteFile(filepath.Join(repo, "src", "main.go"), []byte("package main\n// TODO: fix 42\nfunc todoList() {}\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "data.bin"), []byte("TODO\x00binary"), 0644)
+	run("add", ".")
+	os.WriteFile(filepath.Join(repo, "untracked.txt"), []byte("TODO later\n"), 0644)
+	
+	ctx := context.Background()
+	matches, err := Grep(ctx, repo, "TODO", git.GrepOptions{})
+	if err != nil {
+		t.Fatalf("Grep() failed: %v", err)
+	}
+	expected := []git.GrepMatch{{Path: "src/main.go", Line: 2, Text: "// TODO: fix 42"}}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, matches)
+	}
+	
+	// 使用 git grep 时只搜索已跟踪的文件，--untracked 时包括未跟踪的文件
+	matches, _ = Grep(ctx, repo, "TODO", git.GrepOptions{Untracked: true, FilesOnly: true})
+	expected = []git.GrepMatch{{Path: "src/main.go"}, {Path: "untracked.txt"}}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, matches)
+	}
+	
+	// 模式为扩展正则表达式，由 git 校验
+	if _, err := Grep(ctx, repo, "fix(", git.GrepOptions{}); err == nil {
+		t.Error("Grep() should fail with invalid pattern")
+	}
+}
+
+func TestGrepFile(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "projj-grep-file-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	re := regexp.MustCompile("TODO")
+	// 只检查开头的字节判断二进制文件，之后的内容逐行读取
+	text := filepath.Join(tempDir, "large.txt")
+	content := strings.Repeat("line\n", binaryProbe) + "TODO \x00 later\r\nTODO end"
+	os.WriteFile(text, []byte(content), 0644)
+	matches, err := grepFile(text, "large.txt", re, false)
+	expected := []git.GrepMatch{
+		{Path: "large.txt", Line: binaryProbe + 1, Text: "TODO \x00 later"},
+		{Path: "large.txt", Line: binaryProbe + 2, Text: "TODO end"},
+	}
+	if err != nil || !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Expected %+v, got %+v (%v)", expected, matches, err)
+	}
+	
+	binary := filepath.Join(tempDir, "data.bin")
+	os.WriteFile(binary, []byte("TODO\x00binary"), 0644)
+	if matches, err := grepFile(binary, "data.bin", re, false); err != nil || len(matches) != 0 {
+		t.Errorf("Expected binary file to be skipped, got %+v (%v)", matches, err)
+	}
+	
+	short := filepath.Join(tempDir, "short.txt")
+	os.WriteFile(short, []byte("TODO"), 0644)
+	if matches, err := grepFile(short, "short.txt", re, true); err != nil || !reflect.DeepEqual(matches, []git.GrepMatch{{Path: "short.txt"}}) {
+		t.Errorf("Expected file shorter than the probe to match, got %+v (%v)", matches, err)
+	}
+}
+
+func TestIgnoreRules(t *testing.T) {
+	tests := []struct {
+		base    string
+		pattern string
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"", "*.log", "a/b/c.log", false, true},
+		{"", "/*.log", "a/c.log", false, false},
+		{"", "build/", "a/build", true, true},
+		{"", "build/", "a/build", false, false},
+		{"", "docs/*.md", "docs/a.md", false, true},
+		{"", "docs/*.md", "x/docs/a.md", false, false},
+		{"", "**/gen/*.go", "a/b/gen/x.go", false, true},
+		{"", "a/**/z", "a/z", false, true},
+		{"", "file[0-9].txt", "file3.txt", false, true},
+		{"sub", "*.md", "sub/x/a.md", false, true},
+		{"sub", "*.md", "other/a.md", false, false},
+	}
+	
+	for _, tt := range tests {
+		rule, ok := parseIgnoreRule(tt.base, tt.pattern)
+		if !ok {
+			t.Fatalf("parseIgnoreRule(%q) failed", tt.pattern)
+		}
+		if got := (ignoreRules{rule}).match(tt.path, tt.isDir); got != tt.ignored {
+			t.Errorf("Pattern %q on %q (dir=%v): expected %v, got %v", tt.pattern, tt.path, tt.isDir, tt.ignored, got)
+		}
+	}
+	
+	for _, line := range []string{"", "# comment", "!", "/"} {
+		if _, ok := parseIgnoreRule("", line); ok {
+			t.Errorf("Expected %q to be skipped", line)
+		}
+	}
+}
\ No newline at end of file
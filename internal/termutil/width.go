@@ -0,0 +1,64 @@
+package termutil
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RuneWidth 返回字符在终端中占用的列数，中日韩文字和全角符号占两列
+func RuneWidth(r rune) int {
+	switch {
+	case unicode.Is(unicode.Han, r), unicode.Is(unicode.Hangul, r),
+		unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r),
+		r >= 0x3000 && r <= 0x303f, r >= 0xff00 && r <= 0xff60:
+		return 2
+	}
+	return 1
+}
+
+// Width 返回字符串在终端中占用的列数，颜色控制序列不占宽度
+func Width(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			if end := strings.IndexByte(s[i:], 'm'); end > 0 {
+				i += end + 1
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		width += RuneWidth(r)
+		i += size
+	}
+	return width
+}
+
+// Truncate 截断超出终端宽度的内容，避免自动换行打乱重绘；颜色控制序列不占宽度
+func Truncate(s string, width int) string {
+	if width <= 1 {
+		return s
+	}
+	
+	var b strings.Builder
+	used := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			if end := strings.IndexByte(s[i:], 'm'); end > 0 {
+				b.WriteString(s[i : i+end+1])
+				i += end + 1
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := RuneWidth(r)
+		if used+w >= width {
+			b.WriteString("…")
+			return b.String()
+		}
+		used += w
+		b.WriteRune(r)
+		i += size
+	}
+	return s
+}
\ No newline at end of file
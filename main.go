@@ -12,6 +12,10 @@ func main() {
 	app := cmd.NewApp()
 	
 	if err := app.Run(context.Background(), os.Args); err != nil {
+		// 机器可读格式下错误以结构化对象输出
+		if cmd.ReportError(app, err) {
+			os.Exit(1)
+		}
 		log.Fatal(err)
 	}
 }
\ No newline at end of file
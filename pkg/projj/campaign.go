@@ -0,0 +1,300 @@
+package projj
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/campaign"
+	"github.com/atian25/projj-go/internal/fsutil"
+	"github.com/atian25/projj-go/internal/git"
+	"github.com/atian25/projj-go/internal/hook"
+)
+
+// CampaignOptions 表示执行活动的选项
+type CampaignOptions struct {
+	Workers int       // 并发数，为 0 时使用配置中的 scan_workers 或 CPU 数
+	Push    bool      // 提交后推送活动分支
+	Stdout  io.Writer // 脚本的输出位置，为空时使用 os.Stdout
+}
+
+// RollbackOptions 表示回滚活动的选项
+type RollbackOptions struct {
+	Force  bool // 丢弃仓库中未提交的修改
+	Remote bool // 同时删除已推送的远程分支
+}
+
+// NewCampaign 创建活动，选中满足查询的仓库并保存状态，尚未执行
+func (c *Client) NewCampaign(name, branch, script, message, filter string) (*campaign.Campaign, error) {
+	if branch == "" {
+		branch = name
+	}
+	if script == "" || message == "" {
+		return nil, fmt.Errorf("请提供要执行的脚本和提交信息")
+	}
+	
+	repos, err := c.Query(filter)
+	if err != nil {
+		return nil, fmt.Errorf("查询仓库失败: %w", err)
+	}
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("没有满足条件的仓库")
+	}
+	
+	camp := &campaign.Campaign{
+		Name:      name,
+		Branch:    branch,
+		Script:    script,
+		Message:   message,
+		CreatedAt: time.Now(),
+	}
+	for _, repo := range repos {
+		camp.Repos = append(camp.Repos, campaign.Repo{
+			Name:      repo.Name,
+			Path:      repo.Path,
+			URL:       repo.URL,
+			State:     campaign.Pending,
+			UpdatedAt: camp.CreatedAt,
+		})
+	}
+	
+	if err := campaign.Create(camp); err != nil {
+		return nil, err
+	}
+	return camp, nil
+}
+
+// RunCampaign 并发执行活动中尚未完成的仓库，每个仓库完成后保存状态，中断后可以再次执行继续；
+// 每个仓库依次创建分支、执行脚本、提交修改，opts.Push 为 true 时再推送分支
+func (c *Client) RunCampaign(ctx context.Context, camp *campaign.Campaign, opts CampaignOptions) error {
+	stdout := opts.Stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	
+	var pending []campaign.Repo
+	for _, repo := range camp.Repos {
+		if !repo.Done(opts.Push) {
+			pending = append(pending, repo)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	
+	return c.campaignRepos(camp, pending, opts.Workers, stdout, func(repo campaign.Repo, out io.Writer, save func(campaign.Repo)) campaign.Repo {
+		return c.advanceCampaign(ctx, camp, repo, opts.Push, out, save)
+	})
+}
+
+// RollbackCampaign 回滚活动：切换回原来的分支并删除活动分支，opts.Remote 为 true 时同时删除远程分支；
+// 已回滚但保留了远程分支的仓库可以再次使用 opts.Remote 回滚
+func (c *Client) RollbackCampaign(ctx context.Context, camp *campaign.Campaign, opts RollbackOptions) error {
+	var targets []campaign.Repo
+	for _, repo := range camp.Repos {
+		if repo.State != campaign.RolledBack || (repo.RemoteBranch && opts.Remote) {
+			targets = append(targets, repo)
+		}
+	}
+	
+	return c.campaignRepos(camp, targets, 0, c.out, func(repo campaign.Repo, out io.Writer, _ func(campaign.Repo)) campaign.Repo {
+		return rollbackRepo(ctx, camp, repo, opts)
+	})
+}
+
+// campaignResult 表示处理活动中一个仓库的结果和输出
+type campaignResult struct {
+	repo   campaign.Repo
+	output []byte
+}
+
+// campaignRepos 并发处理活动中的仓库，每个仓库完成后整块输出结果并保存活动状态，避免并发输出交错；
+// fn 可以通过 save 在处理过程中提前保存仓库的状态，中断后从保存的状态继续
+func (c *Client) campaignRepos(camp *campaign.Campaign, repos []campaign.Repo, workers int, w io.Writer, fn func(repo campaign.Repo, out io.Writer, save func(campaign.Repo)) campaign.Repo) error {
+	// parallel 按缓存中的仓库分配任务，这里通过路径找回活动中的仓库
+	byPath := make(map[string]campaign.Repo, len(repos))
+	list := make([]cache.Repository, len(repos))
+	for i, repo := range repos {
+		byPath[repo.Path] = repo
+		list[i] = cache.Repository{Name: repo.Name, Path: repo.Path, URL: repo.URL}
+	}
+	
+	var mu sync.Mutex
+	var saveErr error
+	save := func(repo campaign.Repo) {
+		mu.Lock()
+		defer mu.Unlock()
+		camp.Set(repo)
+		if err := camp.Save(); err != nil && saveErr == nil {
+			saveErr = err
+		}
+	}
+	parallel(list, c.workers(workers), func(r cache.Repository) campaignResult {
+		var out bytes.Buffer
+		repo := fn(byPath[r.Path], &out, save)
+		return campaignResult{repo: repo, output: out.Bytes()}
+	}, func(_ int, result campaignResult) {
+		writeCampaignOutput(w, result.repo, result.output)
+		save(result.repo)
+	})
+	return saveErr
+}
+
+// advanceCampaign 从仓库当前的状态继续执行活动，失败时记录错误并停在失败前的状态；
+// 创建分支前通过 save 保存原来的分支，创建后先保存状态再切换，避免中断后无法继续或回滚
+func (c *Client) advanceCampaign(ctx context.Context, camp *campaign.Campaign, repo campaign.Repo, push bool, out io.Writer, save func(campaign.Repo)) campaign.Repo {
+	repo.Error = ""
+	repo.UpdatedAt = time.Now()
+	fail := func(err error) campaign.Repo {
+		repo.Error = err.Error()
+		return repo
+	}
+	
+	if !fsutil.Exists(repo.Path) {
+		return fail(fmt.Errorf("仓库目录不存在: %s", repo.Path))
+	}
+	
+	if repo.State == campaign.Pending {
+		status, err := git.Inspect(repo.Path)
+		switch {
+		case err != nil:
+			return fail(err)
+		case status.Dirty():
+			return fail(fmt.Errorf("有未提交的修改"))
+		case status.Detached:
+			return fail(fmt.Errorf("处于分离 HEAD 状态"))
+		}
+		if !repo.CreatedBranch {
+			// 同名分支不是活动创建的，不能在其上提交，回滚时也不能删除
+			if git.BranchExists(repo.Path, camp.Branch) {
+				return fail(fmt.Errorf("分支 %s 已存在，请删除该分支或为活动指定其他分支", camp.Branch))
+			}
+			repo.OriginalBranch, repo.CreatedBranch = status.Branch, true
+			save(repo)
+		}
+		// 上次在创建分支前后中断时分支可能已存在，由活动创建的分支直接继续使用
+		if !git.BranchExists(repo.Path, camp.Branch) {
+			if err := git.CreateBranch(repo.Path, camp.Branch); err != nil {
+				repo.CreatedBranch = false
+				return fail(err)
+			}
+		}
+		repo.State = campaign.Branched
+		save(repo)
+	}
+	
+	if repo.State == campaign.Branched {
+		// 中途可能切换过分支
+		if err := git.Checkout(repo.Path, camp.Branch); err != nil {
+			return fail(err)
+		}
+		err := hook.Exec(ctx, []string{camp.Script}, hook.Options{
+			Dir: repo.Path,
+			Env: append(c.repoEnv(cache.Repository{Name: repo.Name, Path: repo.Path, URL: repo.URL}),
+				"PROJJ_CAMPAIGN_NAME="+camp.Name,
+				"PROJJ_CAMPAIGN_BRANCH="+camp.Branch,
+			),
+			Stdin:  strings.NewReader(""),
+			Stdout: out,
+			Stderr: out,
+		})
+		if err != nil {
+			return fail(fmt.Errorf("执行脚本失败: %w", err))
+		}
+		commit, err := git.CommitAll(repo.Path, camp.Message)
+		if err != nil {
+			return fail(err)
+		}
+		repo.State, repo.Commit = campaign.Unchanged, commit
+		if commit != "" {
+			repo.State = campaign.Committed
+		}
+	}
+	
+	if repo.State == campaign.Committed && push {
+		if err := git.Push(ctx, repo.Path, "origin", camp.Branch); err != nil {
+			return fail(err)
+		}
+		repo.State, repo.RemoteBranch = campaign.Pushed, true
+	}
+	
+	return repo
+}
+
+// rollbackRepo 回滚单个仓库，尚未创建分支的仓库直接标记为已回滚
+func rollbackRepo(ctx context.Context, camp *campaign.Campaign, repo campaign.Repo, opts RollbackOptions) campaign.Repo {
+	repo.Error = ""
+	repo.UpdatedAt = time.Now()
+	fail := func(err error) campaign.Repo {
+		repo.Error = err.Error()
+		return repo
+	}
+	
+	if repo.State == campaign.Pending && !repo.CreatedBranch {
+		repo.State = campaign.RolledBack
+		return repo
+	}
+	if !fsutil.Exists(repo.Path) {
+		return fail(fmt.Errorf("仓库目录不存在: %s", repo.Path))
+	}
+	
+	status, err := git.Inspect(repo.Path)
+	if err != nil {
+		return fail(err)
+	}
+	if status.Dirty() {
+		if !opts.Force {
+			return fail(fmt.Errorf("有未提交的修改，使用 --force 丢弃"))
+		}
+		// 只丢弃活动分支上的修改，其他分支上的修改不属于活动
+		if status.Branch != camp.Branch {
+			return fail(fmt.Errorf("当前分支 %s 不是活动分支 %s，有未提交的修改，请先处理后再回滚", status.Branch, camp.Branch))
+		}
+		if err := git.Discard(repo.Path); err != nil {
+			return fail(err)
+		}
+	}
+	
+	if status.Branch == camp.Branch && repo.OriginalBranch != "" {
+		if err := git.Checkout(repo.Path, repo.OriginalBranch); err != nil {
+			return fail(err)
+		}
+	}
+	// 只删除活动创建的分支
+	if repo.CreatedBranch && git.BranchExists(repo.Path, camp.Branch) {
+		if err := git.DeleteBranch(repo.Path, camp.Branch); err != nil {
+			return fail(err)
+		}
+	}
+	repo.CreatedBranch = false
+	if repo.RemoteBranch && opts.Remote {
+		if err := git.DeleteRemoteBranch(ctx, repo.Path, "origin", camp.Branch); err != nil {
+			return fail(err)
+		}
+		repo.RemoteBranch = false
+	}
+	
+	repo.State, repo.Commit = campaign.RolledBack, ""
+	return repo
+}
+
+// writeCampaignOutput 输出仓库的结果和脚本的输出
+func writeCampaignOutput(w io.Writer, repo campaign.Repo, output []byte) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "==> %s (%s): %s", repo.Name, repo.Path, repo.State.Label())
+	if repo.Failed() {
+		fmt.Fprintf(&b, "，失败: %s", repo.Error)
+	}
+	b.WriteByte('\n')
+	b.Write(output)
+	if len(output) > 0 && !bytes.HasSuffix(output, []byte("\n")) {
+		b.WriteByte('\n')
+	}
+	w.Write(b.Bytes())
+}
\ No newline at end of file
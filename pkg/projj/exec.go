@@ -0,0 +1,151 @@
+package projj
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/git"
+	"github.com/atian25/projj-go/internal/hook"
+	"github.com/atian25/projj-go/internal/output"
+	"github.com/atian25/projj-go/internal/termutil"
+)
+
+// ExecOptions 表示在多个仓库中执行命令的选项
+type ExecOptions struct {
+	Filter   string    // 查询条件，为空时为所有仓库
+	Workers  int       // 并发数，为 0 时使用配置中的 scan_workers 或 CPU 数
+	Group    bool      // 每个仓库执行完后整体输出，否则逐行输出并加上仓库名前缀
+	FailFast bool      // 有仓库失败后不再启动新的仓库，并终止正在执行的命令
+	Stdout   io.Writer // 为空时使用 os.Stdout
+	Stderr   io.Writer // 为空时使用 os.Stderr
+}
+
+// ExecResult 表示单个仓库中命令的执行结果
+type ExecResult struct {
+	Repo     cache.Repository
+	ExitCode int   // 命令的退出码，命令不存在时为 127
+	Err      error // 执行失败的原因，成功时为空
+	Skipped  bool  // 因 fail-fast 而没有执行
+}
+
+// Exec 在满足查询的仓库目录中并发执行命令，结果保持仓库列表的顺序；
+// 命令的环境变量中包含 PROJJ_REPO_NAME、PROJJ_REPO_OWNER、PROJJ_REPO_HOST、PROJJ_REPO_PATH 和 PROJJ_REPO_URL
+func (c *Client) Exec(ctx context.Context, args []string, opts ExecOptions) ([]ExecResult, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("请提供要执行的命令")
+	}
+	
+	repos, err := c.Query(opts.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("查询仓库失败: %w", err)
+	}
+	
+	stdout, stderr := opts.Stdout, opts.Stderr
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+	
+	// 前缀按最长的仓库名对齐
+	width := 0
+	for _, repo := range repos {
+		width = max(width, termutil.Width(c.execLabel(repo)))
+	}
+	
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	
+	var mu sync.Mutex
+	results := parallel(repos, c.workers(opts.Workers), func(repo cache.Repository) ExecResult {
+		if ctx.Err() != nil {
+			return ExecResult{Repo: repo, Skipped: true}
+		}
+		
+		label := c.execLabel(repo)
+		var result ExecResult
+		if opts.Group {
+			var buf bytes.Buffer
+			result = c.execRepo(ctx, repo, args, &buf, &buf)
+			mu.Lock()
+			fmt.Fprintf(stdout, "==> %s (%s)\n", label, repo.Path)
+			if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+				buf.WriteByte('\n')
+			}
+			stdout.Write(buf.Bytes())
+			if result.Err != nil {
+				fmt.Fprintf(stderr, "错误: %v\n", result.Err)
+			}
+			mu.Unlock()
+		} else {
+			prefix := "[" + label + "]" + strings.Repeat(" ", width-termutil.Width(label)) + " "
+			out := output.NewPrefixWriter(stdout, prefix, &mu)
+			errOut := output.NewPrefixWriter(stderr, prefix, &mu)
+			result = c.execRepo(ctx, repo, args, out, errOut)
+			out.Flush()
+			if result.Err != nil {
+				fmt.Fprintf(errOut, "错误: %v\n", result.Err)
+			}
+			errOut.Flush()
+		}
+		
+		if result.Err != nil && opts.FailFast {
+			cancel()
+		}
+		return result
+	}, nil)
+	
+	return results, nil
+}
+
+// execRepo 在单个仓库中执行命令
+func (c *Client) execRepo(ctx context.Context, repo cache.Repository, args []string, stdout, stderr io.Writer) ExecResult {
+	if _, err := os.Stat(repo.Path); err != nil {
+		return ExecResult{Repo: repo, ExitCode: 1, Err: fmt.Errorf("仓库目录不存在: %s", repo.Path)}
+	}
+	
+	err := hook.Exec(ctx, args, hook.Options{
+		Dir:    repo.Path,
+		Env:    c.repoEnv(repo),
+		Stdin:  strings.NewReader(""),
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	if err != nil && ctx.Err() != nil {
+		// fail-fast 时被终止的命令
+		return ExecResult{Repo: repo, ExitCode: hook.ExitCode(err), Err: fmt.Errorf("已终止")}
+	}
+	return ExecResult{Repo: repo, ExitCode: hook.ExitCode(err), Err: err}
+}
+
+// execLabel 返回输出中标识仓库的名称，格式为 owner/name
+func (c *Client) execLabel(repo cache.Repository) string {
+	return repoOwner(repo, c.config.Alias) + "/" + repo.Name
+}
+
+// repoEnv 返回在仓库中执行命令时提供的环境变量
+func (c *Client) repoEnv(repo cache.Repository) []string {
+	return []string{
+		"PROJJ_REPO_NAME=" + repo.Name,
+		"PROJJ_REPO_OWNER=" + repoOwner(repo, c.config.Alias),
+		"PROJJ_REPO_HOST=" + c.repoHost(repo),
+		"PROJJ_REPO_PATH=" + repo.Path,
+		"PROJJ_REPO_URL=" + repo.URL,
+	}
+}
+
+// repoOwner 返回仓库的所有者，无法解析 URL 时取路径的上一级目录名
+func repoOwner(repo cache.Repository, aliases map[string]string) string {
+	if info, err := git.ParseURL(repo.URL, aliases); err == nil {
+		return info.Owner
+	}
+	return filepath.Base(filepath.Dir(repo.Path))
+}
\ No newline at end of file
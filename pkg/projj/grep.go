@@ -0,0 +1,66 @@
+package projj
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/fsutil"
+	"github.com/atian25/projj-go/internal/git"
+	"github.com/atian25/projj-go/internal/search"
+)
+
+// GrepOptions 表示跨仓库搜索的选项
+type GrepOptions struct {
+	git.GrepOptions
+	Filter  string // 查询条件，为空时为所有仓库
+	Workers int    // 并发数，为 0 时使用配置中的 scan_workers 或 CPU 数
+}
+
+// GrepResult 表示单个仓库的搜索结果
+type GrepResult struct {
+	Repo    cache.Repository
+	Matches []git.GrepMatch
+	Err     error // 搜索失败的原因
+}
+
+// Grep 在满足查询的仓库中并发搜索，结果保持仓库列表的顺序，只返回有匹配或搜索失败的仓库；
+// git 仓库使用 git grep 搜索已跟踪的文件，其他目录遍历文件并遵循 .gitignore，都会跳过二进制文件
+func (c *Client) Grep(ctx context.Context, pattern string, opts GrepOptions) ([]GrepResult, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("请提供搜索模式")
+	}
+	repos, err := c.Query(opts.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("查询仓库失败: %w", err)
+	}
+	// 有需要遍历的目录时提前校验 Go 正则表达式，避免每个目录都报告同样的错误；
+	// git grep 使用扩展正则表达式，由 git 自己校验
+	for _, repo := range repos {
+		if fsutil.Exists(repo.Path) && !search.UsesGit(repo.Path) {
+			if _, err := search.Compile(pattern, opts.GrepOptions); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	
+	results := parallel(repos, c.workers(opts.Workers), func(repo cache.Repository) GrepResult {
+		if err := ctx.Err(); err != nil {
+			return GrepResult{Repo: repo, Err: err}
+		}
+		if !fsutil.Exists(repo.Path) {
+			return GrepResult{Repo: repo, Err: fmt.Errorf("仓库目录不存在: %s", repo.Path)}
+		}
+		matches, err := search.Grep(ctx, repo.Path, pattern, opts.GrepOptions)
+		return GrepResult{Repo: repo, Matches: matches, Err: err}
+	}, nil)
+	
+	var found []GrepResult
+	for _, result := range results {
+		if result.Err != nil || len(result.Matches) > 0 {
+			found = append(found, result)
+		}
+	}
+	return found, nil
+}
\ No newline at end of file
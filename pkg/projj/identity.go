@@ -0,0 +1,117 @@
+package projj
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/git"
+)
+
+// IdentityIssue 表示仓库本地 git 配置与 postadd 配置不一致的项
+type IdentityIssue struct {
+	Repo     cache.Repository
+	Host     string
+	Key      string
+	Expected string
+	Actual   string
+}
+
+// identityFor 返回指定平台需要写入仓库的 git 配置，键名已按字母排序
+func (c *Client) identityFor(host string) ([]string, map[string]string) {
+	values := make(map[string]string)
+	for key, value := range c.config.PostAdd[host] {
+		// 允许省略 user. 前缀，如 name、email
+		if !strings.Contains(key, ".") {
+			key = "user." + key
+		}
+		values[key] = value
+	}
+	
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	
+	return keys, values
+}
+
+// repoHost 获取仓库所在平台的主机名
+func (c *Client) repoHost(repo cache.Repository) string {
+	if repoInfo, err := git.ParseURL(repo.URL, c.config.Alias); err == nil {
+		return repoInfo.Platform
+	}
+	return repo.Platform
+}
+
+// applyIdentity 将平台对应的 git 配置写入仓库本地配置
+func (c *Client) applyIdentity(repoPath, host string) error {
+	keys, values := c.identityFor(host)
+	for _, key := range keys {
+		if err := git.SetLocalConfig(repoPath, key, values[key]); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "设置 %s = %s\n", key, values[key])
+	}
+	return nil
+}
+
+// AuditIdentity 检查满足查询的仓库的本地 git 配置是否与 postadd 配置一致，查询为空时检查所有仓库
+func (c *Client) AuditIdentity(filter string) ([]IdentityIssue, error) {
+	repos, err := c.Query(filter)
+	if err != nil {
+		return nil, fmt.Errorf("查询仓库失败: %w", err)
+	}
+	
+	var issues []IdentityIssue
+	for _, repo := range repos {
+		host := c.repoHost(repo)
+		keys, values := c.identityFor(host)
+		if len(keys) == 0 {
+			continue
+		}
+		
+		if !git.IsGitRepository(repo.Path) {
+			fmt.Fprintf(os.Stderr, "警告: 跳过不存在的仓库: %s\n", repo.Path)
+			continue
+		}
+		
+		for _, key := range keys {
+			actual, err := git.GetLocalConfig(repo.Path, key)
+			if err != nil {
+				return nil, fmt.Errorf("检查仓库 %s 失败: %w", repo.Path, err)
+			}
+			if actual != values[key] {
+				issues = append(issues, IdentityIssue{
+					Repo:     repo,
+					Host:     host,
+					Key:      key,
+					Expected: values[key],
+					Actual:   actual,
+				})
+			}
+		}
+	}
+	
+	return issues, nil
+}
+
+// FixIdentity 修正满足查询的仓库中与 postadd 配置不一致的本地 git 配置，返回已修正和未修正的项；
+// 修正失败时停止，未修正的项包括失败的项和之后尚未处理的项
+func (c *Client) FixIdentity(filter string) (fixed, remaining []IdentityIssue, err error) {
+	issues, err := c.AuditIdentity(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	for i, issue := range issues {
+		if err := git.SetLocalConfig(issue.Repo.Path, issue.Key, issue.Expected); err != nil {
+			return issues[:i], issues[i:], fmt.Errorf("修正仓库 %s 失败: %w", issue.Repo.Path, err)
+		}
+	}
+	
+	return issues, nil, nil
+}
\ No newline at end of file
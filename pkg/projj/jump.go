@@ -0,0 +1,78 @@
+package projj
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/atian25/projj-go/internal/cache"
+)
+
+// Visit 记录一次对仓库的访问，失败时只输出警告，不影响当前命令；
+// 缓存不保存访问记录时（projj-node 格式）跳过，避免无意义地重写缓存文件
+func (c *Client) Visit(path string) {
+	if !c.config.CacheKeepsMetadata() {
+		return
+	}
+	if _, err := c.cache.Visit(path, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 记录访问失败: %v\n", err)
+	}
+}
+
+// Jump 在匹配查询的仓库中选出最常用的一个并记录访问，查询为空时从访问过的仓库中选择；
+// 与 Resolve 一致，子序列模糊匹配容易误中，不予考虑
+func (c *Client) Jump(query string) (*cache.Repository, error) {
+	found, err := c.Search(query)
+	if err != nil {
+		return nil, fmt.Errorf("查找仓库失败: %w", err)
+	}
+	var matches []cache.Match
+	for _, m := range found {
+		if m.Kind != cache.MatchFuzzy {
+			matches = append(matches, m)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("未找到匹配的仓库: %s", query)
+	}
+	
+	now := time.Now()
+	cache.SortByFrecency(matches, now)
+	repo := matches[0].Repo
+	if query == "" && cache.Frecency(repo, now) == 0 {
+		return nil, fmt.Errorf("还没有访问记录，请先使用 'projj find' 或 'projj jump <query>'")
+	}
+	
+	c.Visit(repo.Path)
+	return &repo, nil
+}
+
+// Recent 返回满足查询且访问过的仓库，按常用程度排序，limit 小于等于 0 时不限制数量
+func (c *Client) Recent(limit int, filter string) ([]cache.Repository, error) {
+	repos, err := c.Query(filter)
+	if err != nil {
+		return nil, fmt.Errorf("查询仓库失败: %w", err)
+	}
+	
+	var visited []cache.Repository
+	for _, repo := range repos {
+		if repo.Visits > 0 {
+			visited = append(visited, repo)
+		}
+	}
+	
+	now := time.Now()
+	sort.SliceStable(visited, func(i, j int) bool {
+		fi, fj := cache.Frecency(visited[i], now), cache.Frecency(visited[j], now)
+		if fi != fj {
+			return fi > fj
+		}
+		return visited[i].LastVisitedAt.After(visited[j].LastVisitedAt)
+	})
+	
+	if limit > 0 && len(visited) > limit {
+		visited = visited[:limit]
+	}
+	return visited, nil
+}
\ No newline at end of file
@@ -0,0 +1,70 @@
+package projj
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/git"
+	"github.com/atian25/projj-go/internal/picker"
+)
+
+// Chooser 在查询匹配的多个仓库中选出一个
+type Chooser func(repos []cache.Repository) (*cache.Repository, error)
+
+// SetChooser 设置查询匹配多个仓库时的选择方式，未设置时列出候选仓库并报错
+func (c *Client) SetChooser(chooser Chooser) {
+	c.chooser = chooser
+}
+
+// fzfPreview fzf 模式下的预览命令
+const fzfPreview = `echo {key}; git -C {key} remote get-url origin 2>/dev/null; git -C {key} branch --show-current 2>/dev/null`
+
+// PickRepo 通过内置选择器或 fzf 选择仓库，候选项按与过滤词的匹配程度排序
+func PickRepo(repos []cache.Repository, useFZF bool) (*cache.Repository, error) {
+	items := make([]picker.Item, len(repos))
+	positions := make(map[string]int, len(repos))
+	for i, repo := range repos {
+		items[i] = picker.Item{Label: fmt.Sprintf("%s (%s)", repo.Name, repo.Path), Key: repo.Path}
+		positions[repo.Path] = i
+	}
+	
+	opts := picker.Options{Prompt: "选择仓库> "}
+	var index int
+	var err error
+	if useFZF {
+		index, err = picker.PickFZF(items, opts, fzfPreview)
+	} else {
+		idx := cache.NewIndex(repos)
+		opts.Filter = func(q string) []int {
+			var result []int
+			for _, m := range idx.Search(q) {
+				result = append(result, positions[m.Repo.Path])
+			}
+			return result
+		}
+		opts.Preview = func(i int) []string {
+			return previewRepo(repos[i])
+		}
+		index, err = picker.Pick(items, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &repos[index], nil
+}
+
+// previewRepo 返回选择器中显示的仓库信息
+func previewRepo(repo cache.Repository) []string {
+	lines := []string{"Path: " + repo.Path, "URL: " + repo.URL}
+	if branch, err := git.CurrentBranch(repo.Path); err == nil {
+		lines = append(lines, "Branch: "+branch)
+	}
+	if len(repo.Tags) > 0 {
+		lines = append(lines, "Tags: "+strings.Join(repo.Tags, ", "))
+	}
+	if repo.Description != "" {
+		lines = append(lines, "Description: "+repo.Description)
+	}
+	return lines
+}
\ No newline at end of file
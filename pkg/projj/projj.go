@@ -1,20 +1,30 @@
 package projj
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/atian25/projj-go/internal/cache"
 	"github.com/atian25/projj-go/internal/config"
+	"github.com/atian25/projj-go/internal/fsutil"
 	"github.com/atian25/projj-go/internal/git"
+	"github.com/atian25/projj-go/internal/hook"
+	"github.com/atian25/projj-go/internal/query"
+	"github.com/atian25/projj-go/internal/scan"
 )
 
 // Client 表示 projj 客户端
 type Client struct {
-	config *config.Config
-	cache  *cache.Cache
+	config  *config.Config
+	cache   cache.Store
+	chooser Chooser
+	out     io.Writer // 进度和结果信息的输出位置
 }
 
 // New 创建新的 projj 客户端
@@ -24,17 +34,49 @@ func New() (*Client, error) {
 		return nil, fmt.Errorf("加载配置失败: %w", err)
 	}
 	
-	cch, err := cache.Load()
+	store, err := cache.Open()
+	if errors.Is(err, cache.ErrCorrupt) {
+		// 没有可用的备份时重新扫描基础目录重建缓存，损坏的文件已被移走
+		fmt.Fprintf(os.Stderr, "警告: %v\n", err)
+		if store, err = cache.Open(); err != nil {
+			return nil, fmt.Errorf("加载缓存失败: %w", err)
+		}
+		c := &Client{config: cfg, cache: store, out: os.Stdout}
+		if err := c.rebuildCache(); err != nil {
+			return nil, fmt.Errorf("重建缓存失败: %w", err)
+		}
+		return c, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("加载缓存失败: %w", err)
 	}
+	if notice := store.Notice(); notice != "" {
+		fmt.Fprintf(os.Stderr, "警告: %s\n", notice)
+	}
 	
 	return &Client{
 		config: cfg,
-		cache:  cch,
+		cache:  store,
+		out:    os.Stdout,
 	}, nil
 }
 
+// rebuildCache 扫描基础目录重建缓存
+func (c *Client) rebuildCache() error {
+	discovered, _, err := c.discoverRepos(c.config.GetBasePath(), SyncOptions{})
+	if err != nil {
+		return fmt.Errorf("扫描仓库失败: %w", err)
+	}
+	
+	added, _, err := c.cache.Sync(discovered)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Fprintf(os.Stderr, "已重新扫描 %s 重建缓存，共 %d 个仓库\n", c.config.GetBasePath(), added)
+	return nil
+}
+
 // Init 初始化 projj 环境
 func (c *Client) Init() error {
 	// 创建配置目录
@@ -54,128 +96,318 @@ func (c *Client) Init() error {
 		return fmt.Errorf("保存配置失败: %w", err)
 	}
 	
-	// 保存空缓存
-	if err := c.cache.Save(); err != nil {
+	// 创建缓存，已有缓存时保持不变
+	if err := c.cache.Put(); err != nil {
 		return fmt.Errorf("保存缓存失败: %w", err)
 	}
 	
-	fmt.Printf("Projj 初始化完成\n")
-	fmt.Printf("配置目录: %s\n", configDir)
-	fmt.Printf("基础目录: %s\n", basePath)
+	fmt.Fprintf(c.out, "Projj 初始化完成\n")
+	fmt.Fprintf(c.out, "配置目录: %s\n", configDir)
+	fmt.Fprintf(c.out, "基础目录: %s\n", basePath)
 	
 	return nil
 }
 
-// Add 添加仓库
-func (c *Client) Add(repoURL string) error {
+// Add 添加仓库，返回登记到缓存中的仓库
+func (c *Client) Add(repoURL string) (*cache.Repository, error) {
 	// 解析仓库 URL
 	repoInfo, err := git.ParseURL(repoURL, c.config.Alias)
 	if err != nil {
-		return fmt.Errorf("解析仓库 URL 失败: %w", err)
+		return nil, fmt.Errorf("解析仓库 URL 失败: %w", err)
 	}
 	
 	// 生成目标路径
 	targetPath := repoInfo.GetRepoPath(c.config.GetBasePath())
 	
-	// 检查是否已存在
-	if existingRepo := c.cache.GetByPath(targetPath); existingRepo != nil {
-		return fmt.Errorf("仓库已存在: %s", targetPath)
+	// 检查是否已存在，同一仓库可能以其他协议或路径登记过
+	existingRepo, err := c.cache.GetByPath(targetPath)
+	if err != nil {
+		return nil, fmt.Errorf("查询缓存失败: %w", err)
+	}
+	if existingRepo != nil {
+		return nil, fmt.Errorf("仓库已存在: %s", targetPath)
+	}
+	sameURL, err := c.cache.GetByURL(repoInfo.URL)
+	if err != nil {
+		return nil, fmt.Errorf("查询缓存失败: %w", err)
+	}
+	if len(sameURL) > 0 {
+		return nil, fmt.Errorf("仓库已存在: %s", sameURL[0].Path)
 	}
 	
-	fmt.Printf("正在克隆 %s 到 %s...\n", repoInfo.URL, targetPath)
+	// 执行 preadd hook，失败时中止添加
+	parentDir := filepath.Dir(targetPath)
+	if err := os.MkdirAll(parentDir, 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+	if err := c.runHook("preadd", parentDir, repoInfo.URL, targetPath); err != nil {
+		return nil, err
+	}
+	
+	fmt.Fprintf(c.out, "正在克隆 %s 到 %s...\n", repoInfo.URL, targetPath)
 	
 	// 克隆仓库
 	if err := git.Clone(repoInfo.URL, targetPath); err != nil {
-		return fmt.Errorf("克隆仓库失败: %w", err)
+		return nil, fmt.Errorf("克隆仓库失败: %w", err)
 	}
 	
-	// 添加到缓存
-	repo := cache.Repository{
-		Name:     repoInfo.Name,
-		URL:      repoInfo.URL,
-		Path:     targetPath,
-		Platform: repoInfo.Platform,
+	// 写入平台对应的 git 身份配置
+	if err := c.applyIdentity(targetPath, repoInfo.Platform); err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 设置 git 身份失败: %v\n", err)
 	}
-	c.cache.Add(repo)
 	
-	// 保存缓存
-	if err := c.cache.Save(); err != nil {
-		return fmt.Errorf("保存缓存失败: %w", err)
+	// 添加到缓存，添加即视为一次访问
+	now := time.Now()
+	repo := cache.Repository{
+		Name:          repoInfo.Name,
+		URL:           repoInfo.URL,
+		Path:          targetPath,
+		Platform:      repoInfo.Platform,
+		AddedAt:       now,
+		Visits:        1,
+		LastVisitedAt: now,
+	}
+	if err := c.cache.Put(repo); err != nil {
+		return nil, fmt.Errorf("保存缓存失败: %w", err)
 	}
 	
-	fmt.Printf("仓库添加成功: %s\n", targetPath)
+	fmt.Fprintf(c.out, "仓库添加成功: %s\n", targetPath)
+	
+	// 执行 postadd hook，此时仓库已添加，失败只给出警告
+	if err := c.runHook("postadd", targetPath, repoInfo.URL, targetPath); err != nil {
+		fmt.Fprintf(os.Stderr, "警告: %v\n", err)
+	}
 	
 	// 如果启用了 change_directory，输出特殊格式的路径信息供 shell 包装函数使用
 	if c.config.ChangeDirectory {
-		fmt.Printf("PROJJ_CHANGE_DIRECTORY=%s\n", targetPath)
+		fmt.Fprintf(c.out, "PROJJ_CHANGE_DIRECTORY=%s\n", targetPath)
 	}
 	
-	return nil
+	return &repo, nil
 }
 
-// Remove 移除仓库
-func (c *Client) Remove(query string, deleteFiles bool) error {
-	// 查找仓库
-	repos := c.cache.Find(query)
-	if len(repos) == 0 {
-		return fmt.Errorf("未找到匹配的仓库: %s", query)
+// runHook 执行配置中的指定 hook，未配置时直接返回
+func (c *Client) runHook(name, dir, repoURL, repoPath string) error {
+	command, ok := hook.Lookup(c.config.Hooks, name)
+	if !ok {
+		return nil
 	}
 	
-	if len(repos) > 1 {
-		fmt.Printf("找到多个匹配的仓库:\n")
-		for i, repo := range repos {
-			fmt.Printf("%d. %s (%s)\n", i+1, repo.Name, repo.Path)
-		}
-		return fmt.Errorf("请提供更具体的查询条件")
+	fmt.Fprintf(c.out, "执行 %s hook: %s\n", name, command)
+	err := hook.Run(command, hook.Options{
+		Dir:    dir,
+		Stdout: c.out,
+		Env: []string{
+			"PROJJ_HOOK_NAME=" + name,
+			"PROJJ_REPO_URL=" + repoURL,
+			"PROJJ_REPO_PATH=" + repoPath,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("执行 %s hook 失败: %w", name, err)
 	}
 	
-	repo := repos[0]
-	
-	// 从缓存中移除
-	if !c.cache.Remove(repo.Path) {
-		return fmt.Errorf("从缓存中移除仓库失败")
+	return nil
+}
+
+// Remove 移除仓库，返回被移除的仓库
+func (c *Client) Remove(query string, deleteFiles bool) (*cache.Repository, error) {
+	repo, err := c.Resolve(query)
+	if err != nil {
+		return nil, err
 	}
 	
 	// 删除文件（如果需要）
 	if deleteFiles {
 		if err := os.RemoveAll(repo.Path); err != nil {
-			return fmt.Errorf("删除仓库文件失败: %w", err)
+			return nil, fmt.Errorf("删除仓库文件失败: %w", err)
 		}
-		fmt.Printf("已删除仓库文件: %s\n", repo.Path)
+		fmt.Fprintf(c.out, "已删除仓库文件: %s\n", repo.Path)
 	}
 	
-	// 保存缓存
-	if err := c.cache.Save(); err != nil {
-		return fmt.Errorf("保存缓存失败: %w", err)
+	// 从缓存中移除并保存
+	removed, err := c.cache.Remove(repo.Path)
+	if err != nil {
+		return nil, fmt.Errorf("保存缓存失败: %w", err)
+	}
+	if !removed {
+		return nil, fmt.Errorf("从缓存中移除仓库失败")
 	}
 	
-	fmt.Printf("仓库移除成功: %s\n", repo.Name)
-	return nil
+	fmt.Fprintf(c.out, "仓库移除成功: %s\n", repo.Name)
+	return repo, nil
 }
 
-// Find 查找仓库
+// Find 查找仓库，结果按匹配程度排序
 func (c *Client) Find(query string) ([]cache.Repository, error) {
-	return c.cache.Find(query), nil
+	matches, err := c.Search(query)
+	if err != nil {
+		return nil, err
+	}
+	
+	repos := make([]cache.Repository, 0, len(matches))
+	for _, m := range matches {
+		repos = append(repos, m.Repo)
+	}
+	return repos, nil
+}
+
+// Search 查找仓库并返回匹配类型和分数，关键词按匹配程度排序，字段条件按原顺序过滤
+func (c *Client) Search(input string) ([]cache.Match, error) {
+	q, err := query.Compile(input)
+	if err != nil {
+		return nil, err
+	}
+	// 关键词按匹配程度排序，其余条件再过滤排序结果
+	if keyword, rest, ok := q.Keyword(); ok {
+		matches, err := c.cache.Find(keyword)
+		if err != nil || rest == nil {
+			return matches, err
+		}
+		var kept []cache.Match
+		for _, m := range matches {
+			ok, err := rest.Match(m.Repo, nil)
+			if err != nil {
+				return nil, fmt.Errorf("检查仓库 %s 失败: %w", m.Repo.Path, err)
+			}
+			if ok {
+				kept = append(kept, m)
+			}
+		}
+		return kept, nil
+	}
+	
+	repos, err := c.filter(q)
+	if err != nil {
+		return nil, err
+	}
+	
+	// 字段查询的结果不区分匹配程度
+	matches := make([]cache.Match, 0, len(repos))
+	for _, repo := range repos {
+		matches = append(matches, cache.Match{Repo: repo})
+	}
+	return matches, nil
+}
+
+// Query 返回满足查询的仓库，保持缓存中的顺序
+func (c *Client) Query(input string) ([]cache.Repository, error) {
+	q, err := query.Compile(input)
+	if err != nil {
+		return nil, err
+	}
+	return c.filter(q)
+}
+
+// filter 返回满足已编译查询的仓库
+func (c *Client) filter(q *query.Query) ([]cache.Repository, error) {
+	repos, err := c.cache.List()
+	if err != nil {
+		return nil, fmt.Errorf("查询缓存失败: %w", err)
+	}
+	return q.Filter(repos, nil)
 }
 
 // List 列出所有仓库
 func (c *Client) List() ([]cache.Repository, error) {
-	return c.cache.Repositories, nil
+	return c.cache.List()
+}
+
+// SyncOptions 表示同步选项
+type SyncOptions struct {
+	Nested  bool // 继续扫描仓库内部嵌套的仓库和子模块
+	Workers int  // 扫描并发数，为 0 时使用配置中的 scan_workers
+}
+
+// PathMismatch 表示磁盘路径与 origin URL 对应的标准路径不一致的仓库
+type PathMismatch struct {
+	Path     string `json:"path"`
+	Expected string `json:"expected"`
+	URL      string `json:"url"`
+}
+
+// SyncResult 表示同步结果
+type SyncResult struct {
+	Added      int            `json:"added"`
+	Removed    int            `json:"removed"`
+	Mismatched []PathMismatch `json:"mismatched"`
 }
 
 // Sync 同步缓存
-func (c *Client) Sync() error {
-	added, removed, err := c.cache.Sync()
+func (c *Client) Sync(opts SyncOptions) (*SyncResult, error) {
+	discovered, mismatched, err := c.discoverRepos(c.config.GetBasePath(), opts)
 	if err != nil {
-		return fmt.Errorf("同步缓存失败: %w", err)
+		return nil, fmt.Errorf("扫描仓库失败: %w", err)
 	}
 	
-	if err := c.cache.Save(); err != nil {
-		return fmt.Errorf("保存缓存失败: %w", err)
+	added, removed, err := c.cache.Sync(discovered)
+	if err != nil {
+		return nil, fmt.Errorf("同步缓存失败: %w", err)
 	}
 	
-	fmt.Printf("同步完成: 添加 %d 个，移除 %d 个仓库\n", added, removed)
-	return nil
+	for _, m := range mismatched {
+		fmt.Fprintf(c.out, "路径与 origin 不一致: %s (期望 %s)\n", m.Path, m.Expected)
+	}
+	
+	fmt.Fprintf(c.out, "同步完成: 添加 %d 个，移除 %d 个仓库\n", added, removed)
+	return &SyncResult{Added: added, Removed: removed, Mismatched: mismatched}, nil
+}
+
+// discoverRepos 扫描目录下的所有 Git 仓库，并找出路径与 origin URL 不一致的仓库
+func (c *Client) discoverRepos(root string, opts SyncOptions) ([]cache.Repository, []PathMismatch, error) {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	
+	found, err := c.scanRepos(root, opts.Workers, opts.Nested)
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	var repos []cache.Repository
+	var mismatched []PathMismatch
+	for _, found := range found {
+		if repo, mismatch := c.inspectRepo(found); repo != nil {
+			repos = append(repos, *repo)
+			if mismatch != nil {
+				mismatched = append(mismatched, *mismatch)
+			}
+		}
+	}
+	
+	return repos, mismatched, nil
+}
+
+// inspectRepo 根据 origin URL 解析仓库信息，无法识别时返回 nil
+func (c *Client) inspectRepo(found scan.Repo) (*cache.Repository, *PathMismatch) {
+	if found.Err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 跳过没有 origin 的仓库: %s\n", found.Path)
+		return nil, nil
+	}
+	
+	repoInfo, err := git.ParseURL(found.RemoteURL, c.config.Alias)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 无法解析 %s 的 URL %s: %v\n", found.Path, found.RemoteURL, err)
+		return nil, nil
+	}
+	
+	repo := &cache.Repository{
+		Name:     repoInfo.Name,
+		URL:      repoInfo.URL,
+		Path:     found.Path,
+		Platform: repoInfo.Platform,
+	}
+	
+	expected := repoInfo.GetRepoPath(c.config.GetBasePath())
+	if filepath.Clean(found.Path) != filepath.Clean(expected) {
+		return repo, &PathMismatch{Path: found.Path, Expected: expected, URL: found.RemoteURL}
+	}
+	return repo, nil
+}
+
+// SetOutput 设置进度和结果信息的输出位置，默认为标准输出
+func (c *Client) SetOutput(w io.Writer) {
+	c.out = w
 }
 
 // GetConfig 获取配置
@@ -188,82 +420,325 @@ func (c *Client) SaveConfig() error {
 	return c.config.Save()
 }
 
+// 导入时目标路径已存在的处理方式
+const (
+	ConflictSkip   = "skip"   // 跳过该仓库
+	ConflictRename = "rename" // 在目标路径后追加序号
+	ConflictFail   = "fail"   // 中止导入
+)
+
+// 导入时对单个仓库的处理结果
+const (
+	ImportActionRegister = "register" // 已在标准目录中，仅登记
+	ImportActionMove     = "move"
+	ImportActionCopy     = "copy"
+	ImportActionClone    = "clone" // 从缓存文件导入时目录不存在，重新克隆
+	ImportActionSkip     = "skip"
+	ImportActionFail     = "fail"
+)
+
+// ImportOptions 表示导入选项
+type ImportOptions struct {
+	DryRun   bool   // 只打印计划，不做任何修改
+	Copy     bool   // 复制而不是移动，保留原目录
+	Conflict string // 目标已存在时的处理方式，默认为 skip
+	Workers  int    // 扫描并发数，为 0 时使用配置中的 scan_workers
+}
+
+// ImportEntry 表示导入时单个仓库的处理结果
+type ImportEntry struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+}
+
+// ImportResult 表示导入结果
+type ImportResult struct {
+	Entries []ImportEntry `json:"entries"`
+}
+
+// Count 统计指定处理结果的仓库数量
+func (r *ImportResult) Count(actions ...string) int {
+	var count int
+	for _, entry := range r.Entries {
+		for _, action := range actions {
+			if entry.Action == action {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
 // Import 导入现有仓库
-func (c *Client) Import(sourcePath string) error {
+func (c *Client) Import(sourcePath string, opts ImportOptions) (*ImportResult, error) {
 	// 检查源路径是否存在
 	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
-		return fmt.Errorf("源路径不存在: %s", sourcePath)
+		return nil, fmt.Errorf("源路径不存在: %s", sourcePath)
 	}
 	
-	var imported int
+	switch opts.Conflict {
+	case "":
+		opts.Conflict = ConflictSkip
+	case ConflictSkip, ConflictRename, ConflictFail:
+	default:
+		return nil, fmt.Errorf("未知的冲突处理方式: %s", opts.Conflict)
+	}
 	
-	// 遍历源路径下的所有目录
-	err := filepath.Walk(sourcePath, func(path string, info os.FileInfo, err error) error {
+	// 扫描源路径下的所有仓库，再依次移动，避免边遍历边移动
+	found, err := c.scanRepos(sourcePath, opts.Workers, false)
+	if err != nil {
+		return nil, fmt.Errorf("扫描仓库失败: %w", err)
+	}
+	
+	result := &ImportResult{}
+	var registered []cache.Repository
+	for _, repo := range found {
+		entry, imported, err := c.importRepo(repo, opts)
 		if err != nil {
-			return err
+			// 已移动的仓库仍需登记，登记失败时一并返回
+			err = fmt.Errorf("导入仓库失败: %w", err)
+			if regErr := c.registerRepos(registered); regErr != nil {
+				err = errors.Join(err, fmt.Errorf("保存缓存失败: %w", regErr))
+			}
+			return result, err
+		}
+		if entry != nil {
+			result.Entries = append(result.Entries, *entry)
+		}
+		if imported != nil {
+			registered = append(registered, *imported)
+		}
+	}
+	
+	imported := result.Count(ImportActionRegister, ImportActionMove, ImportActionCopy)
+	skipped := result.Count(ImportActionSkip)
+	if opts.DryRun {
+		fmt.Fprintf(c.out, "预览完成: 将导入 %d 个仓库，跳过 %d 个\n", imported, skipped)
+		return result, nil
+	}
+	
+	// 保存缓存
+	if err := c.registerRepos(registered); err != nil {
+		return result, fmt.Errorf("保存缓存失败: %w", err)
+	}
+	
+	fmt.Fprintf(c.out, "导入完成: 共导入 %d 个仓库，跳过 %d 个\n", imported, skipped)
+	return result, nil
+}
+
+// registerRepos 将仓库登记到缓存
+func (c *Client) registerRepos(repos []cache.Repository) error {
+	if len(repos) == 0 {
+		return nil
+	}
+	return c.cache.Put(repos...)
+}
+
+// importRepo 导入单个仓库，返回处理结果和需要登记的仓库，无法识别的仓库返回 nil
+func (c *Client) importRepo(found scan.Repo, opts ImportOptions) (*ImportEntry, *cache.Repository, error) {
+	path, remoteURL := found.Path, found.RemoteURL
+	if found.Err != nil {
+		fmt.Fprintf(c.out, "警告: 无法获取 %s 的远程 URL: %v\n", path, found.Err)
+		return nil, nil, nil
+	}
+	
+	// 解析仓库信息
+	repoInfo, err := git.ParseURL(remoteURL, c.config.Alias)
+	if err != nil {
+		fmt.Fprintf(c.out, "警告: 无法解析 %s 的 URL %s: %v\n", path, remoteURL, err)
+		return nil, nil, nil
+	}
+	
+	// 生成目标路径
+	targetPath := repoInfo.GetRepoPath(c.config.GetBasePath())
+	entry := &ImportEntry{Source: path, Target: targetPath, Action: ImportActionRegister}
+	
+	// 如果目标路径与当前路径不同，移动或复制仓库
+	if filepath.Clean(path) != filepath.Clean(targetPath) {
+		entry.Action = ImportActionMove
+		if opts.Copy {
+			entry.Action = ImportActionCopy
 		}
 		
-		// 检查是否是 Git 仓库
-		if info.IsDir() && git.IsGitRepository(path) {
-			// 获取远程 URL
-			remoteURL, err := git.GetRemoteURL(path)
-			if err != nil {
-				fmt.Printf("警告: 无法获取 %s 的远程 URL: %v\n", path, err)
-				return nil
-			}
-			
-			// 解析仓库信息
-			repoInfo, err := git.ParseURL(remoteURL, c.config.Alias)
-			if err != nil {
-				fmt.Printf("警告: 无法解析 %s 的 URL %s: %v\n", path, remoteURL, err)
-				return nil
-			}
-			
-			// 生成目标路径
-			targetPath := repoInfo.GetRepoPath(c.config.GetBasePath())
-			
-			// 如果目标路径与当前路径不同，移动仓库
-			if path != targetPath {
-				if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-					return fmt.Errorf("创建目标目录失败: %w", err)
-				}
-				
-				if err := os.Rename(path, targetPath); err != nil {
-					return fmt.Errorf("移动仓库失败: %w", err)
-				}
-				
-				fmt.Printf("移动仓库: %s -> %s\n", path, targetPath)
-				path = targetPath
+		if fsutil.Exists(targetPath) {
+			switch opts.Conflict {
+			case ConflictFail:
+				return nil, nil, fmt.Errorf("目标路径已存在: %s", targetPath)
+			case ConflictRename:
+				entry.Target = nextAvailablePath(targetPath)
+			default:
+				entry.Action = ImportActionSkip
+				entry.Reason = "目标路径已存在"
+				fmt.Fprintf(c.out, "跳过仓库: %s (目标路径已存在: %s)\n", path, targetPath)
+				return entry, nil, nil
 			}
-			
-			// 添加到缓存
-			repo := cache.Repository{
-				Name:     repoInfo.Name,
-				URL:      repoInfo.URL,
-				Path:     path,
-				Platform: repoInfo.Platform,
-			}
-			c.cache.Add(repo)
-			imported++
-			
-			// 跳过子目录
-			return filepath.SkipDir
 		}
 		
+		if err := c.transferRepo(entry, opts); err != nil {
+			return nil, nil, err
+		}
+	}
+	
+	if opts.DryRun {
+		return entry, nil, nil
+	}
+	
+	repo := &cache.Repository{
+		Name:     repoInfo.Name,
+		URL:      repoInfo.URL,
+		Path:     entry.Target,
+		Platform: repoInfo.Platform,
+	}
+	
+	return entry, repo, nil
+}
+
+// transferRepo 按导入选项移动或复制仓库到目标路径
+func (c *Client) transferRepo(entry *ImportEntry, opts ImportOptions) error {
+	verb := "移动"
+	if entry.Action == ImportActionCopy {
+		verb = "复制"
+	}
+	
+	if opts.DryRun {
+		fmt.Fprintf(c.out, "计划%s: %s -> %s\n", verb, entry.Source, entry.Target)
 		return nil
+	}
+	
+	if err := os.MkdirAll(filepath.Dir(entry.Target), 0755); err != nil {
+		return fmt.Errorf("创建目标目录失败: %w", err)
+	}
+	
+	if entry.Action == ImportActionCopy {
+		if err := fsutil.Copy(entry.Source, entry.Target); err != nil {
+			return fmt.Errorf("复制仓库失败: %w", err)
+		}
+	} else if err := fsutil.Move(entry.Source, entry.Target); err != nil {
+		return fmt.Errorf("移动仓库失败: %w", err)
+	}
+	
+	fmt.Fprintf(c.out, "%s仓库: %s -> %s\n", verb, entry.Source, entry.Target)
+	return nil
+}
+
+// ImportCache 从缓存文件导入仓库，支持原版 projj 的 cache.json
+//
+// 缓存中的目录存在时直接登记，不存在时按 repo URL 克隆到标准目录结构。
+// 缓存中的仓库不会移动，不支持 opts.Copy 和 opts.Conflict。
+func (c *Client) ImportCache(cachePath string, opts ImportOptions) (*ImportResult, error) {
+	if opts.Copy || (opts.Conflict != "" && opts.Conflict != ConflictSkip) {
+		return nil, fmt.Errorf("从缓存导入时不支持复制和冲突处理选项")
+	}
+	
+	source, err := cache.LoadFile(cachePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取缓存文件失败: %w", err)
+	}
+	
+	repos := make([]cache.Repository, len(source.Repositories))
+	copy(repos, source.Repositories)
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].Path < repos[j].Path
 	})
 	
+	result := &ImportResult{}
+	var imported []cache.Repository
+	for _, repo := range repos {
+		entry, repo := c.importCacheEntry(repo, opts)
+		result.Entries = append(result.Entries, entry)
+		if repo != nil {
+			imported = append(imported, *repo)
+		}
+	}
+	
+	registered := result.Count(ImportActionRegister)
+	cloned := result.Count(ImportActionClone)
+	failed := result.Count(ImportActionFail)
+	if opts.DryRun {
+		fmt.Fprintf(c.out, "预览完成: 将登记 %d 个、克隆 %d 个仓库\n", registered, cloned)
+		return result, nil
+	}
+	
+	if err := c.registerRepos(imported); err != nil {
+		return result, fmt.Errorf("保存缓存失败: %w", err)
+	}
+	
+	fmt.Fprintf(c.out, "导入完成: 登记 %d 个、克隆 %d 个、失败 %d 个仓库\n", registered, cloned, failed)
+	if failed > 0 {
+		return result, fmt.Errorf("%d 个仓库导入失败", failed)
+	}
+	return result, nil
+}
+
+// importCacheEntry 导入缓存文件中的单个仓库，返回处理结果和需要登记的仓库
+func (c *Client) importCacheEntry(repo cache.Repository, opts ImportOptions) (ImportEntry, *cache.Repository) {
+	entry := ImportEntry{Source: repo.Path, Target: repo.Path, Action: ImportActionRegister}
+	
+	repoInfo, err := git.ParseURL(repo.URL, c.config.Alias)
 	if err != nil {
-		return fmt.Errorf("导入仓库失败: %w", err)
+		entry.Action = ImportActionFail
+		entry.Reason = fmt.Sprintf("无法解析 URL %s", repo.URL)
+		fmt.Fprintf(c.out, "警告: 无法解析 %s 的 URL %s: %v\n", repo.Path, repo.URL, err)
+		return entry, nil
 	}
 	
-	// 保存缓存
-	if err := c.cache.Save(); err != nil {
-		return fmt.Errorf("保存缓存失败: %w", err)
+	// 原路径不存在时使用标准目录，标准目录也不存在时需要克隆
+	if !git.IsGitRepository(repo.Path) {
+		entry.Target = repoInfo.GetRepoPath(c.config.GetBasePath())
+		if !git.IsGitRepository(entry.Target) {
+			if fsutil.Exists(entry.Target) {
+				entry.Action = ImportActionFail
+				entry.Reason = "目标路径已存在且不是 Git 仓库"
+				fmt.Fprintf(c.out, "警告: 目标路径已存在且不是 Git 仓库: %s\n", entry.Target)
+				return entry, nil
+			}
+			entry.Action = ImportActionClone
+		}
 	}
 	
-	fmt.Printf("导入完成: 共导入 %d 个仓库\n", imported)
-	return nil
+	if opts.DryRun {
+		if entry.Action == ImportActionClone {
+			fmt.Fprintf(c.out, "计划克隆: %s -> %s\n", repoInfo.URL, entry.Target)
+		} else {
+			fmt.Fprintf(c.out, "计划登记: %s\n", entry.Target)
+		}
+		return entry, nil
+	}
+	
+	if entry.Action == ImportActionClone {
+		fmt.Fprintf(c.out, "正在克隆 %s 到 %s...\n", repoInfo.URL, entry.Target)
+		if err := git.Clone(repoInfo.URL, entry.Target); err != nil {
+			entry.Action = ImportActionFail
+			entry.Reason = err.Error()
+			fmt.Fprintf(os.Stderr, "错误: 克隆 %s 失败: %v\n", repoInfo.URL, err)
+			return entry, nil
+		}
+		if err := c.applyIdentity(entry.Target, repoInfo.Platform); err != nil {
+			fmt.Fprintf(os.Stderr, "警告: 设置 git 身份失败: %v\n", err)
+		}
+	}
+	
+	// 保留原缓存中的添加时间
+	return entry, &cache.Repository{
+		Name:     repoInfo.Name,
+		URL:      repo.URL,
+		Path:     entry.Target,
+		Platform: repoInfo.Platform,
+		AddedAt:  repo.AddedAt,
+	}
+}
+
+// nextAvailablePath 返回追加序号后第一个不存在的路径，如 repo-1、repo-2
+func nextAvailablePath(path string) string {
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", path, i)
+		if !fsutil.Exists(candidate) {
+			return candidate
+		}
+	}
 }
 
 // FormatRepoList 格式化仓库列表输出
@@ -275,10 +750,29 @@ func FormatRepoList(repos []cache.Repository, showDetails bool) string {
 	var lines []string
 	for _, repo := range repos {
 		if showDetails {
-			lines = append(lines, fmt.Sprintf("%s\n  URL: %s\n  Path: %s\n  Platform: %s\n  Added: %s",
-				repo.Name, repo.URL, repo.Path, repo.Platform, repo.AddedAt.Format("2006-01-02 15:04:05")))
+			// projj-node 格式的缓存没有添加时间
+			added := "未知"
+			if !repo.AddedAt.IsZero() {
+				added = repo.AddedAt.Format("2006-01-02 15:04:05")
+			}
+			line := fmt.Sprintf("%s\n  URL: %s\n  Path: %s\n  Platform: %s\n  Added: %s",
+				repo.Name, repo.URL, repo.Path, repo.Platform, added)
+			if repo.Visits > 0 {
+				line += fmt.Sprintf("\n  Visits: %d (last %s)", repo.Visits, repo.LastVisitedAt.Format("2006-01-02 15:04:05"))
+			}
+			if len(repo.Tags) > 0 {
+				line += fmt.Sprintf("\n  Tags: %s", strings.Join(repo.Tags, ", "))
+			}
+			if repo.Description != "" {
+				line += fmt.Sprintf("\n  Description: %s", repo.Description)
+			}
+			lines = append(lines, line)
 		} else {
-			lines = append(lines, fmt.Sprintf("%s (%s)", repo.Name, repo.Path))
+			line := fmt.Sprintf("%s (%s)", repo.Name, repo.Path)
+			if len(repo.Tags) > 0 {
+				line += fmt.Sprintf(" [%s]", strings.Join(repo.Tags, ", "))
+			}
+			lines = append(lines, line)
 		}
 	}
 	
@@ -1,14 +1,23 @@
 package projj
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/campaign"
 	"github.com/atian25/projj-go/internal/config"
+	"github.com/atian25/projj-go/internal/git"
 )
 
 func setupTestEnv(t *testing.T) (string, func()) {
@@ -194,8 +203,7 @@ func TestList(t *testing.T) {
 		Platform: "github.com",
 		AddedAt:  time.Now(),
 	}
-	client.cache.Add(repo)
-	client.cache.Save()
+	client.cache.Put(repo)
 	
 	// 重新加载并测试
 	repos, err = client.List()
@@ -244,10 +252,7 @@ func TestFind(t *testing.T) {
 		},
 	}
 	
-	for _, repo := range repos {
-		client.cache.Add(repo)
-	}
-	client.cache.Save()
+	client.cache.Put(repos...)
 	
 	// 测试精确匹配
 	results, err := client.Find("hello-world")
@@ -288,6 +293,29 @@ func TestFind(t *testing.T) {
 	if len(results) != 2 {
 		t.Errorf("Expected 2 results for empty query, got %d", len(results))
 	}
+	
+	// 测试字段查询
+	results, err = client.Find("host:gitlab.com owner:user")
+	if err != nil {
+		t.Fatalf("Find() failed: %v", err)
+	}
+	
+	if len(results) != 1 || results[0].Name != "my-project" {
+		t.Errorf("Expected my-project for field query, got %v", results)
+	}
+	
+	listed, err := client.Query("NOT host:gitlab.com")
+	if err != nil {
+		t.Fatalf("Query() failed: %v", err)
+	}
+	
+	if len(listed) != 1 || listed[0].Name != "hello-world" {
+		t.Errorf("Expected hello-world for negated query, got %v", listed)
+	}
+	
+	if _, err := client.Query("color:red"); err == nil {
+		t.Error("Expected error for unknown field")
+	}
 }
 
 func TestRemove(t *testing.T) {
@@ -312,14 +340,21 @@ func TestRemove(t *testing.T) {
 		Platform: "github.com",
 		AddedAt:  time.Now(),
 	}
-	client.cache.Add(repo)
-	client.cache.Save()
+	client.cache.Put(repo)
 	
-	// 测试移除存在的仓库
-	err = client.Remove("test-repo", false)
+	// 测试移除存在的仓库，输出写入 SetOutput 指定的位置
+	var out bytes.Buffer
+	client.SetOutput(&out)
+	removed, err := client.Remove("test-repo", false)
 	if err != nil {
 		t.Fatalf("Remove() failed: %v", err)
 	}
+	if removed.Path != repo.Path {
+		t.Errorf("Expected removed repo %s, got %s", repo.Path, removed.Path)
+	}
+	if !strings.Contains(out.String(), "仓库移除成功") {
+		t.Errorf("Expected message written to output, got %q", out.String())
+	}
 	
 	// 验证仓库已从缓存中移除
 	repos, err := client.List()
@@ -332,7 +367,7 @@ func TestRemove(t *testing.T) {
 	}
 	
 	// 测试移除不存在的仓库
-	err = client.Remove("nonexistent", false)
+	_, err = client.Remove("nonexistent", false)
 	if err == nil {
 		t.Error("Should fail when removing nonexistent repository")
 	}
@@ -368,7 +403,7 @@ func TestSync(t *testing.T) {
 	}
 	
 	// 执行同步
-	err = client.Sync()
+	_, err = client.Sync(SyncOptions{})
 	if err != nil {
 		t.Fatalf("Sync() failed: %v", err)
 	}
@@ -498,7 +533,7 @@ func TestImport(t *testing.T) {
 	}
 	
 	// 执行导入
-	err = client.Import(sourceDir)
+	_, err = client.Import(sourceDir, ImportOptions{})
 	if err != nil {
 		t.Fatalf("Import() failed: %v", err)
 	}
@@ -514,4 +549,1232 @@ func TestImport(t *testing.T) {
 		t.Logf("Warning: Expected 0 repositories after failed import, got %d", len(repos))
 		// 这不是错误，因为我们的模拟 git 配置可能不完整
 	}
+}
+
+func TestAddPreaddHookAbort(t *testing.T) {
+	tempDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	
+	// 使用临时基础目录并配置失败的 preadd hook
+	client.GetConfig().Base = filepath.Join(tempDir, "base")
+	client.GetConfig().Hooks = map[string]string{
+		"preadd": "exit 1",
+	}
+	
+	_, err = client.Add("user/test-repo")
+	if err == nil {
+		t.Fatal("Add() should fail when preadd hook fails")
+	}
+	
+	if !strings.Contains(err.Error(), "preadd") {
+		t.Errorf("Expected error to mention preadd, got: %v", err)
+	}
+	
+	// 仓库不应被克隆或加入缓存
+	targetPath := filepath.Join(tempDir, "base", "github.com", "user", "test-repo")
+	if _, err := os.Stat(targetPath); !os.IsNotExist(err) {
+		t.Error("Repository should not be cloned when preadd hook fails")
+	}
+	
+	repos, _ := client.List()
+	if len(repos) != 0 {
+		t.Errorf("Expected 0 repositories after aborted add, got %d", len(repos))
+	}
+}
+
+func TestRunAndRunAll(t *testing.T) {
+	tempDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	
+	// 创建两个仓库目录，其中一个稍后会被删除
+	repo1 := filepath.Join(tempDir, "base", "github.com", "user", "repo1")
+	repo2 := filepath.Join(tempDir, "base", "github.com", "user", "repo2")
+	for _, dir := range []string{repo1, repo2} {
+		if err := os.MkdirAll(filepath.Join(dir, "src"), 0755); err != nil {
+			t.Fatalf("Failed to create repo dir: %v", err)
+		}
+		client.cache.Put(cache.Repository{Name: filepath.Base(dir), Path: dir})
+	}
+	
+	client.GetConfig().Hooks = map[string]string{
+		"touch": "touch hook-ran",
+	}
+	
+	// run: 从子目录解析所属仓库
+	if err := client.Run("touch", filepath.Join(repo1, "src")); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repo1, "hook-ran")); err != nil {
+		t.Error("Hook should run in the repository root")
+	}
+	
+	// run: 未配置的 hook
+	if err := client.Run("missing", repo1); err == nil {
+		t.Error("Run() should fail for unknown hook")
+	}
+	
+	// run: 不在管理仓库中的目录
+	if err := client.Run("touch", tempDir); err == nil {
+		t.Error("Run() should fail outside managed repositories")
+	}
+	
+	// runall: 一个仓库缺失时继续执行并汇总
+	os.RemoveAll(repo1)
+	result, err := client.RunAll("touch", "")
+	if err == nil {
+		t.Error("RunAll() should report failures")
+	}
+	if result == nil || len(result.Succeeded) != 1 || len(result.Failed) != 1 {
+		t.Fatalf("Expected 1 success and 1 failure, got %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(repo2, "hook-ran")); err != nil {
+		t.Error("Hook should run in repo2")
+	}
+}
+
+func TestAuditAndFixIdentity(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	tempDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	
+	// 创建真实的 Git 仓库
+	repoPath := filepath.Join(tempDir, "base", "gitlab.example.com", "team", "service")
+	if err := os.MkdirAll(repoPath, 0755); err != nil {
+		t.Fatalf("Failed to create repo dir: %v", err)
+	}
+	cmd := exec.Command("git", "init")
+	cmd.Dir = repoPath
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to init git repo: %v", err)
+	}
+	
+	client.cache.Put(cache.Repository{
+		Name:     "service",
+		URL:      "git@gitlab.example.com:team/service.git",
+		Path:     repoPath,
+		Platform: "gitlab.example.com",
+	})
+	client.GetConfig().PostAdd = map[string]map[string]string{
+		"gitlab.example.com": {
+			"user.name": "Work Name",
+			"email":     "work@example.com",
+		},
+	}
+	
+	issues, err := client.AuditIdentity("")
+	if err != nil {
+		t.Fatalf("AuditIdentity() failed: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %d", len(issues))
+	}
+	
+	// 部分仓库修正失败时返回已修正和未修正的项
+	lockPath := filepath.Join(repoPath, ".git", "config.lock")
+	if err := os.WriteFile(lockPath, nil, 0644); err != nil {
+		t.Fatalf("Failed to create config lock: %v", err)
+	}
+	fixed, remaining, err := client.FixIdentity("")
+	if err == nil || len(fixed) != 0 || len(remaining) != 2 {
+		t.Errorf("Expected failure with 2 remaining issues, got %d fixed, %d remaining (%v)", len(fixed), len(remaining), err)
+	}
+	os.Remove(lockPath)
+	
+	fixed, remaining, err = client.FixIdentity("")
+	if err != nil || len(fixed) != 2 || len(remaining) != 0 {
+		t.Fatalf("FixIdentity() returned %d fixed, %d remaining (%v)", len(fixed), len(remaining), err)
+	}
+	
+	issues, err = client.AuditIdentity("")
+	if err != nil {
+		t.Fatalf("AuditIdentity() failed: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Expected 0 issues after fix, got %d", len(issues))
+	}
+	
+	email, _ := git.GetLocalConfig(repoPath, "user.email")
+	if email != "work@example.com" {
+		t.Errorf("Expected user.email 'work@example.com', got '%s'", email)
+	}
+}
+
+// initGitRepo 创建带有 remote origin 的 Git 仓库
+func initGitRepo(t *testing.T, dir, remoteURL string) {
+	t.Helper()
+	
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create repo dir: %v", err)
+	}
+	for _, args := range [][]string{
+		{"init"},
+		{"remote", "add", "origin", remoteURL},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Failed to run git %v: %v", args, err)
+		}
+	}
+}
+
+func TestImportWithOptions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	tempDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	basePath := filepath.Join(tempDir, "base")
+	client.GetConfig().Base = basePath
+	
+	sourceDir := filepath.Join(tempDir, "source")
+	initGitRepo(t, filepath.Join(sourceDir, "foo"), "https://github.com/user/foo.git")
+	initGitRepo(t, filepath.Join(sourceDir, "bar"), "https://github.com/user/bar.git")
+	
+	// bar 的目标路径已存在
+	barTarget := filepath.Join(basePath, "github.com", "user", "bar")
+	if err := os.MkdirAll(barTarget, 0755); err != nil {
+		t.Fatalf("Failed to create target dir: %v", err)
+	}
+	fooTarget := filepath.Join(basePath, "github.com", "user", "foo")
+	
+	// dry-run 不做任何修改
+	result, err := client.Import(sourceDir, ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Import() dry-run failed: %v", err)
+	}
+	if result.Count(ImportActionMove) != 1 || result.Count(ImportActionSkip) != 1 {
+		t.Errorf("Expected 1 planned move and 1 skip, got %+v", result.Entries)
+	}
+	if _, err := os.Stat(fooTarget); !os.IsNotExist(err) {
+		t.Error("Dry-run should not move repositories")
+	}
+	if repos, _ := client.List(); len(repos) != 0 {
+		t.Errorf("Dry-run should not register repositories, got %d", len(repos))
+	}
+	
+	// fail 模式遇到冲突时中止
+	if _, err := client.Import(sourceDir, ImportOptions{Conflict: ConflictFail, DryRun: true}); err == nil {
+		t.Error("Import() should fail on conflict in fail mode")
+	}
+	
+	// copy + rename: 原目录保留，冲突的仓库导入到追加序号的路径
+	result, err = client.Import(sourceDir, ImportOptions{Copy: true, Conflict: ConflictRename})
+	if err != nil {
+		t.Fatalf("Import() failed: %v", err)
+	}
+	if result.Count(ImportActionCopy) != 2 {
+		t.Errorf("Expected 2 copies, got %+v", result.Entries)
+	}
+	if !git.IsGitRepository(fooTarget) {
+		t.Error("foo should be copied to the standard path")
+	}
+	if !git.IsGitRepository(barTarget + "-1") {
+		t.Error("bar should be copied to the renamed path")
+	}
+	if !git.IsGitRepository(filepath.Join(sourceDir, "foo")) {
+		t.Error("Copy mode should keep the original repository")
+	}
+	
+	repos, _ := client.List()
+	if len(repos) != 2 {
+		t.Errorf("Expected 2 repositories after import, got %d", len(repos))
+	}
+}
+
+func TestImportCache(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	tempDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	basePath := filepath.Join(tempDir, "base")
+	client.GetConfig().Base = basePath
+	
+	// 一个仓库在原路径存在，另一个不存在
+	existing := filepath.Join(tempDir, "colleague", "github.com", "user", "existing")
+	initGitRepo(t, existing, "git@github.com:user/existing.git")
+	missing := filepath.Join(tempDir, "colleague", "github.com", "user", "missing")
+	
+	// 原版 projj 的缓存格式
+	nodeCache := fmt.Sprintf(`{
+  %q: {"repo": "git@github.com:user/existing.git"},
+  %q: {"repo": "git@github.com:user/missing.git"},
+  "version": "v1"
+}`, existing, missing)
+	cacheFile := filepath.Join(tempDir, "node-cache.json")
+	if err := os.WriteFile(cacheFile, []byte(nodeCache), 0644); err != nil {
+		t.Fatalf("Failed to write cache file: %v", err)
+	}
+	
+	result, err := client.ImportCache(cacheFile, ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportCache() failed: %v", err)
+	}
+	if len(result.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(result.Entries))
+	}
+	
+	for _, entry := range result.Entries {
+		switch entry.Source {
+		case existing:
+			if entry.Action != ImportActionRegister || entry.Target != existing {
+				t.Errorf("Existing checkout should be registered in place, got %+v", entry)
+			}
+		case missing:
+			expected := filepath.Join(basePath, "github.com", "user", "missing")
+			if entry.Action != ImportActionClone || entry.Target != expected {
+				t.Errorf("Missing checkout should be cloned to %s, got %+v", expected, entry)
+			}
+		default:
+			t.Errorf("Unexpected entry: %+v", entry)
+		}
+	}
+	
+	if repos, _ := client.List(); len(repos) != 0 {
+		t.Errorf("Dry-run should not register repositories, got %d", len(repos))
+	}
+	
+	// 缓存导入不会移动仓库，复制和冲突处理选项无效
+	if _, err := client.ImportCache(cacheFile, ImportOptions{DryRun: true, Copy: true}); err == nil {
+		t.Error("ImportCache() should reject the copy option")
+	}
+	if _, err := client.ImportCache(cacheFile, ImportOptions{DryRun: true, Conflict: ConflictRename}); err == nil {
+		t.Error("ImportCache() should reject the conflict option")
+	}
+}
+
+func TestSyncDiscovery(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	tempDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	basePath := filepath.Join(tempDir, "base")
+	client.GetConfig().Base = basePath
+	
+	standard := filepath.Join(basePath, "github.com", "user", "standard")
+	misplaced := filepath.Join(basePath, "misc", "other")
+	nested := filepath.Join(standard, "libs", "nested")
+	initGitRepo(t, standard, "git@github.com:user/standard.git")
+	initGitRepo(t, misplaced, "git@github.com:user/other.git")
+	initGitRepo(t, nested, "git@github.com:user/nested.git")
+	
+	result, err := client.Sync(SyncOptions{})
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	
+	if result.Added != 2 {
+		t.Errorf("Expected 2 repositories added without nested, got %d", result.Added)
+	}
+	
+	if len(result.Mismatched) != 1 || result.Mismatched[0].Path != misplaced {
+		t.Errorf("Expected %s to be flagged as mismatched, got %+v", misplaced, result.Mismatched)
+	}
+	
+	// 开启 nested 后发现嵌套仓库
+	result, err = client.Sync(SyncOptions{Nested: true})
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	
+	if result.Added != 1 {
+		t.Errorf("Expected nested repository to be added, got %d", result.Added)
+	}
+	
+	repos, _ := client.List()
+	if len(repos) != 3 {
+		t.Errorf("Expected 3 repositories after sync, got %d", len(repos))
+	}
+}
+
+func TestNewRebuildsCorruptCache(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	tempDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	basePath := filepath.Join(tempDir, "base")
+	cfg := config.DefaultConfig()
+	cfg.Base = basePath
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	initGitRepo(t, filepath.Join(basePath, "github.com", "user", "repo"), "git@github.com:user/repo.git")
+	
+	// 模拟写入中断留下的不完整缓存文件
+	if err := os.WriteFile(cache.GetCachePath(), []byte(`{"repositories": [{"name": "re`), 0644); err != nil {
+		t.Fatalf("Failed to write cache: %v", err)
+	}
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() should rebuild corrupt cache: %v", err)
+	}
+	
+	repos, _ := client.List()
+	if len(repos) != 1 || repos[0].URL != "git@github.com:user/repo.git" {
+		t.Errorf("Expected cache rebuilt from base directory, got %+v", repos)
+	}
+	
+	if _, err := os.Stat(cache.GetCachePath() + ".corrupt"); err != nil {
+		t.Errorf("Corrupt cache should be kept: %v", err)
+	}
+}
+
+func TestNewRejectsNewerCache(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	// 更新版本的 projj 写入的缓存，重建会丢失标签、描述和访问记录
+	newer := []byte(`{"version": 99, "repositories": [{"name": "repo", "path": "/path/to/repo", "tags": ["work"]}]}`)
+	if err := os.WriteFile(cache.GetCachePath(), newer, 0644); err != nil {
+		t.Fatalf("Failed to write cache: %v", err)
+	}
+	
+	if _, err := New(); !errors.Is(err, cache.ErrUnsupportedVersion) {
+		t.Fatalf("Expected ErrUnsupportedVersion, got %v", err)
+	}
+	if data, _ := os.ReadFile(cache.GetCachePath()); string(data) != string(newer) {
+		t.Error("Newer cache should be left untouched")
+	}
+}
+
+func TestJumpAndRecent(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	
+	client.cache.Put(
+		cache.Repository{Name: "api", Path: "/projj/github.com/team/api", URL: "git@github.com:team/api.git"},
+		cache.Repository{Name: "api-gateway", Path: "/projj/github.com/team/api-gateway", URL: "git@github.com:team/api-gateway.git"},
+		cache.Repository{Name: "web", Path: "/projj/github.com/team/web", URL: "git@github.com:team/web.git"},
+		cache.Repository{Name: "awesome-pipeline-infra", Path: "/projj/github.com/team/awesome-pipeline-infra", URL: "git@github.com:team/awesome-pipeline-infra.git"},
+	)
+	
+	// 没有访问记录时空查询无法跳转
+	if _, err := client.Jump(""); err == nil {
+		t.Error("Jump(\"\") should fail without visits")
+	}
+	
+	// 常用的仓库优先于匹配更精确的仓库，但只按子序列匹配的仓库再常用也不考虑
+	for i := 0; i < 3; i++ {
+		client.Visit("/projj/github.com/team/api-gateway")
+	}
+	for i := 0; i < 10; i++ {
+		client.Visit("/projj/github.com/team/awesome-pipeline-infra")
+	}
+	repo, err := client.Jump("api")
+	if err != nil {
+		t.Fatalf("Jump() failed: %v", err)
+	}
+	if repo.Name != "api-gateway" {
+		t.Errorf("Expected most visited api-gateway, got %s", repo.Name)
+	}
+	
+	client.Visit("/projj/github.com/team/web")
+	recent, err := client.Recent(0, "")
+	if err != nil {
+		t.Fatalf("Recent() failed: %v", err)
+	}
+	if len(recent) != 3 || recent[1].Name != "api-gateway" || recent[1].Visits != 4 {
+		t.Errorf("Unexpected recent repositories: %+v", recent)
+	}
+	
+	if recent, _ := client.Recent(1, ""); len(recent) != 1 {
+		t.Errorf("Expected limit to apply, got %d", len(recent))
+	}
+}
+
+func TestVisitNodeFormat(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	cfg := config.DefaultConfig()
+	cfg.CacheFormat = config.CacheFormatNode
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	client.cache.Put(cache.Repository{Name: "api", Path: "/projj/github.com/team/api", URL: "git@github.com:team/api.git"})
+	before, err := os.ReadFile(cache.GetCachePath())
+	if err != nil {
+		t.Fatalf("Failed to read cache: %v", err)
+	}
+	
+	// projj-node 格式不保存访问记录，不必重写缓存文件
+	client.Visit("/projj/github.com/team/api")
+	if after, _ := os.ReadFile(cache.GetCachePath()); string(after) != string(before) {
+		t.Errorf("Visit() should not rewrite a projj-node cache, got %s", after)
+	}
+}
+
+func TestTagsAndDescription(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	
+	client.cache.Put(
+		cache.Repository{Name: "api", Path: "/projj/github.com/team/api", URL: "git@github.com:team/api.git"},
+		cache.Repository{Name: "api-gateway", Path: "/projj/github.com/team/api-gateway", URL: "git@github.com:team/api-gateway.git"},
+		cache.Repository{Name: "web", Path: "/projj/github.com/team/web", URL: "git@github.com:team/web.git"},
+	)
+	
+	repo, added, err := client.AddTags("api-gateway", "Payments", "infra")
+	if err != nil {
+		t.Fatalf("AddTags() failed: %v", err)
+	}
+	if len(added) != 2 || len(repo.Tags) != 2 || repo.Tags[0] != "infra" || repo.Tags[1] != "payments" {
+		t.Errorf("Unexpected tags after add: %v (added %v)", repo.Tags, added)
+	}
+	if _, _, err := client.AddTags("web", "bad tag"); err == nil {
+		t.Error("AddTags() should reject invalid tags")
+	}
+	if _, _, err := client.AddTags("api", "infra"); err == nil {
+		t.Error("AddTags() should fail when the query matches several repositories")
+	}
+	client.AddTags("web", "infra")
+	
+	if _, removed, err := client.RemoveTags("api-gateway", "infra"); err != nil || len(removed) != 1 {
+		t.Errorf("RemoveTags() returned %v (%v)", removed, err)
+	}
+	
+	if _, err := client.Describe("web", "  前端站点 "); err != nil {
+		t.Fatalf("Describe() failed: %v", err)
+	}
+	if repo, _ := client.Resolve("web"); repo == nil || repo.Description != "前端站点" {
+		t.Errorf("Expected description saved, got %+v", repo)
+	}
+	
+	counts, err := client.Tags()
+	if err != nil {
+		t.Fatalf("Tags() failed: %v", err)
+	}
+	if counts["infra"] != 1 || counts["payments"] != 1 {
+		t.Errorf("Unexpected tag counts: %v", counts)
+	}
+	
+	// 关键词与标签同时使用时仍按匹配程度排序
+	matches, err := client.Search("(api) tag:payments")
+	if err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Repo.Name != "api-gateway" || matches[0].Kind != cache.MatchPrefix {
+		t.Errorf("Expected ranked api-gateway, got %+v", matches)
+	}
+	
+	repos, err := client.Query("tag:infra OR desc:前端")
+	if err != nil || len(repos) != 1 || repos[0].Name != "web" {
+		t.Errorf("Expected web for tag query, got %v (%v)", repos, err)
+	}
+}
+
+func TestTagsNodeFormat(t *testing.T) {
+	for _, format := range []string{config.CacheFormatNode, config.CacheFormatBoth} {
+		t.Run(format, func(t *testing.T) {
+			_, cleanup := setupTestEnv(t)
+			defer cleanup()
+			
+			cfg := config.DefaultConfig()
+			cfg.CacheFormat = format
+			if err := cfg.Save(); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+			client, err := New()
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			client.cache.Put(cache.Repository{Name: "api", Path: "/projj/github.com/team/api", URL: "git@github.com:team/api.git"})
+			
+			_, _, tagErr := client.AddTags("api", "payments")
+			_, descErr := client.Describe("api", "支付服务")
+			
+			// 重新读取缓存文件，确认标签和描述确实保存
+			client, err = New()
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			repo, err := client.Resolve("api")
+			if err != nil {
+				t.Fatalf("Resolve() failed: %v", err)
+			}
+			
+			if format == config.CacheFormatNode {
+				// projj-node 只保存仓库地址，不能报告成功后丢失数据
+				if tagErr == nil || descErr == nil {
+					t.Errorf("Expected errors under projj-node, got %v and %v", tagErr, descErr)
+				}
+				return
+			}
+			if tagErr != nil || descErr != nil {
+				t.Fatalf("Expected tags and description saved, got %v and %v", tagErr, descErr)
+			}
+			if !repo.HasTag("payments") || repo.Description != "支付服务" {
+				t.Errorf("Expected tags and description after reload, got %+v", repo)
+			}
+		})
+	}
+}
+
+func TestReimportKeepsMetadata(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	tempDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	basePath := filepath.Join(tempDir, "base")
+	cfg := config.DefaultConfig()
+	cfg.Base = basePath
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	repoPath := filepath.Join(basePath, "github.com", "user", "repo")
+	initGitRepo(t, repoPath, "git@github.com:user/repo.git")
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err := client.Import(basePath, ImportOptions{}); err != nil {
+		t.Fatalf("Import() failed: %v", err)
+	}
+	if _, _, err := client.AddTags("repo", "work"); err != nil {
+		t.Fatalf("AddTags() failed: %v", err)
+	}
+	if _, err := client.Describe("repo", "示例仓库"); err != nil {
+		t.Fatalf("Describe() failed: %v", err)
+	}
+	client.Visit(repoPath)
+	before, _ := client.Resolve("repo")
+	
+	// 再次导入已登记的仓库不能丢失标签、描述和访问记录
+	if _, err := client.Import(basePath, ImportOptions{}); err != nil {
+		t.Fatalf("Import() failed: %v", err)
+	}
+	repo, err := client.Resolve("repo")
+	if err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+	if !repo.HasTag("work") || repo.Description != "示例仓库" || repo.Visits != 1 || repo.LastVisitedAt.IsZero() {
+		t.Errorf("Expected metadata kept after re-import, got %+v", repo)
+	}
+	if !repo.AddedAt.Equal(before.AddedAt) {
+		t.Errorf("Expected AddedAt kept, got %v, want %v", repo.AddedAt, before.AddedAt)
+	}
+}
+
+func TestResolveWithChooser(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	
+	client.cache.Put(
+		cache.Repository{Name: "api", Path: "/projj/github.com/team/api", URL: "git@github.com:team/api.git"},
+		cache.Repository{Name: "api-gateway", Path: "/projj/github.com/team/api-gateway", URL: "git@github.com:team/api-gateway.git"},
+		cache.Repository{Name: "web", Path: "/projj/github.com/team/web", URL: "git@github.com:team/web.git"},
+	)
+	
+	// 没有选择方式时匹配多个仓库报错
+	if _, err := client.Remove("github.com/team", false); err == nil {
+		t.Fatal("Remove() should fail for ambiguous query without chooser")
+	}
+	
+	var candidates int
+	client.SetChooser(func(repos []cache.Repository) (*cache.Repository, error) {
+		candidates = len(repos)
+		for i := range repos {
+			if repos[i].Name == "web" {
+				return &repos[i], nil
+			}
+		}
+		return nil, fmt.Errorf("web not offered")
+	})
+	if _, err := client.Remove("github.com/team", false); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+	if candidates != 3 {
+		t.Errorf("Expected 3 candidates, got %d", candidates)
+	}
+	if repo, _ := client.cache.GetByPath("/projj/github.com/team/web"); repo != nil {
+		t.Error("Expected chosen repository to be removed")
+	}
+	
+	// 唯一匹配时不调用选择方式
+	candidates = 0
+	if _, err := client.Resolve("api-gateway"); err != nil || candidates != 0 {
+		t.Errorf("Resolve() should not ask for a unique match: %v", err)
+	}
+}
+
+func TestNewRepoRecord(t *testing.T) {
+	added := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+	record := NewRepoRecord(cache.Repository{
+		Name:     "api",
+		Path:     "/projj/github.com/team/api",
+		URL:      "git@github.com:team/api.git",
+		Platform: "github.com",
+		AddedAt:  added,
+	})
+	
+	// 没有标签时为空数组，未访问时访问时间为空
+	if record.Tags == nil || len(record.Tags) != 0 {
+		t.Errorf("Expected empty tags, got %v", record.Tags)
+	}
+	if record.AddedAt != "2024-05-01T08:30:00Z" || record.LastVisitedAt != "" {
+		t.Errorf("Unexpected times: %q %q", record.AddedAt, record.LastVisitedAt)
+	}
+	
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	json.Unmarshal(data, &fields)
+	for _, column := range RepoColumns {
+		if _, ok := fields[column]; !ok {
+			t.Errorf("Expected column %s in record", column)
+		}
+	}
+}
+
+func TestStatus(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	tempDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	
+	clean := filepath.Join(tempDir, "base", "github.com", "team", "clean")
+	dirty := filepath.Join(tempDir, "base", "github.com", "team", "dirty")
+	initGitRepo(t, clean, "git@github.com:team/clean.git")
+	initGitRepo(t, dirty, "git@github.com:team/dirty.git")
+	os.WriteFile(filepath.Join(dirty, "new.txt"), []byte("x"), 0644)
+	client.cache.Put(
+		cache.Repository{Name: "clean", Path: clean, URL: "git@github.com:team/clean.git", Tags: []string{"work"}},
+		cache.Repository{Name: "dirty", Path: dirty, URL: "git@github.com:team/dirty.git", Tags: []string{"work"}},
+		cache.Repository{Name: "missing", Path: filepath.Join(tempDir, "missing"), URL: "git@github.com:team/missing.git"},
+	)
+	
+	results, err := client.Status(StatusOptions{Workers: 2})
+	if err != nil {
+		t.Fatalf("Status() failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	for _, result := range results {
+		switch result.Repo.Name {
+		case "clean":
+			if result.Err != nil || result.Status.Dirty() {
+				t.Errorf("Expected clean repo, got %+v (%v)", result.Status, result.Err)
+			}
+		case "dirty":
+			if result.Err != nil || result.Status.Untracked != 1 {
+				t.Errorf("Expected 1 untracked file, got %+v (%v)", result.Status, result.Err)
+			}
+		case "missing":
+			if result.Err == nil {
+				t.Error("Expected error for missing repository")
+			}
+		}
+	}
+	
+	// 读取失败的仓库总会保留
+	results, err = client.Status(StatusOptions{OnlyDirty: true})
+	if err != nil {
+		t.Fatalf("Status() failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Repo.Name == "clean" || results[1].Repo.Name == "clean" {
+		t.Errorf("Expected dirty and missing repos, got %+v", results)
+	}
+	
+	// 没有上游的分支视为有未推送的提交
+	results, err = client.Status(StatusOptions{Filter: "tag:work", OnlyUnpushed: true})
+	if err != nil {
+		t.Fatalf("Status() failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected 2 repos without upstream, got %d", len(results))
+	}
+	
+	record := NewStatusRecord(results[0])
+	if !record.Unpushed || record.Error != "" || record.Name == "" {
+		t.Errorf("Unexpected status record: %+v", record)
+	}
+}
+
+func TestPull(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	tempDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	
+	clean := filepath.Join(tempDir, "base", "github.com", "team", "clean")
+	dirty := filepath.Join(tempDir, "base", "github.com", "team", "dirty")
+	untracked := filepath.Join(tempDir, "base", "github.com", "team", "untracked")
+	initGitRepo(t, clean, "git@github.com:team/clean.git")
+	initGitRepo(t, dirty, "git@github.com:team/dirty.git")
+	os.WriteFile(filepath.Join(dirty, "new.txt"), []byte("x"), 0644)
+	run := func(dir string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+	run(dirty, "add", "new.txt")
+	
+	// 有上游、只有未跟踪文件的仓库仍会拉取
+	remote := filepath.Join(tempDir, "remotes", "untracked.git")
+	run(tempDir, "init", "-q", "--bare", remote)
+	run(tempDir, "init", "-q", "-b", "main", untracked)
+	run(untracked, "commit", "-q", "--allow-empty", "-m", "init")
+	run(untracked, "remote", "add", "origin", remote)
+	run(untracked, "push", "-q", "-u", "origin", "main")
+	os.WriteFile(filepath.Join(untracked, "notes.txt"), []byte("x"), 0644)
+	
+	client.cache.Put(
+		cache.Repository{Name: "clean", Path: clean, URL: "git@github.com:team/clean.git"},
+		cache.Repository{Name: "dirty", Path: dirty, URL: "git@github.com:team/dirty.git"},
+		cache.Repository{Name: "untracked", Path: untracked, URL: "git@github.com:team/untracked.git"},
+		cache.Repository{Name: "missing", Path: filepath.Join(tempDir, "missing"), URL: "git@github.com:team/missing.git"},
+	)
+	
+	// 已跟踪文件有修改和没有上游的仓库被跳过，目录不存在的仓库失败
+	results, err := client.Pull(context.Background(), UpdateOptions{FFOnly: true, Timeout: time.Minute})
+	if err != nil {
+		t.Fatalf("Pull() failed: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(results))
+	}
+	expected := map[string]UpdateState{"clean": UpdateSkipped, "dirty": UpdateSkipped, "untracked": UpdateUpToDate, "missing": UpdateFailed}
+	for _, result := range results {
+		if result.State != expected[result.Repo.Name] || (result.State != UpdateUpToDate && result.Reason == "") {
+			t.Errorf("Unexpected result for %s: %+v", result.Repo.Name, result)
+		}
+	}
+	
+	record := NewUpdateRecord(results[0])
+	if record.State == "" || record.Path == "" {
+		t.Errorf("Unexpected update record: %+v", record)
+	}
+}
+
+func TestExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell exec test on windows")
+	}
+	
+	tempDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	
+	api := filepath.Join(tempDir, "base", "github.com", "infra", "api")
+	web := filepath.Join(tempDir, "base", "gitlab.com", "team", "web")
+	os.MkdirAll(api, 0755)
+	os.MkdirAll(web, 0755)
+	client.cache.Put(
+		cache.Repository{Name: "api", Path: api, URL: "git@github.com:infra/api.git"},
+		cache.Repository{Name: "web", Path: web, URL: "git@gitlab.com:team/web.git"},
+		cache.Repository{Name: "missing", Path: filepath.Join(tempDir, "missing"), URL: "git@github.com:team/missing.git"},
+	)
+	
+	var stdout, stderr bytes.Buffer
+	results, err := client.Exec(context.Background(), []string{`echo "$PROJJ_REPO_OWNER $PROJJ_REPO_NAME $PROJJ_REPO_HOST $PROJJ_REPO_PATH"`}, ExecOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		t.Fatalf("Exec() failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	for _, result := range results {
+		if (result.Err != nil) != (result.Repo.Name == "missing") {
+			t.Errorf("Unexpected result for %s: %v", result.Repo.Name, result.Err)
+		}
+	}
+	out := stdout.String()
+	// 前缀按最长的 team/missing 对齐
+	for _, line := range []string{
+		"[infra/api]    infra api github.com " + api + "\n",
+		"[team/web]     team web gitlab.com " + web + "\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("Expected output to contain %q, got %q", line, out)
+		}
+	}
+	if !strings.Contains(stderr.String(), "仓库目录不存在") {
+		t.Errorf("Expected error for missing repository, got %q", stderr.String())
+	}
+	
+	// 分组输出，失败后不再执行其余仓库
+	stdout.Reset()
+	results, err = client.Exec(context.Background(), []string{"sh", "-c", "echo $PROJJ_REPO_NAME; exit 4"}, ExecOptions{
+		Filter:   "host:github.com",
+		Workers:  1,
+		Group:    true,
+		FailFast: true,
+		Stdout:   &stdout,
+		Stderr:   &stderr,
+	})
+	if err != nil {
+		t.Fatalf("Exec() failed: %v", err)
+	}
+	if len(results) != 2 || results[0].ExitCode != 4 || !results[1].Skipped {
+		t.Errorf("Expected first repo to fail with 4 and second to be skipped, got %+v", results)
+	}
+	if !strings.HasPrefix(stdout.String(), "==> ") || strings.Count(stdout.String(), "==> ") != 1 {
+		t.Errorf("Expected one grouped block, got %q", stdout.String())
+	}
+	
+	if _, err := client.Exec(context.Background(), nil, ExecOptions{}); err == nil {
+		t.Error("Exec() should fail without command")
+	}
+}
+
+func TestCampaign(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell campaign test on windows")
+	}
+	
+	tempDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	// 活动中的提交需要 git 身份
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_AUTHOR_EMAIL", "GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL"} {
+		original, ok := os.LookupEnv(key)
+		os.Setenv(key, "t@example.com")
+		if ok {
+			defer os.Setenv(key, original)
+		} else {
+			defer os.Unsetenv(key)
+		}
+	}
+	
+	run := func(dir string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	
+	var repos []cache.Repository
+	for _, name := range []string{"api", "web", "docs"} {
+		remote := filepath.Join(tempDir, "remotes", name+".git")
+		path := filepath.Join(tempDir, "base", "github.com", "infra", name)
+		run(tempDir, "init", "-q", "--bare", remote)
+		run(tempDir, "init", "-q", "-b", "main", path)
+		os.WriteFile(filepath.Join(path, "deps.txt"), []byte("lib v1\n"), 0644)
+		run(path, "add", ".")
+		run(path, "commit", "-q", "-m", "init")
+		run(path, "remote", "add", "origin", remote)
+		run(path, "push", "-q", "-u", "origin", "main")
+		repos = append(repos, cache.Repository{Name: name, Path: path, URL: "git@github.com:infra/" + name + ".git"})
+	}
+	client.cache.Put(repos...)
+	// web 有未提交的修改，docs 不需要修改
+	os.WriteFile(filepath.Join(repos[1].Path, "wip.txt"), []byte("x"), 0644)
+	
+	script := `[ "$PROJJ_REPO_NAME" = docs ] || echo "lib v2 ($PROJJ_CAMPAIGN_NAME)" > deps.txt`
+	camp, err := client.NewCampaign("bump-lib", "", script, "bump lib", "owner:infra")
+	if err != nil {
+		t.Fatalf("NewCampaign() failed: %v", err)
+	}
+	if camp.Branch != "bump-lib" || len(camp.Repos) != 3 {
+		t.Fatalf("Unexpected campaign: %+v", camp)
+	}
+	if _, err := client.NewCampaign("bump-lib", "", script, "bump lib", ""); err == nil {
+		t.Error("NewCampaign() should fail for existing campaign")
+	}
+	
+	var out bytes.Buffer
+	if err := client.RunCampaign(context.Background(), camp, CampaignOptions{Stdout: &out}); err != nil {
+		t.Fatalf("RunCampaign() failed: %v", err)
+	}
+	states := func(c *campaign.Campaign) map[string]campaign.Repo {
+		result := make(map[string]campaign.Repo)
+		for _, repo := range c.Repos {
+			result[repo.Name] = repo
+		}
+		return result
+	}
+	
+	// 状态已保存到配置目录
+	saved, err := campaign.Load("bump-lib")
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	got := states(saved)
+	if got["api"].State != campaign.Committed || got["api"].Commit == "" || got["api"].OriginalBranch != "main" {
+		t.Errorf("Expected api to be committed, got %+v", got["api"])
+	}
+	if got["web"].State != campaign.Pending || !got["web"].Failed() {
+		t.Errorf("Expected web to fail before branching, got %+v", got["web"])
+	}
+	if got["docs"].State != campaign.Unchanged {
+		t.Errorf("Expected docs to be unchanged, got %+v", got["docs"])
+	}
+	data, _ := os.ReadFile(filepath.Join(repos[0].Path, "deps.txt"))
+	if string(data) != "lib v2 (bump-lib)\n" {
+		t.Errorf("Unexpected script result: %q", data)
+	}
+	
+	// 处理后继续执行并推送
+	os.Remove(filepath.Join(repos[1].Path, "wip.txt"))
+	if err := client.RunCampaign(context.Background(), saved, CampaignOptions{Push: true, Stdout: &out}); err != nil {
+		t.Fatalf("RunCampaign() failed: %v", err)
+	}
+	got = states(saved)
+	for _, name := range []string{"api", "web"} {
+		if got[name].State != campaign.Pushed || !got[name].RemoteBranch || got[name].Failed() {
+			t.Errorf("Expected %s to be pushed, got %+v", name, got[name])
+		}
+	}
+	if !strings.Contains(out.String(), "==> web") {
+		t.Errorf("Expected output for web, got %q", out.String())
+	}
+	
+	// 回滚后切换回原来的分支并删除活动分支，远程分支保留
+	if err := client.RollbackCampaign(context.Background(), saved, RollbackOptions{}); err != nil {
+		t.Fatalf("RollbackCampaign() failed: %v", err)
+	}
+	for _, repo := range saved.Repos {
+		branch, _ := git.CurrentBranch(repo.Path)
+		if repo.State != campaign.RolledBack || branch != "main" || git.BranchExists(repo.Path, "bump-lib") {
+			t.Errorf("Expected %s to be rolled back, got %+v on %s", repo.Name, repo, branch)
+		}
+	}
+	if !states(saved)["api"].RemoteBranch {
+		t.Error("Expected remote branch to be kept without Remote option")
+	}
+	
+	if err := client.RollbackCampaign(context.Background(), saved, RollbackOptions{Remote: true}); err != nil {
+		t.Fatalf("RollbackCampaign() failed: %v", err)
+	}
+	for _, repo := range saved.Repos {
+		if repo.RemoteBranch || repo.Failed() {
+			t.Errorf("Expected remote branch of %s to be deleted, got %+v", repo.Name, repo)
+		}
+	}
+	
+	// 已存在的同名分支不是活动创建的，不在其上执行，回滚时也不删除
+	run(repos[0].Path, "branch", "resume")
+	script = `grep -q branched "$PROJJ_CONFIG_DIR/campaigns/resume.json" && echo saved > deps.txt`
+	resumed, err := client.NewCampaign("resume", "", script, "resume", "api")
+	if err != nil || len(resumed.Repos) != 1 {
+		t.Fatalf("NewCampaign() returned %+v (%v)", resumed, err)
+	}
+	if err := client.RunCampaign(context.Background(), resumed, CampaignOptions{Stdout: &out}); err != nil {
+		t.Fatalf("RunCampaign() failed: %v", err)
+	}
+	if repo := resumed.Repos[0]; repo.State != campaign.Pending || !repo.Failed() || repo.CreatedBranch {
+		t.Errorf("Expected existing branch to be refused, got %+v", repo)
+	}
+	if err := client.RollbackCampaign(context.Background(), resumed, RollbackOptions{}); err != nil {
+		t.Fatalf("RollbackCampaign() failed: %v", err)
+	}
+	if !git.BranchExists(repos[0].Path, "resume") {
+		t.Error("Rollback should not delete a branch the campaign did not create")
+	}
+	campaign.Delete("resume")
+	
+	// 创建分支后、保存状态前中断时，由活动创建的分支可以继续；执行脚本前已保存状态
+	resumed, err = client.NewCampaign("resume", "", script, "resume", "api")
+	if err != nil {
+		t.Fatalf("NewCampaign() failed: %v", err)
+	}
+	resumed.Repos[0].OriginalBranch, resumed.Repos[0].CreatedBranch = "main", true
+	if err := client.RunCampaign(context.Background(), resumed, CampaignOptions{Stdout: &out}); err != nil {
+		t.Fatalf("RunCampaign() failed: %v", err)
+	}
+	if repo := resumed.Repos[0]; repo.State != campaign.Committed || repo.OriginalBranch != "main" {
+		t.Errorf("Expected created branch to be resumed, got %+v", repo)
+	}
+	
+	// 不在活动分支上时即使 Force 也不丢弃修改
+	run(repos[0].Path, "checkout", "-q", "main")
+	os.WriteFile(filepath.Join(repos[0].Path, "deps.txt"), []byte("local\n"), 0644)
+	if err := client.RollbackCampaign(context.Background(), resumed, RollbackOptions{Force: true}); err != nil {
+		t.Fatalf("RollbackCampaign() failed: %v", err)
+	}
+	if repo := resumed.Repos[0]; repo.State != campaign.Committed || !repo.Failed() {
+		t.Errorf("Expected rollback to fail off the campaign branch, got %+v", repo)
+	}
+	if data, _ := os.ReadFile(filepath.Join(repos[0].Path, "deps.txt")); string(data) != "local\n" {
+		t.Errorf("Changes on other branches should be kept, got %q", data)
+	}
+}
+
+func TestGrep(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git command not available")
+	}
+	
+	tempDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	
+	client, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	
+	api := filepath.Join(tempDir, "base", "github.com", "infra", "api")
+	plain := filepath.Join(tempDir, "base", "github.com", "infra", "plain")
+	other := filepath.Join(tempDir, "base", "github.com", "team", "other")
+	initGitRepo(t, api, "git@github.com:infra/api.git")
+	os.MkdirAll(plain, 0755)
+	os.MkdirAll(other, 0755)
+	os.WriteFile(filepath.Join(api, "main.go"), []byte("package main\n\nimport \"example.com/lib\"\n"), 0644)
+	os.WriteFile(filepath.Join(api, "notes.txt"), []byte("example.com/lib untracked\n"), 0644)
+	os.WriteFile(filepath.Join(plain, "go.mod"), []byte("require example.com/lib v1.0.0\n"), 0644)
+	os.WriteFile(filepath.Join(other, "go.mod"), []byte("module other\n"), 0644)
+	cmd := exec.Command("git", "add", "main.go")
+	cmd.Dir = api
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to run git add: %v", err)
+	}
+	client.cache.Put(
+		cache.Repository{Name: "api", Path: api, URL: "git@github.com:infra/api.git"},
+		cache.Repository{Name: "plain", Path: plain, URL: "git@github.com:infra/plain.git"},
+		cache.Repository{Name: "other", Path: other, URL: "git@github.com:team/other.git"},
+		cache.Repository{Name: "missing", Path: filepath.Join(tempDir, "missing"), URL: "git@github.com:infra/missing.git"},
+	)
+	
+	// git 仓库只搜索已跟踪的文件，没有匹配的仓库不返回
+	results, err := client.Grep(context.Background(), `example\.com/lib`, GrepOptions{Workers: 2})
+	if err != nil {
+		t.Fatalf("Grep() failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %+v", results)
+	}
+	for _, result := range results {
+		switch result.Repo.Name {
+		case "api":
+			if result.Err != nil || len(result.Matches) != 1 || result.Matches[0].Path != "main.go" || result.Matches[0].Line != 3 {
+				t.Errorf("Unexpected matches in api: %+v (%v)", result.Matches, result.Err)
+			}
+		case "plain":
+			if result.Err != nil || len(result.Matches) != 1 || result.Matches[0].Path != "go.mod" {
+				t.Errorf("Unexpected matches in plain: %+v (%v)", result.Matches, result.Err)
+			}
+		case "missing":
+			if result.Err == nil {
+				t.Error("Expected error for missing repository")
+			}
+		default:
+			t.Errorf("Unexpected result for %s", result.Repo.Name)
+		}
+	}
+	
+	results, err = client.Grep(context.Background(), "EXAMPLE.COM/LIB", GrepOptions{
+		GrepOptions: git.GrepOptions{IgnoreCase: true, Fixed: true, Untracked: true},
+		Filter:      "name:api",
+	})
+	if err != nil {
+		t.Fatalf("Grep() failed: %v", err)
+	}
+	if len(results) != 1 || len(results[0].Matches) != 2 {
+		t.Fatalf("Expected 2 matches including untracked file, got %+v", results)
+	}
+	records := NewGrepRecords(results[0])
+	if records[0].Repo != "api" || records[0].Path != filepath.Join(api, "main.go") || records[0].File != "main.go" {
+		t.Errorf("Unexpected grep record: %+v", records[0])
+	}
+	
+	// 只搜索 git 仓库时模式为扩展正则表达式，不按 Go 正则表达式提前校验
+	results, err = client.Grep(context.Background(), `(a)ck\1`, GrepOptions{Filter: "name:api"})
+	if err != nil || len(results) != 1 || len(results[0].Matches) != 1 || results[0].Matches[0].Line != 1 {
+		t.Errorf("Expected back reference to be passed to git grep, got %+v (%v)", results, err)
+	}
+	
+	if _, err := client.Grep(context.Background(), "lib(", GrepOptions{}); err == nil {
+		t.Error("Grep() should fail with invalid pattern")
+	}
 }
\ No newline at end of file
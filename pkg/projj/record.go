@@ -0,0 +1,145 @@
+package projj
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/atian25/projj-go/internal/cache"
+)
+
+// RepoRecord 表示机器可读输出中的仓库，字段名和含义保持稳定
+type RepoRecord struct {
+	Name          string   `json:"name"`
+	Path          string   `json:"path"`
+	URL           string   `json:"url"`
+	Platform      string   `json:"platform"`
+	Tags          []string `json:"tags"`
+	Description   string   `json:"description"`
+	AddedAt       string   `json:"added_at"` // RFC 3339 格式，未知时为空
+	Visits        int      `json:"visits"`
+	LastVisitedAt string   `json:"last_visited_at"` // RFC 3339 格式，未访问过时为空
+}
+
+// RepoColumns 仓库记录在 tsv 输出中的列
+var RepoColumns = []string{"name", "path", "url", "platform", "tags", "description", "added_at", "visits", "last_visited_at"}
+
+// NewRepoRecord 将仓库转换为输出记录
+func NewRepoRecord(repo cache.Repository) RepoRecord {
+	tags := repo.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return RepoRecord{
+		Name:          repo.Name,
+		Path:          repo.Path,
+		URL:           repo.URL,
+		Platform:      repo.Platform,
+		Tags:          tags,
+		Description:   repo.Description,
+		AddedAt:       formatTime(repo.AddedAt),
+		Visits:        repo.Visits,
+		LastVisitedAt: formatTime(repo.LastVisitedAt),
+	}
+}
+
+// NewRepoRecords 将仓库列表转换为输出记录
+func NewRepoRecords(repos []cache.Repository) []RepoRecord {
+	records := make([]RepoRecord, len(repos))
+	for i, repo := range repos {
+		records[i] = NewRepoRecord(repo)
+	}
+	return records
+}
+
+// formatTime 以 RFC 3339 格式输出时间，零值为空
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// StatusRecord 表示机器可读输出中仓库的 git 状态，读取失败时 error 不为空，其余状态字段为零值
+type StatusRecord struct {
+	RepoRecord
+	Branch    string `json:"branch"`
+	Detached  bool   `json:"detached"`
+	Upstream  string `json:"upstream"`
+	Ahead     int    `json:"ahead"`
+	Behind    int    `json:"behind"`
+	Changed   int    `json:"changed"`
+	Untracked int    `json:"untracked"`
+	Conflicts int    `json:"conflicts"`
+	Stashes   int    `json:"stashes"`
+	Dirty     bool   `json:"dirty"`
+	Unpushed  bool   `json:"unpushed"`
+	Error     string `json:"error"`
+}
+
+// StatusColumns 状态记录在 tsv 输出中的列
+var StatusColumns = []string{"name", "path", "branch", "detached", "upstream", "ahead", "behind", "changed", "untracked", "conflicts", "stashes", "dirty", "unpushed", "error"}
+
+// NewStatusRecord 将仓库状态转换为输出记录
+func NewStatusRecord(result RepoStatus) StatusRecord {
+	record := StatusRecord{RepoRecord: NewRepoRecord(result.Repo)}
+	if result.Err != nil {
+		record.Error = result.Err.Error()
+		return record
+	}
+	s := result.Status
+	record.Branch, record.Detached, record.Upstream = s.Branch, s.Detached, s.Upstream
+	record.Ahead, record.Behind = s.Ahead, s.Behind
+	record.Changed, record.Untracked, record.Conflicts, record.Stashes = s.Changed, s.Untracked, s.Conflicts, s.Stashes
+	record.Dirty, record.Unpushed = s.Dirty(), s.Unpushed()
+	return record
+}
+
+// UpdateRecord 表示机器可读输出中 fetch 或 pull 的结果，state 为 updated、up-to-date、skipped 或 failed
+type UpdateRecord struct {
+	RepoRecord
+	State  UpdateState `json:"state"`
+	Reason string      `json:"reason"`
+	Output string      `json:"output"`
+}
+
+// UpdateColumns 更新记录在 tsv 输出中的列
+var UpdateColumns = []string{"name", "path", "state", "reason"}
+
+// NewUpdateRecord 将 fetch 或 pull 的结果转换为输出记录
+func NewUpdateRecord(result UpdateResult) UpdateRecord {
+	return UpdateRecord{
+		RepoRecord: NewRepoRecord(result.Repo),
+		State:      result.State,
+		Reason:     result.Reason,
+		Output:     result.Output,
+	}
+}
+
+// GrepRecord 表示机器可读输出中的一处匹配，path 为匹配文件的绝对路径，file 为相对于仓库根目录的路径
+type GrepRecord struct {
+	Repo     string `json:"repo"`
+	RepoPath string `json:"repo_path"`
+	File     string `json:"file"`
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	Text     string `json:"text"`
+}
+
+// GrepColumns 匹配记录在 tsv 输出中的列
+var GrepColumns = []string{"repo", "file", "line", "text"}
+
+// NewGrepRecords 将仓库的搜索结果转换为输出记录
+func NewGrepRecords(result GrepResult) []GrepRecord {
+	records := make([]GrepRecord, len(result.Matches))
+	for i, match := range result.Matches {
+		records[i] = GrepRecord{
+			Repo:     result.Repo.Name,
+			RepoPath: result.Repo.Path,
+			File:     match.Path,
+			Path:     filepath.Join(result.Repo.Path, filepath.FromSlash(match.Path)),
+			Line:     match.Line,
+			Text:     match.Text,
+		}
+	}
+	return records
+}
\ No newline at end of file
@@ -0,0 +1,73 @@
+package projj
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/atian25/projj-go/internal/cache"
+)
+
+// Resolve 根据查询确定唯一的仓库，查询为 "." 时使用当前目录所属的仓库；子序列模糊匹配容易误中，不予考虑
+func (c *Client) Resolve(query string) (*cache.Repository, error) {
+	if query == "." {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("获取当前目录失败: %w", err)
+		}
+		return c.repoForDir(cwd)
+	}
+	
+	matches, err := c.Search(query)
+	if err != nil {
+		return nil, fmt.Errorf("查找仓库失败: %w", err)
+	}
+	var repos []cache.Repository
+	for _, m := range matches {
+		if m.Kind != cache.MatchFuzzy {
+			repos = append(repos, m.Repo)
+		}
+	}
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("未找到匹配的仓库: %s", query)
+	}
+	
+	if len(repos) > 1 && c.chooser != nil {
+		return c.chooser(repos)
+	}
+	
+	if len(repos) > 1 {
+		fmt.Fprintf(c.out, "找到多个匹配的仓库:\n")
+		for i, repo := range repos {
+			fmt.Fprintf(c.out, "%d. %s (%s)\n", i+1, repo.Name, repo.Path)
+		}
+		return nil, fmt.Errorf("请提供更具体的查询条件")
+	}
+	
+	return &repos[0], nil
+}
+
+// repoForDir 返回包含指定目录的仓库
+func (c *Client) repoForDir(dir string) (*cache.Repository, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("解析目录失败: %w", err)
+	}
+	
+	// 缓存中的路径可能是未解析符号链接的形式，两种都尝试
+	repo, err := c.cache.GetByDir(absDir)
+	if err != nil {
+		return nil, fmt.Errorf("查询缓存失败: %w", err)
+	}
+	if repo == nil {
+		if resolved, err := filepath.EvalSymlinks(absDir); err == nil {
+			if repo, err = c.cache.GetByDir(resolved); err != nil {
+				return nil, fmt.Errorf("查询缓存失败: %w", err)
+			}
+		}
+	}
+	if repo == nil {
+		return nil, fmt.Errorf("当前目录不在 projj 管理的仓库中: %s", absDir)
+	}
+	return repo, nil
+}
\ No newline at end of file
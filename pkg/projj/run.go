@@ -0,0 +1,77 @@
+package projj
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/hook"
+)
+
+// RunFailure 表示批量执行 hook 时失败的仓库
+type RunFailure struct {
+	Repo cache.Repository
+	Err  error
+}
+
+// RunAllResult 表示批量执行 hook 的结果
+type RunAllResult struct {
+	Succeeded []cache.Repository
+	Failed    []RunFailure
+}
+
+// Run 在包含指定目录的仓库中执行自定义 hook
+func (c *Client) Run(name, dir string) error {
+	if _, ok := hook.Lookup(c.config.Hooks, name); !ok {
+		return fmt.Errorf("未配置 hook: %s", name)
+	}
+	
+	repo, err := c.repoForDir(dir)
+	if err != nil {
+		return err
+	}
+	
+	return c.runHook(name, repo.Path, repo.URL, repo.Path)
+}
+
+// RunAll 在满足查询的仓库中执行自定义 hook，查询为空时为所有仓库，单个仓库失败不会中断执行
+func (c *Client) RunAll(name, filter string) (*RunAllResult, error) {
+	if _, ok := hook.Lookup(c.config.Hooks, name); !ok {
+		return nil, fmt.Errorf("未配置 hook: %s", name)
+	}
+	
+	repos, err := c.Query(filter)
+	if err != nil {
+		return nil, fmt.Errorf("查询仓库失败: %w", err)
+	}
+	
+	result := &RunAllResult{}
+	for _, repo := range repos {
+		fmt.Fprintf(c.out, "\n==> %s (%s)\n", repo.Name, repo.Path)
+		
+		if _, err := os.Stat(repo.Path); err != nil {
+			err = fmt.Errorf("仓库目录不存在: %s", repo.Path)
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			result.Failed = append(result.Failed, RunFailure{Repo: repo, Err: err})
+			continue
+		}
+		
+		if err := c.runHook(name, repo.Path, repo.URL, repo.Path); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			result.Failed = append(result.Failed, RunFailure{Repo: repo, Err: err})
+			continue
+		}
+		result.Succeeded = append(result.Succeeded, repo)
+	}
+	
+	fmt.Fprintf(c.out, "\n执行完成: 成功 %d 个，失败 %d 个仓库\n", len(result.Succeeded), len(result.Failed))
+	for _, failure := range result.Failed {
+		fmt.Fprintf(c.out, "  失败: %s (%s): %v\n", failure.Repo.Name, failure.Repo.Path, failure.Err)
+	}
+	
+	if len(result.Failed) > 0 {
+		return result, fmt.Errorf("%d 个仓库执行 hook %s 失败", len(result.Failed), name)
+	}
+	
+	return result, nil
+}
\ No newline at end of file
@@ -0,0 +1,54 @@
+package projj
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/atian25/projj-go/internal/scan"
+)
+
+// scanRepos 使用配置中的并发数和忽略列表扫描目录下的仓库
+func (c *Client) scanRepos(root string, workers int, nested bool) ([]scan.Repo, error) {
+	if workers <= 0 {
+		workers = c.config.ScanWorkers
+	}
+	
+	opts := scan.Options{
+		Workers: workers,
+		Ignore:  c.config.ScanIgnore,
+		Nested:  nested,
+		Warn: func(path string, err error) {
+			fmt.Fprintf(os.Stderr, "警告: 无法访问 %s: %v\n", path, err)
+		},
+	}
+	
+	// 只在终端中显示进度，避免污染重定向的输出
+	interactive := isTerminal(os.Stderr)
+	if interactive {
+		var last time.Time
+		opts.Progress = func(p scan.Progress) {
+			if time.Since(last) < 100*time.Millisecond {
+				return
+			}
+			last = time.Now()
+			fmt.Fprintf(os.Stderr, "\r扫描中: %d 个目录，发现 %d 个仓库", p.Dirs, p.Repos)
+		}
+	}
+	
+	repos, err := scan.Scan(root, opts)
+	if interactive {
+		// 清除进度行
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+	return repos, err
+}
+
+// isTerminal 检查文件是否为终端
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
\ No newline at end of file
@@ -0,0 +1,101 @@
+package projj
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/git"
+)
+
+// StatusOptions 表示读取仓库状态的选项
+type StatusOptions struct {
+	Filter       string // 查询条件，为空时为所有仓库
+	Workers      int    // 并发数，为 0 时使用配置中的 scan_workers 或 CPU 数
+	OnlyDirty    bool   // 只保留有未提交修改的仓库
+	OnlyUnpushed bool   // 只保留有未推送提交的仓库
+}
+
+// RepoStatus 表示一个仓库的 git 状态，读取失败时 Err 不为空
+type RepoStatus struct {
+	Repo   cache.Repository
+	Status *git.Status
+	Err    error
+}
+
+// Status 并发读取满足查询的仓库状态，结果保持仓库列表的顺序；读取失败的仓库总会保留
+func (c *Client) Status(opts StatusOptions) ([]RepoStatus, error) {
+	repos, err := c.Query(opts.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("查询仓库失败: %w", err)
+	}
+	
+	results := parallel(repos, c.workers(opts.Workers), inspectRepo, nil)
+	
+	filtered := results[:0]
+	for _, result := range results {
+		if result.Err == nil {
+			if opts.OnlyDirty && !result.Status.Dirty() {
+				continue
+			}
+			if opts.OnlyUnpushed && !result.Status.Unpushed() {
+				continue
+			}
+		}
+		filtered = append(filtered, result)
+	}
+	return filtered, nil
+}
+
+// workers 返回并发数，未指定时使用配置中的 scan_workers 或 CPU 数
+func (c *Client) workers(n int) int {
+	if n <= 0 {
+		n = c.config.ScanWorkers
+	}
+	if n <= 0 {
+		n = runtime.NumCPU()
+	}
+	return n
+}
+
+// parallel 使用 workers 个协程对每个仓库执行 fn，结果保持仓库列表的顺序；
+// done 在每个仓库完成后串行调用，参数为已完成的数量和该仓库的结果，可为空
+func parallel[T any](repos []cache.Repository, workers int, fn func(cache.Repository) T, done func(int, T)) []T {
+	results := make([]T, len(repos))
+	jobs := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	finished := 0
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				results[index] = fn(repos[index])
+				if done != nil {
+					mu.Lock()
+					finished++
+					done(finished, results[index])
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range repos {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// inspectRepo 读取单个仓库的状态
+func inspectRepo(repo cache.Repository) RepoStatus {
+	if _, err := os.Stat(repo.Path); err != nil {
+		return RepoStatus{Repo: repo, Err: fmt.Errorf("仓库目录不存在: %s", repo.Path)}
+	}
+	status, err := git.Inspect(repo.Path)
+	return RepoStatus{Repo: repo, Status: status, Err: err}
+}
\ No newline at end of file
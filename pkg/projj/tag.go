@@ -0,0 +1,101 @@
+package projj
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/config"
+)
+
+// AddTags 为查询确定的仓库添加标签，返回更新后的仓库和实际新增的标签
+func (c *Client) AddTags(query string, tags ...string) (*cache.Repository, []string, error) {
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	var added []string
+	repo, err := c.modify(query, func(repo *cache.Repository) error {
+		added = repo.AddTags(normalized...)
+		return nil
+	})
+	return repo, added, err
+}
+
+// RemoveTags 移除查询确定的仓库的标签，返回更新后的仓库和实际移除的标签
+func (c *Client) RemoveTags(query string, tags ...string) (*cache.Repository, []string, error) {
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	var removed []string
+	repo, err := c.modify(query, func(repo *cache.Repository) error {
+		removed = repo.RemoveTags(normalized...)
+		return nil
+	})
+	return repo, removed, err
+}
+
+// Describe 设置查询确定的仓库的描述，为空时清除
+func (c *Client) Describe(query, description string) (*cache.Repository, error) {
+	description = strings.TrimSpace(description)
+	return c.modify(query, func(repo *cache.Repository) error {
+		repo.Description = description
+		return nil
+	})
+}
+
+// Tags 统计所有标签关联的仓库数量
+func (c *Client) Tags() (map[string]int, error) {
+	repos, err := c.cache.List()
+	if err != nil {
+		return nil, fmt.Errorf("查询缓存失败: %w", err)
+	}
+	return cache.CountTags(repos), nil
+}
+
+// modify 在缓存中修改查询确定的仓库，返回修改后的仓库；缓存无法保存标签和描述时返回错误
+func (c *Client) modify(query string, fn func(repo *cache.Repository) error) (*cache.Repository, error) {
+	if !c.config.CacheKeepsMetadata() {
+		return nil, fmt.Errorf("cache_format 为 %s 时无法保存标签和描述，请改用 %s", config.CacheFormatNode, config.CacheFormatBoth)
+	}
+	
+	repo, err := c.Resolve(query)
+	if err != nil {
+		return nil, err
+	}
+	
+	var updated cache.Repository
+	found, err := c.cache.Modify(repo.Path, func(r *cache.Repository) error {
+		if err := fn(r); err != nil {
+			return err
+		}
+		updated = *r
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("保存缓存失败: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("仓库已从缓存中移除: %s", repo.Path)
+	}
+	return &updated, nil
+}
+
+// normalizeTags 规范化标签列表
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("请提供至少一个标签")
+	}
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		t, err := cache.NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, t)
+	}
+	return normalized, nil
+}
\ No newline at end of file
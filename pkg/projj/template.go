@@ -0,0 +1,13 @@
+package projj
+
+import (
+	"github.com/atian25/projj-go/internal/output"
+)
+
+// Template 解析输出模板，text 与配置中的模板预设同名时使用预设
+func (c *Client) Template(text string) (*output.Template, error) {
+	if preset, ok := c.config.Templates[text]; ok {
+		text = preset
+	}
+	return output.ParseTemplate(text, c.config.GetBasePath())
+}
\ No newline at end of file
@@ -0,0 +1,65 @@
+package projj
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/dashboard"
+	"github.com/atian25/projj-go/internal/git"
+)
+
+// UI 显示全屏的仓库面板
+func (c *Client) UI() error {
+	return dashboard.Run(dashboardBackend{c})
+}
+
+// dashboardBackend 为仓库面板提供数据和操作
+type dashboardBackend struct {
+	c *Client
+}
+
+func (b dashboardBackend) List() ([]cache.Repository, error) {
+	return b.c.List()
+}
+
+func (b dashboardBackend) Status(path string) dashboard.Status {
+	if _, err := os.Stat(path); err != nil {
+		return dashboard.Status{Err: fmt.Errorf("仓库目录不存在: %s", path)}
+	}
+	clean, err := git.GetStatus(path)
+	if err != nil {
+		return dashboard.Status{Err: err}
+	}
+	branch, _ := git.CurrentBranch(path)
+	return dashboard.Status{Branch: branch, Dirty: !clean}
+}
+
+func (b dashboardBackend) Pull(repo cache.Repository) error {
+	return git.Pull(repo.Path)
+}
+
+func (b dashboardBackend) Remove(repo cache.Repository) error {
+	removed, err := b.c.cache.Remove(repo.Path)
+	if err != nil {
+		return fmt.Errorf("保存缓存失败: %w", err)
+	}
+	if !removed {
+		return fmt.Errorf("从缓存中移除仓库失败: %s", repo.Path)
+	}
+	return nil
+}
+
+func (b dashboardBackend) RunHook(name string, repo cache.Repository) error {
+	return b.c.runHook(name, repo.Path, repo.URL, repo.Path)
+}
+
+func (b dashboardBackend) Hooks() []string {
+	names := make([]string, 0, len(b.c.config.Hooks))
+	for name := range b.c.config.Hooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
\ No newline at end of file
@@ -0,0 +1,119 @@
+package projj
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/atian25/projj-go/internal/cache"
+	"github.com/atian25/projj-go/internal/git"
+)
+
+// UpdateState 表示单个仓库 fetch 或 pull 的结果状态
+type UpdateState string
+
+const (
+	UpdateUpdated  UpdateState = "updated"    // 获取到了新的提交
+	UpdateUpToDate UpdateState = "up-to-date" // 已是最新
+	UpdateSkipped  UpdateState = "skipped"    // 未执行，如有未提交的修改
+	UpdateFailed   UpdateState = "failed"     // 执行失败或超时
+)
+
+// UpdateOptions 表示批量 fetch 或 pull 的选项
+type UpdateOptions struct {
+	Filter  string        // 查询条件，为空时为所有仓库
+	Workers int           // 并发数，为 0 时使用配置中的 scan_workers 或 CPU 数
+	Timeout time.Duration // 单个仓库的超时时间，为 0 时不限制
+	FFOnly  bool          // 只允许快进合并，只对 pull 有效
+}
+
+// UpdateResult 表示单个仓库 fetch 或 pull 的结果
+type UpdateResult struct {
+	Repo   cache.Repository
+	State  UpdateState
+	Reason string // 跳过或失败的原因
+	Output string // git 的输出
+}
+
+// Fetch 并发地在满足查询的仓库中执行 git fetch，结果保持仓库列表的顺序
+func (c *Client) Fetch(ctx context.Context, opts UpdateOptions) ([]UpdateResult, error) {
+	return c.update(opts, "获取", func(repo cache.Repository) UpdateResult {
+		if _, err := os.Stat(repo.Path); err != nil {
+			return UpdateResult{Repo: repo, State: UpdateFailed, Reason: fmt.Sprintf("仓库目录不存在: %s", repo.Path)}
+		}
+		
+		ctx, cancel := withTimeout(ctx, opts.Timeout)
+		defer cancel()
+		update, err := git.Fetch(ctx, repo.Path)
+		return updateResult(repo, update, err)
+	})
+}
+
+// Pull 并发地在满足查询的仓库中执行 git pull，结果保持仓库列表的顺序；
+// 已跟踪的文件有未提交修改、分离 HEAD 或没有上游分支的仓库会被跳过；
+// 只有未跟踪的文件不影响快进，与未跟踪文件冲突时由 git pull 报告失败
+func (c *Client) Pull(ctx context.Context, opts UpdateOptions) ([]UpdateResult, error) {
+	return c.update(opts, "拉取", func(repo cache.Repository) UpdateResult {
+		status := inspectRepo(repo)
+		switch {
+		case status.Err != nil:
+			return UpdateResult{Repo: repo, State: UpdateFailed, Reason: status.Err.Error()}
+		case status.Status.TrackedChanges():
+			return UpdateResult{Repo: repo, State: UpdateSkipped, Reason: "有未提交的修改"}
+		case status.Status.Detached:
+			return UpdateResult{Repo: repo, State: UpdateSkipped, Reason: "处于分离 HEAD 状态"}
+		case status.Status.NoUpstream():
+			return UpdateResult{Repo: repo, State: UpdateSkipped, Reason: "当前分支没有上游"}
+		}
+		
+		ctx, cancel := withTimeout(ctx, opts.Timeout)
+		defer cancel()
+		update, err := git.PullContext(ctx, repo.Path, opts.FFOnly)
+		return updateResult(repo, update, err)
+	})
+}
+
+// update 查询仓库并并发执行 fn，verb 为进度信息中的动作名称
+func (c *Client) update(opts UpdateOptions, verb string, fn func(cache.Repository) UpdateResult) ([]UpdateResult, error) {
+	repos, err := c.Query(opts.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("查询仓库失败: %w", err)
+	}
+	
+	// 只在终端中显示进度，避免污染重定向的输出
+	var progress func(int, UpdateResult)
+	interactive := isTerminal(os.Stderr)
+	if interactive {
+		progress = func(done int, result UpdateResult) {
+			fmt.Fprintf(os.Stderr, "\r\033[K%s中: %d/%d %s", verb, done, len(repos), result.Repo.Name)
+		}
+	}
+	
+	results := parallel(repos, c.workers(opts.Workers), fn, progress)
+	if interactive {
+		// 清除进度行
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+	return results, nil
+}
+
+// withTimeout 为单个仓库设置超时，timeout 为 0 时不限制
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// updateResult 将 git 的执行结果转换为仓库的结果
+func updateResult(repo cache.Repository, update *git.Update, err error) UpdateResult {
+	result := UpdateResult{Repo: repo, State: UpdateUpToDate, Output: update.Output}
+	switch {
+	case err != nil:
+		result.State, result.Reason = UpdateFailed, err.Error()
+	case update.Updated:
+		result.State = UpdateUpdated
+	}
+	return result
+}
\ No newline at end of file
@@ -22,8 +22,8 @@ function projj
     # Print the output
     echo $output
     
-    # Check if this was an 'add' or 'find' command and if change_directory is enabled
-    if test $exit_code -eq 0 -a \( "$argv[1]" = "add" -o "$argv[1]" = "find" \)
+    # Check if this was an 'add', 'find' or 'jump' command and if change_directory is enabled
+    if test $exit_code -eq 0 -a \( "$argv[1]" = "add" -o "$argv[1]" = "find" -o "$argv[1]" = "jump" -o "$argv[1]" = "j" \)
         # Look for the special PROJJ_CHANGE_DIRECTORY line in output
         set change_dir (echo $output | grep "^PROJJ_CHANGE_DIRECTORY=" | cut -d'=' -f2-)
         